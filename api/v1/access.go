@@ -0,0 +1,156 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// builtinAdminRoles are the built-in mongodb roles which reach beyond a single database,
+// they are never granted by a MongoDBUser or inherited by a MongoDBRole
+var builtinAdminRoles = []string{
+	"root",
+	"__system",
+	"backup",
+	"restore",
+	"clusterAdmin",
+	"clusterManager",
+	"clusterMonitor",
+	"hostManager",
+	"readAnyDatabase",
+	"readWriteAnyDatabase",
+	"userAdminAnyDatabase",
+	"dbAdminAnyDatabase",
+	"enableSharding",
+	"searchCoordinator",
+}
+
+// MongoRoleName returns the name of the role in mongodb
+func (r *MongoDBRole) MongoRoleName() string {
+	if r.Spec.RoleName != "" {
+		return r.Spec.RoleName
+	}
+	return r.Name
+}
+
+// MongoPrivileges converts the privileges of the MongoDBRole for the mongodb commands
+func (r *MongoDBRole) MongoPrivileges() []mongodb.Privilege {
+	privileges := []mongodb.Privilege{}
+	for _, privilege := range r.Spec.Privileges {
+		privileges = append(privileges, mongodb.Privilege{
+			DB:         privilege.Resource.Database,
+			Collection: privilege.Resource.Collection,
+			Cluster:    privilege.Resource.Cluster,
+			Actions:    privilege.Actions,
+		})
+	}
+	return privileges
+}
+
+// MongoRoles converts the inherited roles of the MongoDBRole for the mongodb commands
+func (r *MongoDBRole) MongoRoles() []mongodb.RoleRef {
+	return mongoRoleRefs(r.Spec.Roles)
+}
+
+// MongoUsername returns the name of the user in mongodb
+func (r *MongoDBUser) MongoUsername() string {
+	if r.Spec.Username != "" {
+		return r.Spec.Username
+	}
+	return r.Name
+}
+
+// CredentialsSecretName returns the name of the secret holding the credentials of the user
+func (r *MongoDBUser) CredentialsSecretName() string {
+	if r.Spec.SecretName != "" {
+		return r.Spec.SecretName
+	}
+	return r.Name + "-mongodb-user"
+}
+
+// MongoRoles converts the granted roles of the MongoDBUser for the mongodb commands
+func (r *MongoDBUser) MongoRoles() []mongodb.RoleRef {
+	return mongoRoleRefs(r.Spec.Roles)
+}
+
+// CheckRole checks the databases of the given MongoDBRole and its privileges
+// against the database policy of the MongoDBConfig, cluster privileges are never allowed
+func (r *MongoDBConfig) CheckRole(mongoRole *MongoDBRole) error {
+
+	if err := r.checkRoles(mongoRole.Namespace, mongoRole.Name, mongoRole.Spec.Database, mongoRole.Spec.Roles); err != nil {
+		return err
+	}
+
+	for _, privilege := range mongoRole.Spec.Privileges {
+		if privilege.Resource.Cluster {
+			return fmt.Errorf("cluster privileges are not allowed by MongoDBConfig %s", r.Name)
+		}
+		// an empty database is the resource of every database
+		if privilege.Resource.Database == "" {
+			return fmt.Errorf("privileges on every database are not allowed by MongoDBConfig %s", r.Name)
+		}
+		if !r.IsDatabaseAllowed(mongoRole.Namespace, mongoRole.Name, privilege.Resource.Database) {
+			return fmt.Errorf("namespace %s is not allowed to reach database %s", mongoRole.Namespace, privilege.Resource.Database)
+		}
+	}
+
+	return nil
+}
+
+// CheckUser checks the database of the given MongoDBUser and its roles
+// against the database policy of the MongoDBConfig
+func (r *MongoDBConfig) CheckUser(mongoUser *MongoDBUser) error {
+	return r.checkRoles(mongoUser.Namespace, mongoUser.Name, mongoUser.Spec.Database, mongoUser.Spec.Roles)
+}
+
+func (r *MongoDBConfig) checkRoles(namespace, name, database string, roles []RoleReference) error {
+
+	if !r.IsDatabaseAllowed(namespace, name, database) {
+		return fmt.Errorf("namespace %s is not allowed to reach database %s", namespace, database)
+	}
+
+	for _, role := range roles {
+		if isBuiltinAdminRole(role.Role) {
+			return fmt.Errorf("built-in role %s is not allowed to be granted", role.Role)
+		}
+		if !r.IsDatabaseAllowed(namespace, name, role.Database) {
+			return fmt.Errorf("namespace %s is not allowed to grant roles of database %s", namespace, role.Database)
+		}
+	}
+
+	return nil
+}
+
+// isBuiltinAdminRole reports whether the given role is one of the built-in administrative roles
+func isBuiltinAdminRole(role string) bool {
+	for _, builtin := range builtinAdminRoles {
+		if role == builtin {
+			return true
+		}
+	}
+	return false
+}
+
+func mongoRoleRefs(roles []RoleReference) []mongodb.RoleRef {
+	refs := []mongodb.RoleRef{}
+	for _, role := range roles {
+		refs = append(refs, mongodb.RoleRef{Role: role.Role, DB: role.Database})
+	}
+	return refs
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckRole(t *testing.T) {
+	mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{
+		DatabasePolicy: []DatabasePolicyRule{{Namespaces: []string{"team-a"}, Databases: []string{"team-a", "team-a-*"}}},
+	}}
+
+	tests := []struct {
+		name     string
+		spec     MongoDBRoleSpec
+		noPolicy bool
+		wantErr  bool
+	}{
+		{
+			name: "allowed databases",
+			spec: MongoDBRoleSpec{
+				Database:   "team-a",
+				Privileges: []RolePrivilege{{Resource: PrivilegeResource{Database: "team-a-logs"}, Actions: []string{"find"}}},
+				Roles:      []RoleReference{{Role: "read", Database: "team-a"}},
+			},
+		},
+		{
+			name:    "database not allowed",
+			spec:    MongoDBRoleSpec{Database: "billing"},
+			wantErr: true,
+		},
+		{
+			name: "inherited role of a database which is not allowed",
+			spec: MongoDBRoleSpec{
+				Database: "team-a",
+				Roles:    []RoleReference{{Role: "read", Database: "billing"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privilege on a database which is not allowed",
+			spec: MongoDBRoleSpec{
+				Database:   "team-a",
+				Privileges: []RolePrivilege{{Resource: PrivilegeResource{Database: "billing"}, Actions: []string{"find"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cluster privilege",
+			spec: MongoDBRoleSpec{
+				Database:   "team-a",
+				Privileges: []RolePrivilege{{Resource: PrivilegeResource{Cluster: true}, Actions: []string{"serverStatus"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privilege on every database",
+			spec: MongoDBRoleSpec{
+				Database:   "team-a",
+				Privileges: []RolePrivilege{{Resource: PrivilegeResource{Collection: "logs"}, Actions: []string{"find"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inherited built-in admin role",
+			spec: MongoDBRoleSpec{
+				Database: "team-a",
+				Roles:    []RoleReference{{Role: "dbAdminAnyDatabase", Database: "team-a"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no policy and the default database",
+			spec: MongoDBRoleSpec{
+				Database:   "team-a",
+				Privileges: []RolePrivilege{{Resource: PrivilegeResource{Database: "team-a"}, Actions: []string{"find"}}},
+			},
+			noPolicy: true,
+		},
+		{
+			name: "no policy and a privilege on another database",
+			spec: MongoDBRoleSpec{
+				Database:   "team-a",
+				Privileges: []RolePrivilege{{Resource: PrivilegeResource{Database: "team-a-logs"}, Actions: []string{"find"}}},
+			},
+			noPolicy: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := mongoCfg.DeepCopy()
+			if tt.noPolicy {
+				config.Spec.DatabasePolicy = nil
+			}
+
+			mongoRole := &MongoDBRole{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}, Spec: tt.spec}
+			if err := config.CheckRole(mongoRole); (err != nil) != tt.wantErr {
+				t.Errorf("CheckRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckUser(t *testing.T) {
+	mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{
+		DatabasePolicy: []DatabasePolicyRule{{Namespaces: []string{"team-a"}, Databases: []string{"team-a"}}},
+	}}
+
+	tests := []struct {
+		name     string
+		spec     MongoDBUserSpec
+		noPolicy bool
+		wantErr  bool
+	}{
+		{
+			name: "allowed database and roles",
+			spec: MongoDBUserSpec{Database: "team-a", Roles: []RoleReference{{Role: "readWrite", Database: "team-a"}}},
+		},
+		{
+			name:    "database not allowed",
+			spec:    MongoDBUserSpec{Database: "admin"},
+			wantErr: true,
+		},
+		{
+			name:    "role of a database which is not allowed",
+			spec:    MongoDBUserSpec{Database: "team-a", Roles: []RoleReference{{Role: "root", Database: "admin"}}},
+			wantErr: true,
+		},
+		{
+			name:    "built-in admin role of an allowed database",
+			spec:    MongoDBUserSpec{Database: "team-a", Roles: []RoleReference{{Role: "readWriteAnyDatabase", Database: "team-a"}}},
+			wantErr: true,
+		},
+		{
+			name:     "no policy and the default database",
+			spec:     MongoDBUserSpec{Database: "team-a", Roles: []RoleReference{{Role: "readWrite", Database: "team-a"}}},
+			noPolicy: true,
+		},
+		{
+			name:     "no policy and another database",
+			spec:     MongoDBUserSpec{Database: "billing"},
+			noPolicy: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := mongoCfg.DeepCopy()
+			if tt.noPolicy {
+				config.Spec.DatabasePolicy = nil
+			}
+
+			mongoUser := &MongoDBUser{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}, Spec: tt.spec}
+			if err := config.CheckUser(mongoUser); (err != nil) != tt.wantErr {
+				t.Errorf("CheckUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMongoUserNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       MongoDBUserSpec
+		wantUser   string
+		wantSecret string
+	}{
+		{
+			name:       "defaults",
+			wantUser:   "app",
+			wantSecret: "app-mongodb-user",
+		},
+		{
+			name:       "explicit names",
+			spec:       MongoDBUserSpec{Username: "svc-app", SecretName: "app-creds"},
+			wantUser:   "svc-app",
+			wantSecret: "app-creds",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoUser := &MongoDBUser{ObjectMeta: metav1.ObjectMeta{Name: "app"}, Spec: tt.spec}
+			if got := mongoUser.MongoUsername(); got != tt.wantUser {
+				t.Errorf("MongoUsername() = %s, want %s", got, tt.wantUser)
+			}
+			if got := mongoUser.CredentialsSecretName(); got != tt.wantSecret {
+				t.Errorf("CredentialsSecretName() = %s, want %s", got, tt.wantSecret)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// PrunePolicyFor returns the prune policy of the given MongoDBBulkData,
+// the deletion policy of the MongoDBConfig is used when it is not set
+func (r *MongoDBConfig) PrunePolicyFor(bulkData *MongoDBBulkData) DeletionPolicy {
+	if bulkData.Spec.PrunePolicy != "" {
+		return bulkData.Spec.PrunePolicy
+	}
+	if r.Spec.DeletionPolicy != "" {
+		return r.Spec.DeletionPolicy
+	}
+	return DeletionPolicyDelete
+}
+
+// BulkDataSoftDeleteFor returns the soft deletion settings of the given MongoDBBulkData,
+// the settings of the MongoDBConfig are used when they are not set
+func (r *MongoDBConfig) BulkDataSoftDeleteFor(bulkData *MongoDBBulkData) SoftDeleteSpec {
+	return r.softDelete(bulkData.Spec.SoftDelete)
+}
+
+// CheckBulkData checks the database and collection of the given MongoDBBulkData
+// against the database policy and allowed collections of the MongoDBConfig
+func (r *MongoDBConfig) CheckBulkData(bulkData *MongoDBBulkData) error {
+
+	if !r.IsDatabaseAllowed(bulkData.Namespace, bulkData.Name, bulkData.Spec.Database) {
+		return fmt.Errorf("namespace %s is not allowed to reach database %s", bulkData.Namespace, bulkData.Spec.Database)
+	}
+
+	if !r.IsCollectionAllowed(bulkData.Spec.Collection) {
+		return fmt.Errorf("collection %s is not allowed by MongoDBConfig %s", bulkData.Spec.Collection, r.Name)
+	}
+
+	return nil
+}
+
+// Owner is the value of the owner field of the documents of the MongoDBBulkData
+func (r *MongoDBBulkData) Owner() string {
+	return r.Namespace + "/" + r.Name
+}
+
+// BulkDocument converts a raw json object into an upsert by the key fields,
+// the document is marked with the owner field
+func (r *MongoDBBulkData) BulkDocument(index int, raw []byte) (mongodb.BulkDocument, error) {
+
+	doc, err := mongodb.NewDocument(raw)
+	if err != nil {
+		return mongodb.BulkDocument{}, err
+	}
+
+	for _, e := range doc {
+		if e.Key == "_id" || e.Key == BulkDataOwnerField {
+			return mongodb.BulkDocument{}, fmt.Errorf("%s is managed by the operator", e.Key)
+		}
+	}
+
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return mongodb.BulkDocument{}, err
+	}
+
+	filter, err := mongodb.KeyFilter(data, r.Spec.Key)
+	if err != nil {
+		return mongodb.BulkDocument{}, err
+	}
+
+	return mongodb.BulkDocument{
+		Index:    index,
+		Filter:   filter,
+		Document: append(doc, bson.E{Key: BulkDataOwnerField, Value: r.Owner()}),
+	}, nil
+}
+
+// BulkDocuments converts the raw json objects into upserts, the messages of the
+// documents which are invalid or have a duplicate key are returned separately
+func (r *MongoDBBulkData) BulkDocuments(raws [][]byte) ([]mongodb.BulkDocument, []string) {
+
+	docs := []mongodb.BulkDocument{}
+	errs := []string{}
+	seen := map[string]int{}
+
+	for i, raw := range raws {
+
+		doc, err := r.BulkDocument(i+1, raw)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("document %d: %v", i+1, err))
+			continue
+		}
+
+		key, err := doc.Key()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("document %d: %v", i+1, err))
+			continue
+		}
+
+		if first, ok := seen[key]; ok {
+			errs = append(errs, fmt.Sprintf("document %d: key is a duplicate of document %d", i+1, first))
+			continue
+		}
+		seen[key] = i + 1
+
+		docs = append(docs, doc)
+	}
+
+	return docs, errs
+}
+
+// PruneFilter matches the documents of the MongoDBBulkData which are not one of the given documents
+func (r *MongoDBBulkData) PruneFilter(docs []mongodb.BulkDocument) bson.D {
+
+	filter := bson.D{{Key: BulkDataOwnerField, Value: r.Owner()}}
+	if len(docs) == 0 {
+		return filter
+	}
+
+	keep := bson.A{}
+	for _, doc := range docs {
+		keep = append(keep, doc.Filter)
+	}
+
+	return append(filter, bson.E{Key: "$nor", Value: keep})
+}
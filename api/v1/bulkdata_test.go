@@ -0,0 +1,295 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+func TestBulkDocuments(t *testing.T) {
+	bulkData := &MongoDBBulkData{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "seed"},
+		Spec:       MongoDBBulkDataSpec{Key: []string{"code"}},
+	}
+
+	tests := []struct {
+		name     string
+		raws     []string
+		wantDocs []mongodb.BulkDocument
+		wantErrs []string
+	}{
+		{
+			name: "documents are marked with the owner",
+			raws: []string{`{"code":"a","n":1}`, `{"code":"b"}`},
+			wantDocs: []mongodb.BulkDocument{
+				{
+					Index:    1,
+					Document: bson.D{{Key: "code", Value: "a"}, {Key: "n", Value: int64(1)}, {Key: BulkDataOwnerField, Value: "team-a/seed"}},
+				},
+				{
+					Index:    2,
+					Document: bson.D{{Key: "code", Value: "b"}, {Key: BulkDataOwnerField, Value: "team-a/seed"}},
+				},
+			},
+			wantErrs: []string{},
+		},
+		{
+			name:     "invalid documents are reported",
+			raws:     []string{`{"n":1}`, `{"_id":"x","code":"a"}`, `[1]`},
+			wantDocs: []mongodb.BulkDocument{},
+			wantErrs: []string{
+				"document 1: key code is not found in document",
+				"document 2: _id is managed by the operator",
+				"document 3: document must be a json object",
+			},
+		},
+		{
+			name: "duplicate keys are reported",
+			raws: []string{`{"code":"a"}`, `{"code":"a","n":2}`},
+			wantDocs: []mongodb.BulkDocument{
+				{
+					Index:    1,
+					Document: bson.D{{Key: "code", Value: "a"}, {Key: BulkDataOwnerField, Value: "team-a/seed"}},
+				},
+			},
+			wantErrs: []string{"document 2: key is a duplicate of document 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raws := [][]byte{}
+			for _, raw := range tt.raws {
+				raws = append(raws, []byte(raw))
+			}
+
+			docs, errs := bulkData.BulkDocuments(raws)
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("BulkDocuments() errors = %q, want %q", errs, tt.wantErrs)
+			}
+			if len(docs) != len(tt.wantDocs) {
+				t.Fatalf("BulkDocuments() returned %d documents, want %d", len(docs), len(tt.wantDocs))
+			}
+
+			for i, doc := range docs {
+				want := tt.wantDocs[i]
+				if doc.Index != want.Index || !reflect.DeepEqual(doc.Document, want.Document) {
+					t.Errorf("BulkDocuments()[%d] = %+v, want %+v", i, doc, want)
+				}
+
+				// the documents are upserted by their code
+				if len(doc.Filter) != 1 || doc.Filter[0].Key != "code" ||
+					doc.Filter[0].Value.(bson.RawValue).StringValue() != want.Document[0].Value {
+					t.Errorf("BulkDocuments()[%d] filter = %v, want the code of the document", i, doc.Filter)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneFilter(t *testing.T) {
+	bulkData := &MongoDBBulkData{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "seed"}}
+	keep := bson.D{{Key: "code", Value: "a"}}
+
+	tests := []struct {
+		name string
+		docs []mongodb.BulkDocument
+		want bson.D
+	}{
+		{
+			name: "all documents of the owner",
+			want: bson.D{{Key: BulkDataOwnerField, Value: "team-a/seed"}},
+		},
+		{
+			name: "documents of the owner which are not kept",
+			docs: []mongodb.BulkDocument{{Filter: keep}},
+			want: bson.D{
+				{Key: BulkDataOwnerField, Value: "team-a/seed"},
+				{Key: "$nor", Value: bson.A{keep}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bulkData.PruneFilter(tt.docs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PruneFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrunePolicyFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DeletionPolicy
+		prune  DeletionPolicy
+		want   DeletionPolicy
+	}{
+		{
+			name: "default",
+			want: DeletionPolicyDelete,
+		},
+		{
+			name:   "deletion policy of the config",
+			config: DeletionPolicySoftDelete,
+			want:   DeletionPolicySoftDelete,
+		},
+		{
+			name:   "prune policy wins",
+			config: DeletionPolicySoftDelete,
+			prune:  DeletionPolicyRetain,
+			want:   DeletionPolicyRetain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{DeletionPolicy: tt.config}}
+			bulkData := &MongoDBBulkData{Spec: MongoDBBulkDataSpec{PrunePolicy: tt.prune}}
+
+			if got := mongoCfg.PrunePolicyFor(bulkData); got != tt.want {
+				t.Errorf("PrunePolicyFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkDataValidateDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		raws    []string
+		wantErr bool
+	}{
+		{
+			name: "unique keys",
+			raws: []string{`{"code":"a"}`, `{"code":"b"}`},
+		},
+		{
+			name:    "invalid document",
+			raws:    []string{`{"code":"a"}`, `{"n":1}`},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate keys",
+			raws:    []string{`{"code":"a"}`, `{"code":"a","n":2}`},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documents := []JSONDocument{}
+			for _, raw := range tt.raws {
+				documents = append(documents, JSONDocument{RawExtension: runtime.RawExtension{Raw: []byte(raw)}})
+			}
+
+			bulkData := &MongoDBBulkData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "seed"},
+				Spec: MongoDBBulkDataSpec{
+					DB:         "db",
+					Database:   "app",
+					Collection: "products",
+					Key:        []string{"code"},
+					Documents:  documents,
+				},
+			}
+
+			if err := bulkData.validateSpecs(); (err != nil) != tt.wantErr {
+				t.Errorf("validateSpecs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckBulkData(t *testing.T) {
+	mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{
+		Collection:         "docs",
+		AllowedCollections: []string{"app-*"},
+		DatabasePolicy:     []DatabasePolicyRule{{Namespaces: []string{"team-a"}, Databases: []string{"team-a"}}},
+	}}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		database   string
+		collection string
+		noPolicy   bool
+		wantErr    bool
+	}{
+		{
+			name:       "allowed database and collection",
+			namespace:  "team-a",
+			database:   "team-a",
+			collection: "app-users",
+		},
+		{
+			name:       "database not allowed",
+			namespace:  "team-b",
+			database:   "team-a",
+			collection: "docs",
+			wantErr:    true,
+		},
+		{
+			name:       "collection not allowed",
+			namespace:  "team-a",
+			database:   "team-a",
+			collection: "billing",
+			wantErr:    true,
+		},
+		{
+			name:       "no policy and the default database",
+			namespace:  "team-b",
+			database:   "team-b",
+			collection: "docs",
+			noPolicy:   true,
+		},
+		{
+			name:       "no policy and another database",
+			namespace:  "team-b",
+			database:   "team-a",
+			collection: "docs",
+			noPolicy:   true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := mongoCfg.DeepCopy()
+			if tt.noPolicy {
+				config.Spec.DatabasePolicy = nil
+			}
+
+			bulkData := &MongoDBBulkData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace},
+				Spec:       MongoDBBulkDataSpec{Database: tt.database, Collection: tt.collection},
+			}
+
+			if err := config.CheckBulkData(bulkData); (err != nil) != tt.wantErr {
+				t.Errorf("CheckBulkData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
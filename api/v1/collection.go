@@ -0,0 +1,140 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// CollectionFor resolves the target collection of the given MongoDBData and
+// checks it against the allowed collections of the MongoDBConfig
+func (r *MongoDBConfig) CollectionFor(mongoData *MongoDBData) (string, error) {
+
+	name := r.Spec.Collection
+	if mongoData.Spec.Collection != "" {
+		name = mongoData.Spec.Collection
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("no collection specified on MongoDBData or MongoDBConfig %s", r.Name)
+	}
+
+	if err := validateCollectionName(name); err != nil {
+		return "", err
+	}
+
+	if !r.IsCollectionAllowed(name) {
+		return "", fmt.Errorf("collection %s is not allowed by MongoDBConfig %s", name, r.Name)
+	}
+
+	return name, nil
+}
+
+// IsCollectionAllowed reports whether the given collection is the default
+// collection or matches one of the allowed collections
+func (r *MongoDBConfig) IsCollectionAllowed(name string) bool {
+	return name == r.Spec.Collection || matchAny(r.Spec.AllowedCollections, name)
+}
+
+// validateCollectionName checks the mongodb collection naming restrictions
+func validateCollectionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("collection name cannot be empty")
+	}
+	if strings.ContainsAny(name, "$\x00") {
+		return fmt.Errorf("collection name %s contains an invalid character", name)
+	}
+	if strings.HasPrefix(name, "system.") {
+		return fmt.Errorf("collection name %s cannot start with system.", name)
+	}
+	return nil
+}
+
+// CollectionSpec converts the spec of the MongoDBCollection into a mongodb collection
+func (r *MongoDBCollection) CollectionSpec() (mongodb.CollectionSpec, error) {
+
+	spec := mongodb.CollectionSpec{
+		Name:               r.Spec.Collection,
+		ValidationLevel:    r.Spec.ValidationLevel,
+		ValidationAction:   r.Spec.ValidationAction,
+		ExpireAfterSeconds: r.Spec.ExpireAfterSeconds,
+		Collation:          r.Spec.Collation.options(),
+	}
+
+	if r.Spec.JSONSchema != nil {
+		schema, err := mongodb.NewFilter(r.Spec.JSONSchema.Raw)
+		if err != nil {
+			return spec, fmt.Errorf("could not decode jsonSchema: %v", err)
+		}
+		spec.Validator = schema
+	}
+
+	if capped := r.Spec.Capped; capped != nil {
+		spec.Capped = true
+		spec.Size = capped.Size
+		spec.Max = capped.Max
+	}
+
+	if ts := r.Spec.TimeSeries; ts != nil {
+		spec.TimeSeries = &mongodb.TimeSeriesSpec{
+			TimeField:   ts.TimeField,
+			MetaField:   ts.MetaField,
+			Granularity: ts.Granularity,
+		}
+	}
+
+	if ci := r.Spec.ClusteredIndex; ci != nil {
+		spec.ClusteredIndexName = ci.Name
+		if spec.ClusteredIndexName == "" {
+			spec.ClusteredIndexName = "_id_"
+		}
+	}
+
+	return spec, nil
+}
+
+// IsReady reports whether the collection has been reconciled with the current spec
+func (r *MongoDBCollection) IsReady() bool {
+	cond := apimeta.FindStatusCondition(r.Status.Conditions, string(MongoDBCollectionConditionReady))
+	return r.Status.State == string(MongoDBCollectionConditionReady) &&
+		cond != nil && cond.ObservedGeneration == r.Generation
+}
+
+// CheckCollection checks the database and collection of the given MongoDBCollection
+// against the database policy and allowed collections of the MongoDBConfig
+func (r *MongoDBConfig) CheckCollection(mongoColl *MongoDBCollection) error {
+
+	if !r.IsDatabaseAllowed(mongoColl.Namespace, mongoColl.Name, mongoColl.Spec.Database) {
+		return fmt.Errorf("namespace %s is not allowed to reach database %s", mongoColl.Namespace, mongoColl.Spec.Database)
+	}
+
+	if !r.IsCollectionAllowed(mongoColl.Spec.Collection) {
+		return fmt.Errorf("collection %s is not allowed by MongoDBConfig %s", mongoColl.Spec.Collection, r.Name)
+	}
+
+	return nil
+}
+
+// CollectionTarget identifies a collection of a MongoDBConfig
+func CollectionTarget(db, database, collection string) string {
+	return db + "/" + database + "/" + collection
+}
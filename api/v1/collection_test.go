@@ -0,0 +1,297 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+func TestCollectionFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     MongoDBConfigSpec
+		collection string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:   "default collection",
+			config: MongoDBConfigSpec{Collection: "docs"},
+			want:   "docs",
+		},
+		{
+			name:       "default collection set on the MongoDBData",
+			config:     MongoDBConfigSpec{Collection: "docs"},
+			collection: "docs",
+			want:       "docs",
+		},
+		{
+			name:       "allowed collection pattern",
+			config:     MongoDBConfigSpec{Collection: "docs", AllowedCollections: []string{"app-*"}},
+			collection: "app-users",
+			want:       "app-users",
+		},
+		{
+			name:       "collection not allowed",
+			config:     MongoDBConfigSpec{Collection: "docs", AllowedCollections: []string{"app-*"}},
+			collection: "billing",
+			wantErr:    true,
+		},
+		{
+			name:    "no collection",
+			wantErr: true,
+		},
+		{
+			name:       "system collection",
+			config:     MongoDBConfigSpec{AllowedCollections: []string{"*"}},
+			collection: "system.users",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid character",
+			config:     MongoDBConfigSpec{AllowedCollections: []string{"*"}},
+			collection: "a$b",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &MongoDBConfig{ObjectMeta: metav1.ObjectMeta{Name: "mongo1"}, Spec: tt.config}
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{Collection: tt.collection}}
+
+			got, err := mongoCfg.CollectionFor(mongoData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CollectionFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("CollectionFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCollection(t *testing.T) {
+	mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{
+		Collection:         "docs",
+		AllowedCollections: []string{"app-*"},
+		DatabasePolicy:     []DatabasePolicyRule{{Namespaces: []string{"team-a"}, Databases: []string{"team-a"}}},
+	}}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		database   string
+		collection string
+		noPolicy   bool
+		wantErr    bool
+	}{
+		{
+			name:       "allowed database and collection",
+			namespace:  "team-a",
+			database:   "team-a",
+			collection: "app-users",
+		},
+		{
+			name:       "database not allowed",
+			namespace:  "team-b",
+			database:   "team-a",
+			collection: "docs",
+			wantErr:    true,
+		},
+		{
+			name:       "collection not allowed",
+			namespace:  "team-a",
+			database:   "team-a",
+			collection: "billing",
+			wantErr:    true,
+		},
+		{
+			name:       "no policy and the default database",
+			namespace:  "team-b",
+			database:   "team-b",
+			collection: "docs",
+			noPolicy:   true,
+		},
+		{
+			name:       "no policy and another database",
+			namespace:  "team-b",
+			database:   "team-a",
+			collection: "docs",
+			noPolicy:   true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoColl := &MongoDBCollection{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace},
+				Spec:       MongoDBCollectionSpec{Database: tt.database, Collection: tt.collection},
+			}
+
+			config := mongoCfg.DeepCopy()
+			if tt.noPolicy {
+				config.Spec.DatabasePolicy = nil
+			}
+
+			if err := config.CheckCollection(mongoColl); (err != nil) != tt.wantErr {
+				t.Errorf("CheckCollection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCollectionSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    MongoDBCollectionSpec
+		want    mongodb.CollectionSpec
+		wantErr bool
+	}{
+		{
+			name: "validator",
+			spec: MongoDBCollectionSpec{
+				Collection:      "users",
+				JSONSchema:      &runtime.RawExtension{Raw: []byte(`{"required":["email"]}`)},
+				ValidationLevel: "moderate",
+			},
+			want: mongodb.CollectionSpec{
+				Name:            "users",
+				Validator:       bson.D{{Key: "required", Value: bson.A{"email"}}},
+				ValidationLevel: "moderate",
+			},
+		},
+		{
+			name: "capped with collation",
+			spec: MongoDBCollectionSpec{
+				Collection: "logs",
+				Capped:     &CappedCollectionSpec{Size: 1024, Max: 10},
+				Collation:  &IndexCollation{Locale: "fr"},
+			},
+			want: mongodb.CollectionSpec{
+				Name:      "logs",
+				Capped:    true,
+				Size:      1024,
+				Max:       10,
+				Collation: &options.Collation{Locale: "fr"},
+			},
+		},
+		{
+			name: "time series",
+			spec: MongoDBCollectionSpec{
+				Collection: "metrics",
+				TimeSeries: &TimeSeriesCollectionSpec{TimeField: "ts", Granularity: "minutes"},
+			},
+			want: mongodb.CollectionSpec{
+				Name:       "metrics",
+				TimeSeries: &mongodb.TimeSeriesSpec{TimeField: "ts", Granularity: "minutes"},
+			},
+		},
+		{
+			name: "clustered index with the default name",
+			spec: MongoDBCollectionSpec{
+				Collection:     "events",
+				ClusteredIndex: &ClusteredIndexSpec{},
+			},
+			want: mongodb.CollectionSpec{
+				Name:               "events",
+				ClusteredIndexName: "_id_",
+			},
+		},
+		{
+			name: "invalid json schema",
+			spec: MongoDBCollectionSpec{
+				Collection: "users",
+				JSONSchema: &runtime.RawExtension{Raw: []byte(`{"required":`)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoColl := &MongoDBCollection{Spec: tt.spec}
+
+			got, err := mongoColl.CollectionSpec()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CollectionSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectionSpec() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionIsReady(t *testing.T) {
+	ready := string(MongoDBCollectionConditionReady)
+
+	tests := []struct {
+		name       string
+		state      string
+		generation int64
+		observed   int64
+		want       bool
+	}{
+		{
+			name:       "ready",
+			state:      ready,
+			generation: 2,
+			observed:   2,
+			want:       true,
+		},
+		{
+			name:       "spec changed since",
+			state:      ready,
+			generation: 3,
+			observed:   2,
+			want:       false,
+		},
+		{
+			name:       "failed",
+			state:      string(MongoDBCollectionConditionFailed),
+			generation: 2,
+			observed:   2,
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoColl := &MongoDBCollection{
+				ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+				Status: MongoDBCollectionStatus{
+					State:      tt.state,
+					Conditions: []metav1.Condition{{Type: ready, ObservedGeneration: tt.observed}},
+				},
+			}
+
+			if got := mongoColl.IsReady(); got != tt.want {
+				t.Errorf("IsReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// VersionField returns the version field of spec.concurrency, or an empty string if it's not enabled
+func (r *MongoDBData) VersionField() string {
+	if r.Spec.Concurrency == nil {
+		return ""
+	}
+	if r.Spec.Concurrency.VersionField == "" {
+		return "_version"
+	}
+	return r.Spec.Concurrency.VersionField
+}
+
+// ConflictPolicy returns the conflict policy of spec.concurrency
+func (r *MongoDBData) ConflictPolicy() ConflictPolicy {
+	if r.Spec.Concurrency == nil || r.Spec.Concurrency.ConflictPolicy == "" {
+		return ConflictPolicyFail
+	}
+	return r.Spec.Concurrency.ConflictPolicy
+}
+
+// validateConcurrency checks the version field of spec.concurrency,
+// it's managed by the operator so it cannot be a field of spec.data
+func (r *MongoDBData) validateConcurrency() *field.Error {
+	if r.Spec.Concurrency == nil {
+		return nil
+	}
+
+	key := field.NewPath("spec").Child("concurrency").Child("versionField")
+	path := r.VersionField()
+
+	if err := validateIndexField(path); err != nil {
+		return field.Invalid(key, path, err.Error())
+	}
+
+	if path == "_id" || strings.HasPrefix(path, "_id.") {
+		return field.Forbidden(key, "_id is managed by mongodb")
+	}
+
+	if len(r.Spec.Data.Raw) > 0 {
+		data, err := mongodb.MarshalDocument(r.Spec.Data.Raw)
+		if err != nil {
+			return nil
+		}
+
+		paths, err := mongodb.FieldPaths(data)
+		if err != nil {
+			return nil
+		}
+
+		for _, p := range paths {
+			if p == path || isSubPath(p, path) || isSubPath(path, p) {
+				return field.Invalid(key, path, "versionField cannot be a field of spec.data")
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestVersionField(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency *ConcurrencySpec
+		want        string
+	}{
+		{
+			name: "not enabled",
+			want: "",
+		},
+		{
+			name:        "default",
+			concurrency: &ConcurrencySpec{},
+			want:        "_version",
+		},
+		{
+			name:        "set",
+			concurrency: &ConcurrencySpec{VersionField: "meta.version"},
+			want:        "meta.version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{Concurrency: tt.concurrency}}
+			if got := mongoData.VersionField(); got != tt.want {
+				t.Errorf("VersionField() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConflictPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency *ConcurrencySpec
+		want        ConflictPolicy
+	}{
+		{
+			name: "not enabled",
+			want: ConflictPolicyFail,
+		},
+		{
+			name:        "default",
+			concurrency: &ConcurrencySpec{},
+			want:        ConflictPolicyFail,
+		},
+		{
+			name:        "set",
+			concurrency: &ConcurrencySpec{ConflictPolicy: ConflictPolicySkip},
+			want:        ConflictPolicySkip,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{Concurrency: tt.concurrency}}
+			if got := mongoData.ConflictPolicy(); got != tt.want {
+				t.Errorf("ConflictPolicy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		concurrency *ConcurrencySpec
+		wantErr     bool
+	}{
+		{
+			name: "not enabled",
+			data: `{"_version":1}`,
+		},
+		{
+			name:        "default field",
+			data:        `{"name":"x","meta":{"tag":"a"}}`,
+			concurrency: &ConcurrencySpec{},
+		},
+		{
+			name:        "nested field next to data",
+			data:        `{"meta":{"tag":"a"}}`,
+			concurrency: &ConcurrencySpec{VersionField: "meta.version"},
+		},
+		{
+			name:        "invalid field",
+			concurrency: &ConcurrencySpec{VersionField: "$version"},
+			wantErr:     true,
+		},
+		{
+			name:        "id field",
+			concurrency: &ConcurrencySpec{VersionField: "_id.version"},
+			wantErr:     true,
+		},
+		{
+			name:        "field of data",
+			data:        `{"name":"x","_version":1}`,
+			concurrency: &ConcurrencySpec{},
+			wantErr:     true,
+		},
+		{
+			name:        "field inside of data",
+			data:        `{"meta":"a"}`,
+			concurrency: &ConcurrencySpec{VersionField: "meta.version"},
+			wantErr:     true,
+		},
+		{
+			name:        "field above data",
+			data:        `{"meta":{"version":{"major":1}}}`,
+			concurrency: &ConcurrencySpec{VersionField: "meta.version"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{
+				Data:        runtime.RawExtension{Raw: []byte(tt.data)},
+				Concurrency: tt.concurrency,
+			}}
+
+			if err := mongoData.validateConcurrency(); (err != nil) != tt.wantErr {
+				t.Errorf("validateConcurrency() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
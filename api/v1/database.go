@@ -0,0 +1,251 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// DatabaseStrategy defines how the target database name is chosen
+// +kubebuilder:validation:Enum=Namespace;Fixed;PrefixNamespace;Template
+type DatabaseStrategy string
+
+const (
+	// DatabaseStrategyNamespace uses the MongoDBData namespace as database name
+	DatabaseStrategyNamespace DatabaseStrategy = "Namespace"
+
+	// DatabaseStrategyFixed uses the given database name
+	DatabaseStrategyFixed DatabaseStrategy = "Fixed"
+
+	// DatabaseStrategyPrefixNamespace uses the given prefix followed by the MongoDBData namespace
+	DatabaseStrategyPrefixNamespace DatabaseStrategy = "PrefixNamespace"
+
+	// DatabaseStrategyTemplate renders the given go template
+	DatabaseStrategyTemplate DatabaseStrategy = "Template"
+)
+
+// MongoDBDatabaseSpec defines the target database of MongoDBData documents
+type MongoDBDatabaseSpec struct {
+	// Strategy defines how the database name is chosen
+	// +kubebuilder:default=Namespace
+	// +optional
+	Strategy DatabaseStrategy `json:"strategy,omitempty"`
+
+	// Name is the database name of the Fixed strategy
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Prefix is prepended to the namespace by the PrefixNamespace strategy
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Template is a go template rendered by the Template strategy,
+	// .Namespace, .Name and .Config are available in the template
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// DatabasePolicyRule allows the matching namespaces to reach the matching databases
+type DatabasePolicyRule struct {
+	// Namespaces is a list of namespace names or glob patterns,
+	// an empty list matches every namespace
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Databases is a list of database names or glob patterns
+	Databases []string `json:"databases"`
+}
+
+// ReservedDatabases are the mongodb system databases which can never be reached
+var ReservedDatabases = []string{"admin", "local", "config"}
+
+// databaseTemplateData is passed to the Template strategy
+type databaseTemplateData struct {
+	Namespace string
+	Name      string
+	Config    string
+}
+
+// DatabaseFor resolves the target database of the given MongoDBData and
+// checks it against the database policy of the MongoDBConfig
+func (r *MongoDBConfig) DatabaseFor(mongoData *MongoDBData) (string, error) {
+
+	spec := r.Spec.Database
+	if mongoData.Spec.Database != nil {
+		spec = mongoData.Spec.Database
+	}
+
+	name, err := spec.resolve(databaseTemplateData{
+		Namespace: mongoData.Namespace,
+		Name:      mongoData.Name,
+		Config:    r.Name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := validateDatabaseName(name); err != nil {
+		return "", err
+	}
+
+	if isReservedDatabase(name) {
+		return "", fmt.Errorf("database %s is reserved", name)
+	}
+
+	if !r.IsDatabaseAllowed(mongoData.Namespace, mongoData.Name, name) {
+		return "", fmt.Errorf("namespace %s is not allowed to reach database %s", mongoData.Namespace, name)
+	}
+
+	return name, nil
+}
+
+// IsDatabaseAllowed reports whether the object of the given namespace and name can reach the given database.
+// The database chosen by the MongoDBConfig itself is trusted when there is no database policy, any other
+// database must be allowed by the policy and the reserved databases are never allowed
+func (r *MongoDBConfig) IsDatabaseAllowed(namespace, name, database string) bool {
+	if isReservedDatabase(database) {
+		return false
+	}
+
+	if len(r.Spec.DatabasePolicy) == 0 {
+		defaultDatabase, err := r.Spec.Database.resolve(databaseTemplateData{
+			Namespace: namespace,
+			Name:      name,
+			Config:    r.Name,
+		})
+		return err == nil && database == defaultDatabase
+	}
+
+	for _, rule := range r.Spec.DatabasePolicy {
+		if len(rule.Namespaces) > 0 && !matchAny(rule.Namespaces, namespace) {
+			continue
+		}
+		if matchAny(rule.Databases, database) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *MongoDBDatabaseSpec) resolve(data databaseTemplateData) (string, error) {
+	if s == nil {
+		return data.Namespace, nil
+	}
+
+	switch s.Strategy {
+	case DatabaseStrategyNamespace, "":
+		return data.Namespace, nil
+	case DatabaseStrategyFixed:
+		return s.Name, nil
+	case DatabaseStrategyPrefixNamespace:
+		return s.Prefix + data.Namespace, nil
+	case DatabaseStrategyTemplate:
+
+		tmpl, err := template.New("database").Option("missingkey=error").Parse(s.Template)
+		if err != nil {
+			return "", fmt.Errorf("could not parse database template: %v", err)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, data); err != nil {
+			return "", fmt.Errorf("could not render database template: %v", err)
+		}
+
+		return buf.String(), nil
+	}
+
+	return "", fmt.Errorf("unknown database strategy %s", s.Strategy)
+}
+
+func (s *MongoDBDatabaseSpec) validate() error {
+	if s == nil {
+		return nil
+	}
+
+	switch s.Strategy {
+	case DatabaseStrategyFixed:
+		if s.Name == "" {
+			return fmt.Errorf("name must be specified for the Fixed strategy")
+		}
+		if isReservedDatabase(s.Name) {
+			return fmt.Errorf("database %s is reserved", s.Name)
+		}
+		return validateDatabaseName(s.Name)
+	case DatabaseStrategyPrefixNamespace:
+		if s.Prefix == "" {
+			return fmt.Errorf("prefix must be specified for the PrefixNamespace strategy")
+		}
+	case DatabaseStrategyTemplate:
+		if s.Template == "" {
+			return fmt.Errorf("template must be specified for the Template strategy")
+		}
+		if _, err := template.New("database").Parse(s.Template); err != nil {
+			return fmt.Errorf("could not parse database template: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// validateDatabaseName checks the mongodb database naming restrictions
+func validateDatabaseName(name string) error {
+	if name == "" {
+		return fmt.Errorf("database name cannot be empty")
+	}
+	if len(name) > 63 {
+		return fmt.Errorf("database name %s must be shorter than 64 characters", name)
+	}
+	if strings.ContainsAny(name, "/\\. \"$*<>:|?\x00") {
+		return fmt.Errorf("database name %s contains an invalid character", name)
+	}
+	return nil
+}
+
+// isReservedDatabase reports whether the given database is a mongodb system database
+func isReservedDatabase(name string) bool {
+	for _, reserved := range ReservedDatabases {
+		if name == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePatterns checks the syntax of the given glob patterns
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s is not a valid pattern: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchAny reports whether the given name matches one of the names or glob patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDatabaseFor(t *testing.T) {
+	policy := []DatabasePolicyRule{
+		{Namespaces: []string{"team-*"}, Databases: []string{"team-*", "shared"}},
+		{Databases: []string{"public"}},
+	}
+
+	tests := []struct {
+		name     string
+		config   MongoDBConfigSpec
+		database *MongoDBDatabaseSpec
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "namespace without policy",
+			want: "team-a",
+		},
+		{
+			name:   "fixed database of the config without policy",
+			config: MongoDBConfigSpec{Database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "app"}},
+			want:   "app",
+		},
+		{
+			name:     "override without policy",
+			database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "app"},
+			wantErr:  true,
+		},
+		{
+			name:     "override allowed by policy",
+			config:   MongoDBConfigSpec{DatabasePolicy: policy},
+			database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "shared"},
+			want:     "shared",
+		},
+		{
+			name:     "override allowed by a database pattern",
+			config:   MongoDBConfigSpec{DatabasePolicy: policy},
+			database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "team-b-private"},
+			want:     "team-b-private",
+		},
+		{
+			name:     "override matched by a rule for every namespace",
+			config:   MongoDBConfigSpec{DatabasePolicy: policy},
+			database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "public"},
+			want:     "public",
+		},
+		{
+			name:     "override denied by policy",
+			config:   MongoDBConfigSpec{DatabasePolicy: policy},
+			database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "billing"},
+			wantErr:  true,
+		},
+		{
+			name:     "prefix namespace",
+			config:   MongoDBConfigSpec{DatabasePolicy: policy},
+			database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyPrefixNamespace, Prefix: "team-"},
+			want:     "team-team-a",
+		},
+		{
+			name: "template",
+			config: MongoDBConfigSpec{
+				Database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyTemplate, Template: "{{ .Config }}-{{ .Name }}"},
+			},
+			want: "mongo1-doc",
+		},
+		{
+			name: "template with a missing key",
+			config: MongoDBConfigSpec{
+				Database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyTemplate, Template: "{{ .Cluster }}"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "reserved database of the config",
+			config:  MongoDBConfigSpec{Database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "admin"}},
+			wantErr: true,
+		},
+		{
+			name: "reserved database allowed by policy",
+			config: MongoDBConfigSpec{
+				DatabasePolicy: []DatabasePolicyRule{{Databases: []string{"*"}}},
+			},
+			database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "local"},
+			wantErr:  true,
+		},
+		{
+			name:    "invalid database name",
+			config:  MongoDBConfigSpec{Database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "a.b"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &MongoDBConfig{ObjectMeta: metav1.ObjectMeta{Name: "mongo1"}, Spec: tt.config}
+			mongoData := &MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "doc"},
+				Spec:       MongoDBDataSpec{Database: tt.database},
+			}
+
+			got, err := mongoCfg.DatabaseFor(mongoData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DatabaseFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("DatabaseFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDatabaseAllowed(t *testing.T) {
+	mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{
+		DatabasePolicy: []DatabasePolicyRule{
+			{Namespaces: []string{"team-*"}, Databases: []string{"team-*"}},
+			{Databases: []string{"public", "*"}},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		config    *MongoDBConfig
+		namespace string
+		object    string
+		database  string
+		want      bool
+	}{
+		{
+			name:      "no policy and the default database",
+			config:    &MongoDBConfig{},
+			namespace: "team-a",
+			database:  "team-a",
+			want:      true,
+		},
+		{
+			name:      "no policy and another database",
+			config:    &MongoDBConfig{},
+			namespace: "team-a",
+			database:  "team-b",
+			want:      false,
+		},
+		{
+			name: "no policy and the default database of the template",
+			config: &MongoDBConfig{Spec: MongoDBConfigSpec{Database: &MongoDBDatabaseSpec{
+				Strategy: DatabaseStrategyTemplate,
+				Template: "{{ .Namespace }}-{{ .Name }}",
+			}}},
+			namespace: "team-a",
+			object:    "app",
+			database:  "team-a-app",
+			want:      true,
+		},
+		{
+			name:      "no policy and a reserved default database",
+			config:    &MongoDBConfig{Spec: MongoDBConfigSpec{Database: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "admin"}}},
+			namespace: "team-a",
+			database:  "admin",
+			want:      false,
+		},
+		{
+			name:      "matching rule",
+			config:    mongoCfg,
+			namespace: "team-a",
+			database:  "team-b",
+			want:      true,
+		},
+		{
+			name:      "rule for every namespace",
+			config:    mongoCfg,
+			namespace: "other",
+			database:  "public",
+			want:      true,
+		},
+		{
+			name:      "reserved database",
+			config:    mongoCfg,
+			namespace: "team-a",
+			database:  "admin",
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.IsDatabaseAllowed(tt.namespace, tt.object, tt.database); got != tt.want {
+				t.Errorf("IsDatabaseAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDatabaseSpecValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *MongoDBDatabaseSpec
+		wantErr bool
+	}{
+		{
+			name: "no spec",
+		},
+		{
+			name: "namespace",
+			spec: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyNamespace},
+		},
+		{
+			name: "fixed",
+			spec: &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "app"},
+		},
+		{
+			name:    "fixed without name",
+			spec:    &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed},
+			wantErr: true,
+		},
+		{
+			name:    "fixed reserved database",
+			spec:    &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "config"},
+			wantErr: true,
+		},
+		{
+			name:    "fixed invalid name",
+			spec:    &MongoDBDatabaseSpec{Strategy: DatabaseStrategyFixed, Name: "a/b"},
+			wantErr: true,
+		},
+		{
+			name:    "prefix namespace without prefix",
+			spec:    &MongoDBDatabaseSpec{Strategy: DatabaseStrategyPrefixNamespace},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			spec:    &MongoDBDatabaseSpec{Strategy: DatabaseStrategyTemplate, Template: "{{ .Namespace"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
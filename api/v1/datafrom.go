@@ -0,0 +1,202 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// dataFromNotFoundError is returned when the ConfigMap, Secret or key of a dataFrom doesn't exist
+type dataFromNotFoundError struct {
+	msg string
+}
+
+func (e *dataFromNotFoundError) Error() string {
+	return e.msg
+}
+
+// IsDataFromNotFound reports whether the error is caused by a missing ConfigMap, Secret or key of a dataFrom
+func IsDataFromNotFound(err error) bool {
+	var notFound *dataFromNotFoundError
+	return errors.As(err, &notFound)
+}
+
+// Document returns the bson of spec.data merged with the data of spec.dataFrom
+func (r *MongoDBData) Document(ctx context.Context, c client.Reader) ([]byte, error) {
+
+	if len(r.Spec.Data.Raw) == 0 && len(r.Spec.DataFrom) == 0 {
+		return nil, fmt.Errorf("data cannot be empty")
+	}
+
+	doc := bson.D{}
+	if len(r.Spec.Data.Raw) > 0 {
+		var err error
+		if doc, err = mongodb.NewDocument(r.Spec.Data.Raw); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range r.Spec.DataFrom {
+		source := &r.Spec.DataFrom[i]
+
+		data, err := source.read(ctx, c, r.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("dataFrom[%d]: %w", i, err)
+		}
+
+		value, err := decodeDataFrom(data)
+		if err != nil {
+			return nil, fmt.Errorf("dataFrom[%d]: %v", i, err)
+		}
+
+		if doc, err = mongodb.MergeDocument(doc, source.Path, value); err != nil {
+			return nil, fmt.Errorf("dataFrom[%d]: %v", i, err)
+		}
+	}
+
+	for _, e := range doc {
+		if e.Key == "_id" {
+			return nil, fmt.Errorf("_id is managed by the operator")
+		}
+	}
+
+	return bson.Marshal(doc)
+}
+
+// SecretDataPaths returns the document field paths which are set by the Secrets of spec.dataFrom,
+// a Secret which is merged into the root of the document sets its top level fields
+func (r *MongoDBData) SecretDataPaths(ctx context.Context, c client.Reader) ([]string, error) {
+	paths := []string{}
+	for i := range r.Spec.DataFrom {
+		source := &r.Spec.DataFrom[i]
+		if source.SecretKeyRef == nil {
+			continue
+		}
+
+		if source.Path != "" {
+			paths = append(paths, source.Path)
+			continue
+		}
+
+		data, err := source.read(ctx, c, r.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("dataFrom[%d]: %w", i, err)
+		}
+
+		value, err := decodeDataFrom(data)
+		if err != nil {
+			return nil, fmt.Errorf("dataFrom[%d]: %v", i, err)
+		}
+
+		if obj, ok := value.(bson.D); ok {
+			for _, e := range obj {
+				paths = append(paths, e.Key)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// read returns the data of the referenced ConfigMap or Secret key
+func (s *MongoDBDataFrom) read(ctx context.Context, c client.Reader, namespace string) ([]byte, error) {
+
+	if ref := s.ConfigMapKeyRef; ref != nil {
+
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, &dataFromNotFoundError{fmt.Sprintf("ConfigMap %s doesn't exist", ref.Name)}
+			}
+			return nil, err
+		}
+
+		if data, ok := configMap.Data[ref.Key]; ok {
+			return []byte(data), nil
+		}
+		if data, ok := configMap.BinaryData[ref.Key]; ok {
+			return data, nil
+		}
+
+		return nil, &dataFromNotFoundError{fmt.Sprintf("key %s is not found in ConfigMap %s", ref.Key, ref.Name)}
+	}
+
+	if ref := s.SecretKeyRef; ref != nil {
+
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, &dataFromNotFoundError{fmt.Sprintf("Secret %s doesn't exist", ref.Name)}
+			}
+			return nil, err
+		}
+
+		if data, ok := secret.Data[ref.Key]; ok {
+			return data, nil
+		}
+
+		return nil, &dataFromNotFoundError{fmt.Sprintf("key %s is not found in Secret %s", ref.Key, ref.Name)}
+	}
+
+	return nil, fmt.Errorf("one of configMapKeyRef or secretKeyRef must be specified")
+}
+
+// decodeDataFrom converts json or yaml into a bson value, json is tried first
+// since it keeps the order of the fields
+func decodeDataFrom(data []byte) (interface{}, error) {
+	if value, err := mongodb.NewValue(data); err == nil {
+		return value, nil
+	}
+
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("data is neither json nor yaml: %v", err)
+	}
+
+	return mongodb.NewValue(jsonData)
+}
+
+// SourceRefs returns the ConfigMaps and Secrets of spec.dataFrom and spec.secretFields as kind/name
+func (r *MongoDBData) SourceRefs() []string {
+	refs := []string{}
+	for _, source := range r.Spec.DataFrom {
+		if source.ConfigMapKeyRef != nil {
+			refs = append(refs, SourceRef("ConfigMap", source.ConfigMapKeyRef.Name))
+		}
+		if source.SecretKeyRef != nil {
+			refs = append(refs, SourceRef("Secret", source.SecretKeyRef.Name))
+		}
+	}
+	for _, secretField := range r.Spec.SecretFields {
+		refs = append(refs, SourceRef("Secret", secretField.SecretKeyRef.Name))
+	}
+	return refs
+}
+
+// SourceRef identifies a ConfigMap or Secret of spec.dataFrom or spec.secretFields
+func SourceRef(kind, name string) string {
+	return kind + "/" + name
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDocument(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "settings"},
+			Data: map[string]string{
+				"settings.json": `{"theme":"dark","limits":{"max":5}}`,
+				"region":        "eu",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "creds"},
+			Data:       map[string][]byte{"creds.yaml": []byte("user: app\nport: 27017\n")},
+		},
+	).Build()
+
+	configMapKey := func(key, path string) MongoDBDataFrom {
+		return MongoDBDataFrom{ConfigMapKeyRef: &ConfigMapKeyReference{Name: "settings", Key: key}, Path: path}
+	}
+
+	tests := []struct {
+		name         string
+		data         string
+		dataFrom     []MongoDBDataFrom
+		want         bson.D
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{
+			name: "data only",
+			data: `{"name":"x"}`,
+			want: bson.D{{Key: "name", Value: "x"}},
+		},
+		{
+			name:     "json merged into the root",
+			data:     `{"name":"x","limits":{"min":1}}`,
+			dataFrom: []MongoDBDataFrom{configMapKey("settings.json", "")},
+			want: bson.D{
+				{Key: "name", Value: "x"},
+				{Key: "limits", Value: bson.D{{Key: "min", Value: int64(1)}, {Key: "max", Value: int64(5)}}},
+				{Key: "theme", Value: "dark"},
+			},
+		},
+		{
+			name: "plain value and yaml secret merged at paths",
+			dataFrom: []MongoDBDataFrom{
+				configMapKey("region", "meta.region"),
+				{SecretKeyRef: &LocalSecretKeyReference{Name: "creds", Key: "creds.yaml"}, Path: "auth"},
+			},
+			want: bson.D{
+				{Key: "meta", Value: bson.D{{Key: "region", Value: "eu"}}},
+				{Key: "auth", Value: bson.D{{Key: "port", Value: int64(27017)}, {Key: "user", Value: "app"}}},
+			},
+		},
+		{
+			name:         "missing configmap",
+			dataFrom:     []MongoDBDataFrom{{ConfigMapKeyRef: &ConfigMapKeyReference{Name: "other", Key: "x"}, Path: "x"}},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:         "missing secret key",
+			dataFrom:     []MongoDBDataFrom{{SecretKeyRef: &LocalSecretKeyReference{Name: "creds", Key: "other"}, Path: "x"}},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:     "plain value merged into the root",
+			dataFrom: []MongoDBDataFrom{configMapKey("region", "")},
+			wantErr:  true,
+		},
+		{
+			name:    "empty data",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+				Spec:       MongoDBDataSpec{Data: runtime.RawExtension{Raw: []byte(tt.data)}, DataFrom: tt.dataFrom},
+			}
+
+			got, err := mongoData.Document(context.Background(), c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if IsDataFromNotFound(err) != tt.wantNotFound {
+				t.Errorf("IsDataFromNotFound() = %v, want %v", IsDataFromNotFound(err), tt.wantNotFound)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var doc bson.D
+			if err := bson.Unmarshal(got, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(doc, tt.want) {
+				t.Errorf("Document() = %#v, want %#v", doc, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceRefs(t *testing.T) {
+	mongoData := &MongoDBData{Spec: MongoDBDataSpec{
+		DataFrom: []MongoDBDataFrom{
+			{ConfigMapKeyRef: &ConfigMapKeyReference{Name: "settings", Key: "a"}},
+			{SecretKeyRef: &LocalSecretKeyReference{Name: "creds", Key: "b"}},
+		},
+		SecretFields: []MongoDBSecretField{
+			{Path: "password", SecretKeyRef: LocalSecretKeyReference{Name: "passwords", Key: "c"}},
+		},
+	}}
+
+	want := []string{"ConfigMap/settings", "Secret/creds", "Secret/passwords"}
+	if got := mongoData.SourceRefs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SourceRefs() = %v, want %v", got, want)
+	}
+}
+
+func TestSecretDataPaths(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "creds"},
+			Data: map[string][]byte{
+				"password": []byte("hunter2"),
+				"root":     []byte(`{"token":"abc","auth":{"key":"def"}}`),
+			},
+		},
+	).Build()
+
+	tests := []struct {
+		name     string
+		dataFrom []MongoDBDataFrom
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "configmaps are left out",
+			dataFrom: []MongoDBDataFrom{
+				{ConfigMapKeyRef: &ConfigMapKeyReference{Name: "settings", Key: "region"}, Path: "region"},
+			},
+			want: []string{},
+		},
+		{
+			name: "secret merged at a path",
+			dataFrom: []MongoDBDataFrom{
+				{SecretKeyRef: &LocalSecretKeyReference{Name: "creds", Key: "password"}, Path: "db.password"},
+			},
+			want: []string{"db.password"},
+		},
+		{
+			name: "secret merged into the root",
+			dataFrom: []MongoDBDataFrom{
+				{SecretKeyRef: &LocalSecretKeyReference{Name: "creds", Key: "root"}},
+			},
+			want: []string{"token", "auth"},
+		},
+		{
+			name: "missing secret",
+			dataFrom: []MongoDBDataFrom{
+				{SecretKeyRef: &LocalSecretKeyReference{Name: "other", Key: "root"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+				Spec:       MongoDBDataSpec{DataFrom: tt.dataFrom},
+			}
+
+			got, err := mongoData.SecretDataPaths(context.Background(), c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretDataPaths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SecretDataPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
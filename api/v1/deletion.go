@@ -0,0 +1,111 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeletionPolicy defines what happens to the document when the MongoDBData is deleted
+// +kubebuilder:validation:Enum=Delete;Retain;SoftDelete
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the document
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyRetain leaves the document untouched
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+
+	// DeletionPolicySoftDelete marks the document as deleted
+	DeletionPolicySoftDelete DeletionPolicy = "SoftDelete"
+)
+
+// SoftDeleteType defines the value which marks a document as deleted
+// +kubebuilder:validation:Enum=Timestamp;Boolean
+type SoftDeleteType string
+
+const (
+	// SoftDeleteTypeTimestamp sets the field to the deletion time
+	SoftDeleteTypeTimestamp SoftDeleteType = "Timestamp"
+
+	// SoftDeleteTypeBoolean sets the field to true
+	SoftDeleteTypeBoolean SoftDeleteType = "Boolean"
+)
+
+// SoftDeleteSpec defines how a document is marked as deleted by the SoftDelete policy
+type SoftDeleteSpec struct {
+	// Field is the document field which marks the document as deleted
+	// +kubebuilder:default="deletedAt"
+	// +optional
+	Field string `json:"field,omitempty"`
+
+	// Type of the value which is set on the field
+	// +kubebuilder:default=Timestamp
+	// +optional
+	Type SoftDeleteType `json:"type,omitempty"`
+}
+
+// DeletionPolicyFor returns the deletion policy of the given MongoDBData,
+// the default policy of the MongoDBConfig is used when it is not set
+func (r *MongoDBConfig) DeletionPolicyFor(mongoData *MongoDBData) DeletionPolicy {
+	if mongoData.Spec.DeletionPolicy != "" {
+		return mongoData.Spec.DeletionPolicy
+	}
+	if r.Spec.DeletionPolicy != "" {
+		return r.Spec.DeletionPolicy
+	}
+	return DeletionPolicyDelete
+}
+
+// SoftDeleteFor returns the soft deletion settings of the given MongoDBData,
+// the settings of the MongoDBConfig are used when they are not set
+func (r *MongoDBConfig) SoftDeleteFor(mongoData *MongoDBData) SoftDeleteSpec {
+	return r.softDelete(mongoData.Spec.SoftDelete)
+}
+
+// softDelete returns the given soft deletion settings, or the settings
+// of the MongoDBConfig when they are not set, with the defaults filled in
+func (r *MongoDBConfig) softDelete(override *SoftDeleteSpec) SoftDeleteSpec {
+	spec := SoftDeleteSpec{}
+	if r.Spec.SoftDelete != nil {
+		spec = *r.Spec.SoftDelete
+	}
+	if override != nil {
+		spec = *override
+	}
+
+	if spec.Field == "" {
+		spec.Field = "deletedAt"
+	}
+	if spec.Type == "" {
+		spec.Type = SoftDeleteTypeTimestamp
+	}
+
+	return spec
+}
+
+func (s *SoftDeleteSpec) validate() error {
+	if s.Field == "" {
+		return nil
+	}
+	if s.Field == "_id" || strings.HasPrefix(s.Field, "$") {
+		return fmt.Errorf("field %s cannot be used to mark documents as deleted", s.Field)
+	}
+	return nil
+}
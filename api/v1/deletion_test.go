@@ -0,0 +1,126 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "testing"
+
+func TestDeletionPolicyFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DeletionPolicy
+		data   DeletionPolicy
+		want   DeletionPolicy
+	}{
+		{
+			name: "default",
+			want: DeletionPolicyDelete,
+		},
+		{
+			name:   "policy of the config",
+			config: DeletionPolicyRetain,
+			want:   DeletionPolicyRetain,
+		},
+		{
+			name:   "policy of the MongoDBData wins",
+			config: DeletionPolicyRetain,
+			data:   DeletionPolicySoftDelete,
+			want:   DeletionPolicySoftDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{DeletionPolicy: tt.config}}
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{DeletionPolicy: tt.data}}
+
+			if got := mongoCfg.DeletionPolicyFor(mongoData); got != tt.want {
+				t.Errorf("DeletionPolicyFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *SoftDeleteSpec
+		data   *SoftDeleteSpec
+		want   SoftDeleteSpec
+	}{
+		{
+			name: "defaults",
+			want: SoftDeleteSpec{Field: "deletedAt", Type: SoftDeleteTypeTimestamp},
+		},
+		{
+			name:   "settings of the config",
+			config: &SoftDeleteSpec{Field: "removed", Type: SoftDeleteTypeBoolean},
+			want:   SoftDeleteSpec{Field: "removed", Type: SoftDeleteTypeBoolean},
+		},
+		{
+			name:   "settings of the MongoDBData win",
+			config: &SoftDeleteSpec{Field: "removed", Type: SoftDeleteTypeBoolean},
+			data:   &SoftDeleteSpec{Field: "archivedAt"},
+			want:   SoftDeleteSpec{Field: "archivedAt", Type: SoftDeleteTypeTimestamp},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{SoftDelete: tt.config}}
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{SoftDelete: tt.data}}
+
+			if got := mongoCfg.SoftDeleteFor(mongoData); got != tt.want {
+				t.Errorf("SoftDeleteFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteSpecValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		wantErr bool
+	}{
+		{
+			name: "default field",
+		},
+		{
+			name:  "nested field",
+			field: "meta.deletedAt",
+		},
+		{
+			name:    "_id",
+			field:   "_id",
+			wantErr: true,
+		},
+		{
+			name:    "operator",
+			field:   "$set",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &SoftDeleteSpec{Field: tt.field}
+			if err := spec.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
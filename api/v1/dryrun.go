@@ -0,0 +1,46 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DryRunAnnotation makes the operator compute the writes of the document and report them
+// in status.plannedWrite and events instead of executing them
+const DryRunAnnotation = "mongo.snappcloud.io/dry-run"
+
+// IsDryRun reports whether the dry-run annotation is set to true
+func (r *MongoDBData) IsDryRun() bool {
+	dryRun, _ := strconv.ParseBool(r.Annotations[DryRunAnnotation])
+	return dryRun
+}
+
+// validateDryRun checks that the dry-run annotation is a boolean
+func (r *MongoDBData) validateDryRun() error {
+	value, ok := r.Annotations[DryRunAnnotation]
+	if !ok {
+		return nil
+	}
+
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("annotation %s must be true or false, got %q", DryRunAnnotation, value)
+	}
+
+	return nil
+}
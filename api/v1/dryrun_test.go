@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDryRunAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "true",
+			annotations: map[string]string{DryRunAnnotation: "true"},
+			want:        true,
+		},
+		{
+			name:        "false",
+			annotations: map[string]string{DryRunAnnotation: "false"},
+		},
+		{
+			name:        "not a boolean",
+			annotations: map[string]string{DryRunAnnotation: "yes"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+
+			if got := mongoData.IsDryRun(); got != tt.want {
+				t.Errorf("IsDryRun() = %v, want %v", got, tt.want)
+			}
+			if err := mongoData.validateDryRun(); (err != nil) != tt.wantErr {
+				t.Errorf("validateDryRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// EncryptedFields returns spec.encryptedFields with the algorithm names of mongodb
+func (r *MongoDBData) EncryptedFields() []mongodb.EncryptedField {
+	fields := make([]mongodb.EncryptedField, 0, len(r.Spec.EncryptedFields))
+	for _, encField := range r.Spec.EncryptedFields {
+
+		algorithm := mongodb.AlgorithmDeterministic
+		if encField.Algorithm == EncryptionRandom {
+			algorithm = mongodb.AlgorithmRandom
+		}
+
+		fields = append(fields, mongodb.EncryptedField{Path: encField.Path, Algorithm: algorithm})
+	}
+	return fields
+}
+
+// validateEncryptedFields checks the paths of spec.encryptedFields, the fields of spec.key
+// must stay queryable so they can only be encrypted with the deterministic algorithm
+func (r *MongoDBData) validateEncryptedFields() *field.Error {
+	key := field.NewPath("spec").Child("encryptedFields")
+
+	for i, encField := range r.Spec.EncryptedFields {
+		path := encField.Path
+
+		if err := validateIndexField(path); err != nil {
+			return field.Invalid(key.Index(i).Child("path"), path, err.Error())
+		}
+
+		if path == "_id" || strings.HasPrefix(path, "_id.") {
+			return field.Forbidden(key.Index(i).Child("path"), "_id is managed by the operator")
+		}
+
+		for j, other := range r.Spec.EncryptedFields[:i] {
+			if other.Path == path || isSubPath(other.Path, path) || isSubPath(path, other.Path) {
+				return field.Invalid(key.Index(i).Child("path"), path, fmt.Sprintf("path overlaps spec.encryptedFields[%d]", j))
+			}
+		}
+
+		for _, keyPath := range r.Spec.Key {
+			if isSubPath(path, keyPath) {
+				return field.Forbidden(key.Index(i).Child("path"), "fields of spec.key cannot be inside an encrypted field")
+			}
+			if keyPath == path && encField.Algorithm == EncryptionRandom {
+				return field.Forbidden(key.Index(i).Child("algorithm"), "fields of spec.key must be encrypted with the Deterministic algorithm")
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSubPath reports whether the dot separated path is inside the parent path
+func isSubPath(parent, path string) bool {
+	return strings.HasPrefix(path, parent+".")
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+func TestEncryptedFields(t *testing.T) {
+	mongoData := &MongoDBData{Spec: MongoDBDataSpec{EncryptedFields: []MongoDBEncryptedField{
+		{Path: "email"},
+		{Path: "ssn", Algorithm: EncryptionRandom},
+		{Path: "phone", Algorithm: EncryptionDeterministic},
+	}}}
+
+	want := []mongodb.EncryptedField{
+		{Path: "email", Algorithm: mongodb.AlgorithmDeterministic},
+		{Path: "ssn", Algorithm: mongodb.AlgorithmRandom},
+		{Path: "phone", Algorithm: mongodb.AlgorithmDeterministic},
+	}
+	if got := mongoData.EncryptedFields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EncryptedFields() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateEncryptedFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     []string
+		fields  []MongoDBEncryptedField
+		wantErr bool
+	}{
+		{
+			name:   "deterministic key field",
+			key:    []string{"email"},
+			fields: []MongoDBEncryptedField{{Path: "email"}, {Path: "profile.ssn", Algorithm: EncryptionRandom}},
+		},
+		{
+			name:    "_id",
+			fields:  []MongoDBEncryptedField{{Path: "_id"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid path",
+			fields:  []MongoDBEncryptedField{{Path: "a..b"}},
+			wantErr: true,
+		},
+		{
+			name:    "overlapping paths",
+			fields:  []MongoDBEncryptedField{{Path: "profile"}, {Path: "profile.ssn"}},
+			wantErr: true,
+		},
+		{
+			name:    "random key field",
+			key:     []string{"email"},
+			fields:  []MongoDBEncryptedField{{Path: "email", Algorithm: EncryptionRandom}},
+			wantErr: true,
+		},
+		{
+			name:    "key field inside an encrypted field",
+			key:     []string{"profile.email"},
+			fields:  []MongoDBEncryptedField{{Path: "profile"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{Key: tt.key, EncryptedFields: tt.fields}}
+			if err := mongoData.validateEncryptedFields(); (err != nil) != tt.wantErr {
+				t.Errorf("validateEncryptedFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
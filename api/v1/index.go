@@ -0,0 +1,124 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// IndexSpec converts the spec of the MongoDBIndex into a mongodb index
+func (r *MongoDBIndex) IndexSpec() (mongodb.IndexSpec, error) {
+
+	keys := bson.D{}
+	for _, key := range r.Spec.Keys {
+		keys = append(keys, bson.E{Key: key.Field, Value: key.Type.value()})
+	}
+
+	spec := mongodb.IndexSpec{
+		Name:               r.Spec.Name,
+		Keys:               keys,
+		Unique:             r.Spec.Unique,
+		Sparse:             r.Spec.Sparse,
+		ExpireAfterSeconds: r.Spec.ExpireAfterSeconds,
+	}
+
+	if spec.Name == "" {
+		spec.Name = mongodb.IndexName(keys)
+	}
+
+	if r.Spec.PartialFilterExpression != nil {
+		filter, err := mongodb.NewFilter(r.Spec.PartialFilterExpression.Raw)
+		if err != nil {
+			return spec, err
+		}
+		spec.PartialFilterExpression = filter
+	}
+
+	spec.Collation = r.Spec.Collation.options()
+
+	return spec, nil
+}
+
+// CheckIndex checks the database and collection of the given MongoDBIndex
+// against the database policy and allowed collections of the MongoDBConfig
+func (r *MongoDBConfig) CheckIndex(mongoIndex *MongoDBIndex) error {
+
+	if !r.IsDatabaseAllowed(mongoIndex.Namespace, mongoIndex.Name, mongoIndex.Spec.Database) {
+		return fmt.Errorf("namespace %s is not allowed to reach database %s", mongoIndex.Namespace, mongoIndex.Spec.Database)
+	}
+
+	if !r.IsCollectionAllowed(mongoIndex.Spec.Collection) {
+		return fmt.Errorf("collection %s is not allowed by MongoDBConfig %s", mongoIndex.Spec.Collection, r.Name)
+	}
+
+	return nil
+}
+
+// options converts the collation into the mongodb driver options
+func (c *IndexCollation) options() *options.Collation {
+	if c == nil {
+		return nil
+	}
+
+	return &options.Collation{
+		Locale:          c.Locale,
+		CaseLevel:       c.CaseLevel,
+		CaseFirst:       c.CaseFirst,
+		Strength:        c.Strength,
+		NumericOrdering: c.NumericOrdering,
+		Alternate:       c.Alternate,
+		MaxVariable:     c.MaxVariable,
+		Backwards:       c.Backwards,
+	}
+}
+
+// value returns the mongodb representation of the index key type
+func (t IndexKeyType) value() interface{} {
+	switch t {
+	case IndexKeyDescending:
+		return int32(-1)
+	case IndexKeyText:
+		return "text"
+	case IndexKeyHashed:
+		return "hashed"
+	case IndexKey2dsphere:
+		return "2dsphere"
+	}
+	return int32(1)
+}
+
+// validateIndexField checks the mongodb field path restrictions of an index key
+func validateIndexField(field string) error {
+	if field == "" {
+		return fmt.Errorf("field cannot be empty")
+	}
+	for _, part := range strings.Split(field, ".") {
+		if part == "" {
+			return fmt.Errorf("field %s has an empty path element", field)
+		}
+		if strings.HasPrefix(part, "$") {
+			return fmt.Errorf("field %s cannot start with $", field)
+		}
+	}
+	return nil
+}
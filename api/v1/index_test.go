@@ -0,0 +1,195 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+func TestIndexSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    MongoDBIndexSpec
+		want    mongodb.IndexSpec
+		wantErr bool
+	}{
+		{
+			name: "default name from the keys",
+			spec: MongoDBIndexSpec{Keys: []IndexKey{
+				{Field: "email"},
+				{Field: "createdAt", Type: IndexKeyDescending},
+			}},
+			want: mongodb.IndexSpec{
+				Name: "email_1_createdAt_-1",
+				Keys: bson.D{{Key: "email", Value: int32(1)}, {Key: "createdAt", Value: int32(-1)}},
+			},
+		},
+		{
+			name: "options",
+			spec: MongoDBIndexSpec{
+				Name:                    "by_title",
+				Keys:                    []IndexKey{{Field: "title", Type: IndexKeyText}},
+				Unique:                  true,
+				PartialFilterExpression: &runtime.RawExtension{Raw: []byte(`{"active":true}`)},
+				Collation:               &IndexCollation{Locale: "en", Strength: 2},
+			},
+			want: mongodb.IndexSpec{
+				Name:                    "by_title",
+				Keys:                    bson.D{{Key: "title", Value: "text"}},
+				Unique:                  true,
+				PartialFilterExpression: bson.D{{Key: "active", Value: true}},
+				Collation:               &options.Collation{Locale: "en", Strength: 2},
+			},
+		},
+		{
+			name: "invalid partial filter expression",
+			spec: MongoDBIndexSpec{
+				Keys:                    []IndexKey{{Field: "email"}},
+				PartialFilterExpression: &runtime.RawExtension{Raw: []byte(`{"active":`)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoIndex := &MongoDBIndex{Spec: tt.spec}
+
+			got, err := mongoIndex.IndexSpec()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IndexSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IndexSpec() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckIndex(t *testing.T) {
+	mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{
+		Collection:         "docs",
+		AllowedCollections: []string{"app-*"},
+		DatabasePolicy:     []DatabasePolicyRule{{Namespaces: []string{"team-a"}, Databases: []string{"team-a"}}},
+	}}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		database   string
+		collection string
+		noPolicy   bool
+		wantErr    bool
+	}{
+		{
+			name:       "allowed database and collection",
+			namespace:  "team-a",
+			database:   "team-a",
+			collection: "app-users",
+		},
+		{
+			name:       "database not allowed",
+			namespace:  "team-b",
+			database:   "team-a",
+			collection: "docs",
+			wantErr:    true,
+		},
+		{
+			name:       "collection not allowed",
+			namespace:  "team-a",
+			database:   "team-a",
+			collection: "billing",
+			wantErr:    true,
+		},
+		{
+			name:       "no policy and the default database",
+			namespace:  "team-b",
+			database:   "team-b",
+			collection: "docs",
+			noPolicy:   true,
+		},
+		{
+			name:       "no policy and another database",
+			namespace:  "team-b",
+			database:   "team-a",
+			collection: "docs",
+			noPolicy:   true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoIndex := &MongoDBIndex{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace},
+				Spec:       MongoDBIndexSpec{Database: tt.database, Collection: tt.collection},
+			}
+
+			config := mongoCfg.DeepCopy()
+			if tt.noPolicy {
+				config.Spec.DatabasePolicy = nil
+			}
+
+			if err := config.CheckIndex(mongoIndex); (err != nil) != tt.wantErr {
+				t.Errorf("CheckIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateIndexField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		wantErr bool
+	}{
+		{
+			name:  "nested field",
+			field: "meta.createdAt",
+		},
+		{
+			name:    "empty field",
+			wantErr: true,
+		},
+		{
+			name:    "empty path element",
+			field:   "meta..createdAt",
+			wantErr: true,
+		},
+		{
+			name:    "operator",
+			field:   "meta.$id",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateIndexField(tt.field); (err != nil) != tt.wantErr {
+				t.Errorf("validateIndexField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// BulkDataOwnerField is the document field which records the MongoDBBulkData
+// owning the document, documents which are removed from the spec are found by it
+const BulkDataOwnerField = "_mongodbBulkData"
+
+// MongoDBBulkDataSpec defines the desired state of MongoDBBulkData
+type MongoDBBulkDataSpec struct {
+	// DB is a MongoDBConfig name
+	DB string `json:"db"`
+
+	// Database is the mongodb database of the documents
+	Database string `json:"database"`
+
+	// Collection is the mongodb collection of the documents
+	Collection string `json:"collection"`
+
+	// Key is a list of field paths which identify each document,
+	// the documents are upserted by these fields
+	// +kubebuilder:validation:MinItems=1
+	Key []string `json:"key"`
+
+	// Documents is a list of json objects, exactly one of documents or configMapRef must be set
+	// +optional
+	Documents []JSONDocument `json:"documents,omitempty"`
+
+	// ConfigMapRef selects a key of a ConfigMap in the same namespace, which holds
+	// a json array of objects or newline delimited json objects
+	// +optional
+	ConfigMapRef *ConfigMapKeyReference `json:"configMapRef,omitempty"`
+
+	// PrunePolicy defines what happens to the documents which are removed from the spec
+	// and to all of the documents when the MongoDBBulkData is deleted,
+	// the deletion policy of the MongoDBConfig is used by default
+	// +optional
+	PrunePolicy DeletionPolicy `json:"prunePolicy,omitempty"`
+
+	// SoftDelete overrides the soft deletion settings of the MongoDBConfig
+	// +optional
+	SoftDelete *SoftDeleteSpec `json:"softDelete,omitempty"`
+}
+
+// JSONDocument is a json object which accepts any fields including nested objects and arrays
+// +kubebuilder:pruning:PreserveUnknownFields
+// +kubebuilder:validation:Type=object
+type JSONDocument struct {
+	runtime.RawExtension `json:",inline"`
+}
+
+// ConfigMapKeyReference selects a key of a ConfigMap
+type ConfigMapKeyReference struct {
+	// Name of the ConfigMap
+	Name string `json:"name"`
+
+	// Key of the ConfigMap data
+	Key string `json:"key"`
+}
+
+// MongoDBBulkDataStatus defines the observed state of MongoDBBulkData
+type MongoDBBulkDataStatus struct {
+	// +kubebuilder:default="Pending"
+	State string `json:"state,omitempty"`
+
+	// Documents is the number of documents in the spec
+	Documents int64 `json:"documents,omitempty"`
+
+	// Inserted, Updated, Pruned and Failed count the documents of the last apply which changed anything
+	Inserted int64 `json:"inserted,omitempty"`
+	Updated  int64 `json:"updated,omitempty"`
+	Pruned   int64 `json:"pruned,omitempty"`
+	Failed   int64 `json:"failed,omitempty"`
+
+	// Errors are the messages of the failed documents
+	Errors []string `json:"errors,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// MongoDBBulkData is the Schema for the mongodbbulkdata API
+// +kubebuilder:printcolumn:name="Collection",type="string",JSONPath=".spec.collection",description="Collection of the documents"
+// +kubebuilder:printcolumn:name="Documents",type="integer",JSONPath=".status.documents",description="Number of documents in the spec"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed",description="Number of documents which failed in the last apply"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Current state of the MongoDBBulkData"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// +operator-sdk:csv:customresourcedefinitions:displayName="MongoDBBulkData"
+// +kubebuilder:resource:shortName=mdbb
+type MongoDBBulkData struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MongoDBBulkDataSpec   `json:"spec,omitempty"`
+	Status MongoDBBulkDataStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MongoDBBulkDataList contains a list of MongoDBBulkData
+type MongoDBBulkDataList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MongoDBBulkData `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MongoDBBulkData{}, &MongoDBBulkDataList{})
+}
+
+type MongoDBBulkDataConditionType string
+
+const (
+	MongoDBBulkDataConditionPending          MongoDBBulkDataConditionType = "Pending"
+	MongoDBBulkDataConditionApplied          MongoDBBulkDataConditionType = "Applied"
+	MongoDBBulkDataConditionPartiallyApplied MongoDBBulkDataConditionType = "PartiallyApplied"
+	MongoDBBulkDataConditionFailed           MongoDBBulkDataConditionType = "Failed"
+)
@@ -0,0 +1,205 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var (
+	mongodbbulkdatalog = logf.Log.WithName("mongodbbulkdata-resource")
+)
+
+func (r *MongoDBBulkData) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mongo-snappcloud-io-v1-mongodbbulkdata,mutating=false,failurePolicy=fail,sideEffects=None,groups=mongo.snappcloud.io,resources=mongodbbulkdata,verbs=create;update,versions=v1,name=vmongodbbulkdata.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MongoDBBulkData{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBBulkData) ValidateCreate() error {
+	mongodbbulkdatalog.Info("validate create", "name", r.ObjectMeta.Name)
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBBulkData) ValidateUpdate(old runtime.Object) error {
+	mongodbbulkdatalog.Info("validate update", "name", r.ObjectMeta.Name)
+
+	oldmdbb, ok := old.(*MongoDBBulkData)
+	if !ok {
+		return errors.New("runtime.Object should be a type of mongov1.MongoDBBulkData")
+	}
+
+	if r.Spec.DB != oldmdbb.Spec.DB {
+		return field.Forbidden(field.NewPath("spec").Child("db"), "cannot have a change on db field")
+	}
+
+	if r.Spec.Database != oldmdbb.Spec.Database {
+		return field.Forbidden(field.NewPath("spec").Child("database"), "cannot have a change on database field")
+	}
+
+	if r.Spec.Collection != oldmdbb.Spec.Collection {
+		return field.Forbidden(field.NewPath("spec").Child("collection"), "cannot have a change on collection field")
+	}
+
+	// the documents are matched by their keys, so a new key would duplicate them
+	if !reflect.DeepEqual(r.Spec.Key, oldmdbb.Spec.Key) {
+		return field.Forbidden(field.NewPath("spec").Child("key"), "cannot have a change on key field")
+	}
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBBulkData) ValidateDelete() error {
+	mongodbbulkdatalog.Info("validate delete", "name", r.ObjectMeta.Name)
+	return nil
+}
+
+func (r *MongoDBBulkData) validateSpecs() *field.Error {
+
+	if r.Spec.DB == "" {
+		return field.Invalid(field.NewPath("spec").Child("db"), r.Spec.DB, "db cannot be empty")
+	}
+
+	if err := validateDatabaseName(r.Spec.Database); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("database"), r.Spec.Database, err.Error())
+	}
+
+	if err := validateCollectionName(r.Spec.Collection); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("collection"), r.Spec.Collection, err.Error())
+	}
+
+	// Validate spec.key
+	{
+		key := field.NewPath("spec").Child("key")
+
+		if len(r.Spec.Key) == 0 {
+			return field.Required(key, "key cannot be empty")
+		}
+
+		for i, path := range r.Spec.Key {
+			if err := validateIndexField(path); err != nil {
+				return field.Invalid(key.Index(i), path, err.Error())
+			}
+		}
+	}
+
+	if (len(r.Spec.Documents) == 0) == (r.Spec.ConfigMapRef == nil) {
+		return field.Invalid(field.NewPath("spec").Child("documents"), len(r.Spec.Documents), "exactly one of documents or configMapRef must be specified")
+	}
+
+	// Validate spec.documents, the key of every document must be unique
+	{
+		key := field.NewPath("spec").Child("documents")
+
+		seen := map[string]int{}
+		for i, raw := range r.Spec.Documents {
+
+			doc, err := r.BulkDocument(i+1, raw.Raw)
+			if err != nil {
+				return field.Invalid(key.Index(i), string(raw.Raw), err.Error())
+			}
+
+			docKey, err := doc.Key()
+			if err != nil {
+				return field.Invalid(key.Index(i), string(raw.Raw), err.Error())
+			}
+
+			if first, ok := seen[docKey]; ok {
+				return field.Duplicate(key.Index(i), fmt.Sprintf("key %s of document %d", docKey, first))
+			}
+			seen[docKey] = i + 1
+		}
+	}
+
+	// Validate spec.configMapRef
+	if ref := r.Spec.ConfigMapRef; ref != nil {
+		key := field.NewPath("spec").Child("configMapRef")
+
+		if ref.Name == "" {
+			return field.Required(key.Child("name"), "name cannot be empty")
+		}
+
+		if ref.Key == "" {
+			return field.Required(key.Child("key"), "key cannot be empty")
+		}
+	}
+
+	// Validate spec.softDelete
+	if r.Spec.SoftDelete != nil {
+		if err := r.Spec.SoftDelete.validate(); err != nil {
+			key := field.NewPath("spec").Child("softDelete")
+			return field.Invalid(key, r.Spec.SoftDelete, err.Error())
+		}
+	}
+
+	// check the database policy and allowed collections of the MongoDBConfig when it exists
+	{
+		mongoCfg, err := r.getMongoDBConfig()
+		if err != nil {
+			return field.InternalError(field.NewPath("spec").Child("db"), err)
+		}
+
+		if mongoCfg != nil {
+			if err := mongoCfg.CheckBulkData(r); err != nil {
+				return field.Forbidden(field.NewPath("spec"), err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// getMongoDBConfig returns the referenced MongoDBConfig, or nil if it doesn't exist
+func (r *MongoDBBulkData) getMongoDBConfig() (*MongoDBConfig, error) {
+	if kubeClient == nil {
+		return nil, nil
+	}
+
+	mongoCfg := &MongoDBConfig{}
+	if err := kubeClient.Get(context.Background(), client.ObjectKey{Name: r.Spec.DB}, mongoCfg); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	return mongoCfg, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// CollectionDeletionPolicy defines what happens to a collection when its MongoDBCollection is deleted
+// +kubebuilder:validation:Enum=Drop;Retain
+type CollectionDeletionPolicy string
+
+const (
+	// CollectionDeletionDrop drops the collection and all of its documents
+	CollectionDeletionDrop CollectionDeletionPolicy = "Drop"
+
+	// CollectionDeletionRetain keeps the collection in mongodb
+	CollectionDeletionRetain CollectionDeletionPolicy = "Retain"
+)
+
+// MongoDBCollectionSpec defines the desired state of MongoDBCollection
+type MongoDBCollectionSpec struct {
+	// DB is a MongoDBConfig name
+	DB string `json:"db"`
+
+	// Database is the mongodb database of the collection
+	Database string `json:"database"`
+
+	// Collection is the mongodb collection name
+	Collection string `json:"collection"`
+
+	// JSONSchema is the $jsonSchema validator of the collection
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	JSONSchema *runtime.RawExtension `json:"jsonSchema,omitempty"`
+
+	// ValidationLevel defines which documents are validated
+	// +kubebuilder:validation:Enum=off;strict;moderate
+	// +optional
+	ValidationLevel string `json:"validationLevel,omitempty"`
+
+	// ValidationAction defines whether invalid documents are rejected or only logged
+	// +kubebuilder:validation:Enum=error;warn
+	// +optional
+	ValidationAction string `json:"validationAction,omitempty"`
+
+	// Capped makes a fixed size collection, it cannot be changed after creation
+	// +optional
+	Capped *CappedCollectionSpec `json:"capped,omitempty"`
+
+	// TimeSeries makes a time series collection, it cannot be changed after creation
+	// +optional
+	TimeSeries *TimeSeriesCollectionSpec `json:"timeSeries,omitempty"`
+
+	// ClusteredIndex makes a collection clustered by _id, it cannot be changed after creation
+	// +optional
+	ClusteredIndex *ClusteredIndexSpec `json:"clusteredIndex,omitempty"`
+
+	// ExpireAfterSeconds removes the documents of a time series or clustered collection
+	// the given seconds after their time field or _id
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ExpireAfterSeconds *int64 `json:"expireAfterSeconds,omitempty"`
+
+	// Collation is the default collation of the collection, it cannot be changed after creation
+	// +optional
+	Collation *IndexCollation `json:"collation,omitempty"`
+
+	// BlockDataUntilReady keeps the MongoDBData of the collection pending until the collection is Ready
+	// +optional
+	BlockDataUntilReady bool `json:"blockDataUntilReady,omitempty"`
+
+	// DeletionPolicy defines what happens to the collection when the MongoDBCollection is deleted
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy CollectionDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// CappedCollectionSpec defines a fixed size collection
+type CappedCollectionSpec struct {
+	// Size is the maximum size of the collection in bytes
+	// +kubebuilder:validation:Minimum=1
+	Size int64 `json:"size"`
+
+	// Max is the maximum number of documents of the collection
+	// +optional
+	Max int64 `json:"max,omitempty"`
+}
+
+// TimeSeriesCollectionSpec defines a time series collection
+type TimeSeriesCollectionSpec struct {
+	// TimeField is the field which holds the date of each document
+	TimeField string `json:"timeField"`
+
+	// MetaField is the field which holds the metadata of each document
+	// +optional
+	MetaField string `json:"metaField,omitempty"`
+
+	// Granularity is the expected interval between the documents
+	// +kubebuilder:validation:Enum=seconds;minutes;hours
+	// +optional
+	Granularity string `json:"granularity,omitempty"`
+}
+
+// ClusteredIndexSpec defines the clustered index of a collection
+type ClusteredIndexSpec struct {
+	// Name of the clustered index
+	// +kubebuilder:default="_id_"
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// MongoDBCollectionStatus defines the observed state of MongoDBCollection
+type MongoDBCollectionStatus struct {
+	// +kubebuilder:default="Pending"
+	State string `json:"state,omitempty"`
+
+	// Created reports whether the collection has been created by the MongoDBCollection,
+	// a collection which already existed is never dropped
+	Created bool `json:"created,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// MongoDBCollection is the Schema for the mongodbcollections API
+// +kubebuilder:printcolumn:name="Database",type="string",JSONPath=".spec.database",description="Database of the collection"
+// +kubebuilder:printcolumn:name="Collection",type="string",JSONPath=".spec.collection",description="Name of the collection"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Current state of the MongoDBCollection"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// +operator-sdk:csv:customresourcedefinitions:displayName="MongoDBCollection"
+// +kubebuilder:resource:shortName=mdbc
+type MongoDBCollection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MongoDBCollectionSpec   `json:"spec,omitempty"`
+	Status MongoDBCollectionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MongoDBCollectionList contains a list of MongoDBCollection
+type MongoDBCollectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MongoDBCollection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MongoDBCollection{}, &MongoDBCollectionList{})
+}
+
+type MongoDBCollectionConditionType string
+
+const (
+	MongoDBCollectionConditionPending MongoDBCollectionConditionType = "Pending"
+	MongoDBCollectionConditionReady   MongoDBCollectionConditionType = "Ready"
+	MongoDBCollectionConditionFailed  MongoDBCollectionConditionType = "Failed"
+
+	// MongoDBCollectionConditionImmutableDrift is true when the options which can only be set
+	// on creation differ from the spec, the collection has to be recreated to apply them
+	MongoDBCollectionConditionImmutableDrift MongoDBCollectionConditionType = "ImmutableDrift"
+)
@@ -0,0 +1,195 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// log is for logging in this package.
+var (
+	mongodbcollectionlog = logf.Log.WithName("mongodbcollection-resource")
+)
+
+func (r *MongoDBCollection) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mongo-snappcloud-io-v1-mongodbcollection,mutating=false,failurePolicy=fail,sideEffects=None,groups=mongo.snappcloud.io,resources=mongodbcollections,verbs=create;update,versions=v1,name=vmongodbcollection.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MongoDBCollection{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBCollection) ValidateCreate() error {
+	mongodbcollectionlog.Info("validate create", "name", r.ObjectMeta.Name)
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBCollection) ValidateUpdate(old runtime.Object) error {
+	mongodbcollectionlog.Info("validate update", "name", r.ObjectMeta.Name)
+
+	oldmdbc, ok := old.(*MongoDBCollection)
+	if !ok {
+		return errors.New("runtime.Object should be a type of mongov1.MongoDBCollection")
+	}
+
+	if r.Spec.DB != oldmdbc.Spec.DB {
+		return field.Forbidden(field.NewPath("spec").Child("db"), "cannot have a change on db field")
+	}
+
+	if r.Spec.Database != oldmdbc.Spec.Database {
+		return field.Forbidden(field.NewPath("spec").Child("database"), "cannot have a change on database field")
+	}
+
+	if r.Spec.Collection != oldmdbc.Spec.Collection {
+		return field.Forbidden(field.NewPath("spec").Child("collection"), "cannot have a change on collection field")
+	}
+
+	// these options can only be set when the collection is created
+	if !reflect.DeepEqual(r.Spec.Capped, oldmdbc.Spec.Capped) {
+		return field.Forbidden(field.NewPath("spec").Child("capped"), "cannot have a change on capped field")
+	}
+
+	if !reflect.DeepEqual(r.Spec.TimeSeries, oldmdbc.Spec.TimeSeries) {
+		return field.Forbidden(field.NewPath("spec").Child("timeSeries"), "cannot have a change on timeSeries field")
+	}
+
+	if !reflect.DeepEqual(r.Spec.ClusteredIndex, oldmdbc.Spec.ClusteredIndex) {
+		return field.Forbidden(field.NewPath("spec").Child("clusteredIndex"), "cannot have a change on clusteredIndex field")
+	}
+
+	if !reflect.DeepEqual(r.Spec.Collation, oldmdbc.Spec.Collation) {
+		return field.Forbidden(field.NewPath("spec").Child("collation"), "cannot have a change on collation field")
+	}
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBCollection) ValidateDelete() error {
+	mongodbcollectionlog.Info("validate delete", "name", r.ObjectMeta.Name)
+	return nil
+}
+
+func (r *MongoDBCollection) validateSpecs() *field.Error {
+
+	if r.Spec.DB == "" {
+		return field.Invalid(field.NewPath("spec").Child("db"), r.Spec.DB, "db cannot be empty")
+	}
+
+	if err := validateDatabaseName(r.Spec.Database); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("database"), r.Spec.Database, err.Error())
+	}
+
+	if err := validateCollectionName(r.Spec.Collection); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("collection"), r.Spec.Collection, err.Error())
+	}
+
+	// Validate spec.jsonSchema
+	if schema := r.Spec.JSONSchema; schema != nil {
+		key := field.NewPath("spec").Child("jsonSchema")
+
+		if r.Spec.TimeSeries != nil {
+			return field.Invalid(key, string(schema.Raw), "time series collections don't support validators")
+		}
+
+		if _, err := mongodb.NewFilter(schema.Raw); err != nil {
+			return field.Invalid(key, string(schema.Raw), err.Error())
+		}
+	}
+
+	// Validate spec.capped
+	if r.Spec.Capped != nil {
+		key := field.NewPath("spec").Child("capped")
+
+		if r.Spec.TimeSeries != nil || r.Spec.ClusteredIndex != nil {
+			return field.Invalid(key, r.Spec.Capped, "capped cannot be used with timeSeries or clusteredIndex")
+		}
+	}
+
+	// Validate spec.timeSeries
+	if r.Spec.TimeSeries != nil {
+		key := field.NewPath("spec").Child("timeSeries")
+
+		if r.Spec.ClusteredIndex != nil {
+			return field.Invalid(key, r.Spec.TimeSeries, "timeSeries cannot be used with clusteredIndex")
+		}
+
+		if r.Spec.TimeSeries.TimeField == "" {
+			return field.Required(key.Child("timeField"), "timeField cannot be empty")
+		}
+	}
+
+	// Validate spec.expireAfterSeconds
+	if r.Spec.ExpireAfterSeconds != nil && r.Spec.TimeSeries == nil && r.Spec.ClusteredIndex == nil {
+		key := field.NewPath("spec").Child("expireAfterSeconds")
+		return field.Invalid(key, *r.Spec.ExpireAfterSeconds, "expireAfterSeconds needs timeSeries or clusteredIndex")
+	}
+
+	// check the database policy and allowed collections of the MongoDBConfig when it exists
+	{
+		mongoCfg, err := r.getMongoDBConfig()
+		if err != nil {
+			return field.InternalError(field.NewPath("spec").Child("db"), err)
+		}
+
+		if mongoCfg != nil {
+			if err := mongoCfg.CheckCollection(r); err != nil {
+				return field.Forbidden(field.NewPath("spec"), err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// getMongoDBConfig returns the referenced MongoDBConfig, or nil if it doesn't exist
+func (r *MongoDBCollection) getMongoDBConfig() (*MongoDBConfig, error) {
+	if kubeClient == nil {
+		return nil, nil
+	}
+
+	mongoCfg := &MongoDBConfig{}
+	if err := kubeClient.Get(context.Background(), client.ObjectKey{Name: r.Spec.DB}, mongoCfg); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	return mongoCfg, nil
+}
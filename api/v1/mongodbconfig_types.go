@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,19 +28,182 @@ import (
 // MongoDBConfigSpec defines the desired state of MongoDBConfig
 type MongoDBConfigSpec struct {
 	// MongoURL is a mongodb connection url
-	MongoURL string `json:"mongourl"`
+	// +optional
+	MongoURL string `json:"mongourl,omitempty"`
+
+	// MongoURLSecretRef is a reference to a secret key holding the mongodb connection url,
+	// it can be used instead of mongourl
+	// +optional
+	MongoURLSecretRef *SecretKeyReference `json:"mongoURLSecretRef,omitempty"`
+
+	// CredentialsSecretRef is a reference to a secret holding the mongodb username and password,
+	// the credentials are injected into the connection url
+	// +optional
+	CredentialsSecretRef *CredentialsSecretReference `json:"credentialsSecretRef,omitempty"`
+
+	// DeletionPolicy is the default deletion policy of the MongoDBData documents
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// SoftDelete defines how documents are marked as deleted by the SoftDelete policy
+	// +optional
+	SoftDelete *SoftDeleteSpec `json:"softDelete,omitempty"`
+
+	// ResyncInterval is the default interval in which MongoDBData documents are re-read
+	// and drifts from their spec are corrected, resync is disabled when it's not set
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// TLS enables tls for the mongodb connection
+	// +optional
+	TLS *MongoDBTLSConfig `json:"tls,omitempty"`
+
+	// ChangeStream watches the managed collections and reconciles the MongoDBData
+	// documents which have been changed in mongodb, it needs a replica set
+	// +optional
+	ChangeStream *ChangeStreamSpec `json:"changeStream,omitempty"`
+
+	// Encryption enables client-side field level encryption of the
+	// spec.encryptedFields of the MongoDBData documents
+	// +optional
+	Encryption *EncryptionSpec `json:"encryption,omitempty"`
+
+	// Collection is a mongodb collection name, it is the default
+	// collection of the MongoDBData documents
+	// +optional
+	Collection string `json:"collection,omitempty"`
+
+	// AllowedCollections is a list of collection names or glob patterns
+	// which MongoDBData documents are allowed to choose with spec.collection
+	// +optional
+	AllowedCollections []string `json:"allowedCollections,omitempty"`
+
+	// Database defines the target database of the MongoDBData documents,
+	// the namespace of the MongoDBData is used by default
+	// +optional
+	Database *MongoDBDatabaseSpec `json:"database,omitempty"`
+
+	// DatabasePolicy limits the databases which namespaces are allowed to reach,
+	// nothing but the database chosen by the MongoDBConfig is allowed when there are no rules
+	// and the admin, local and config databases are never allowed
+	// +optional
+	DatabasePolicy []DatabasePolicyRule `json:"databasePolicy,omitempty"`
+
+	// Suspend stops all writes to the MongoDBData documents of the MongoDBConfig,
+	// their drifts are still reported and nothing is deleted until it's resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// SecretKeyReference is a reference to a key of a secret
+type SecretKeyReference struct {
+	// Namespace of the secret
+	Namespace string `json:"namespace"`
+
+	// Name of the secret
+	Name string `json:"name"`
+
+	// Key of the secret data
+	Key string `json:"key"`
+}
+
+// CredentialsSecretReference is a reference to a secret holding mongodb credentials
+type CredentialsSecretReference struct {
+	// Namespace of the secret
+	Namespace string `json:"namespace"`
+
+	// Name of the secret
+	Name string `json:"name"`
+
+	// UsernameKey is the key of the username in secret data
+	// +kubebuilder:default="username"
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// PasswordKey is the key of the password in secret data
+	// +kubebuilder:default="password"
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// SecretReference is a reference to a secret
+type SecretReference struct {
+	// Namespace of the secret
+	Namespace string `json:"namespace"`
 
-	// Collection is a mongodb collection name
-	Collection string `json:"collection"`
+	// Name of the secret
+	Name string `json:"name"`
+}
+
+// EncryptionSpec defines the keys of client-side field level encryption
+type EncryptionSpec struct {
+	// MasterKeySecretRef is a reference to a secret key holding the 96 byte local
+	// master key, raw or base64 encoded, which encrypts the data key
+	MasterKeySecretRef SecretKeyReference `json:"masterKeySecretRef"`
+
+	// KeyVaultNamespace is the database.collection of the data keys
+	// +kubebuilder:default="encryption.__keyVault"
+	// +optional
+	KeyVaultNamespace string `json:"keyVaultNamespace,omitempty"`
+
+	// KeyAltName is the alternate name of the data key, it's created when it doesn't exist
+	// +kubebuilder:default="mongodb-data-operator"
+	// +optional
+	KeyAltName string `json:"keyAltName,omitempty"`
+}
+
+// MongoDBTLSConfig defines the tls settings of a mongodb connection
+type MongoDBTLSConfig struct {
+	// CASecretRef is a reference to a secret key holding the pem encoded ca certificate
+	// used to verify the mongodb server
+	// +optional
+	CASecretRef *SecretKeyReference `json:"caSecretRef,omitempty"`
+
+	// ClientCertSecretRef is a reference to a kubernetes.io/tls secret holding the client
+	// certificate in tls.crt and tls.key, it is used for x509 authentication when
+	// there are no other credentials
+	// +optional
+	ClientCertSecretRef *SecretReference `json:"clientCertSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the server certificate
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// ServerName is used to verify the hostname of the server certificate
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// ChangeStreamSpec defines the change stream of a MongoDBConfig
+type ChangeStreamSpec struct {
+	// Enabled starts the change stream
+	Enabled bool `json:"enabled"`
+
+	// ResumeTokenDatabase is the database of the resume token collection
+	// +kubebuilder:default="mongodb_data_operator"
+	// +optional
+	ResumeTokenDatabase string `json:"resumeTokenDatabase,omitempty"`
+
+	// ResumeTokenCollection is the collection where the resume token is persisted,
+	// so no change is missed when the operator restarts
+	// +kubebuilder:default="resume_tokens"
+	// +optional
+	ResumeTokenCollection string `json:"resumeTokenCollection,omitempty"`
 }
 
 // MongoDBConfigStatus defines the observed state of MongoDBConfig
 type MongoDBConfigStatus struct {
-	Ready      string             `json:"ready,omitempty"`
+	Ready string `json:"ready,omitempty"`
+
+	// LastSyncNow is the last handled value of the sync-now annotation
+	LastSyncNow string `json:"lastSyncNow,omitempty"`
+
 	Conditions []metav1.Condition `json:"conditions"`
 }
 
 // +kubebuilder:printcolumn:name="READY",type=string,JSONPath=`.status.ready`,description=`Current state of the MongoDBConfig`
+// +kubebuilder:printcolumn:name="SUSPENDED",type=boolean,JSONPath=`.spec.suspend`,description=`Whether writes to the documents are suspended`
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
@@ -70,6 +235,57 @@ type MongoDBConfigConditionType string
 const (
 	Ready               MongoDBConfigConditionType = "Ready"
 	NoMongoURLSpecified MongoDBConfigConditionType = "NoMongoURLSpecified"
+	SecretError         MongoDBConfigConditionType = "SecretError"
+	HandshakeError      MongoDBConfigConditionType = "HandshakeError"
+	AuthError           MongoDBConfigConditionType = "AuthError"
 	ConnectError        MongoDBConfigConditionType = "ConnectError"
 	Terminating         MongoDBConfigConditionType = "Terminating"
+	Suspended           MongoDBConfigConditionType = "Suspended"
+
+	// EncryptionUnsupported is true when spec.encryption is set and the operator is built without it
+	EncryptionUnsupported MongoDBConfigConditionType = "EncryptionUnsupported"
 )
+
+// ChangeStreamEnabled reports whether the change stream of the MongoDBConfig is enabled
+func (r *MongoDBConfig) ChangeStreamEnabled() bool {
+	return r.Spec.ChangeStream != nil && r.Spec.ChangeStream.Enabled
+}
+
+// EncryptionEnabled reports whether client-side field level encryption of the MongoDBConfig is enabled
+func (r *MongoDBConfig) EncryptionEnabled() bool {
+	return r.Spec.Encryption != nil
+}
+
+// ResyncIntervalFor returns the resync interval of the given MongoDBData,
+// the resync interval of the MongoDBConfig is used when it is not set
+func (r *MongoDBConfig) ResyncIntervalFor(mongoData *MongoDBData) time.Duration {
+	if mongoData.Spec.ResyncInterval != nil {
+		return mongoData.Spec.ResyncInterval.Duration
+	}
+	if r.Spec.ResyncInterval != nil {
+		return r.Spec.ResyncInterval.Duration
+	}
+	return 0
+}
+
+// ReferencesSecret reports whether the MongoDBConfig is referencing the given secret
+func (r *MongoDBConfig) ReferencesSecret(namespace, name string) bool {
+	if ref := r.Spec.MongoURLSecretRef; ref != nil && ref.Namespace == namespace && ref.Name == name {
+		return true
+	}
+	if ref := r.Spec.CredentialsSecretRef; ref != nil && ref.Namespace == namespace && ref.Name == name {
+		return true
+	}
+	if tls := r.Spec.TLS; tls != nil {
+		if ref := tls.CASecretRef; ref != nil && ref.Namespace == namespace && ref.Name == name {
+			return true
+		}
+		if ref := tls.ClientCertSecretRef; ref != nil && ref.Namespace == namespace && ref.Name == name {
+			return true
+		}
+	}
+	if enc := r.Spec.Encryption; enc != nil && enc.MasterKeySecretRef.Namespace == namespace && enc.MasterKeySecretRef.Name == name {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResyncIntervalFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *metav1.Duration
+		data   *metav1.Duration
+		want   time.Duration
+	}{
+		{
+			name: "disabled by default",
+			want: 0,
+		},
+		{
+			name:   "interval of the config",
+			config: &metav1.Duration{Duration: time.Minute},
+			want:   time.Minute,
+		},
+		{
+			name:   "interval of the MongoDBData wins",
+			config: &metav1.Duration{Duration: time.Minute},
+			data:   &metav1.Duration{Duration: time.Hour},
+			want:   time.Hour,
+		},
+		{
+			name:   "zero interval of the MongoDBData disables the resync",
+			config: &metav1.Duration{Duration: time.Minute},
+			data:   &metav1.Duration{},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &MongoDBConfig{Spec: MongoDBConfigSpec{ResyncInterval: tt.config}}
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{ResyncInterval: tt.data}}
+
+			if got := mongoCfg.ResyncIntervalFor(mongoData); got != tt.want {
+				t.Errorf("ResyncIntervalFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
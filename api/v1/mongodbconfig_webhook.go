@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -112,23 +113,133 @@ func (r *MongoDBConfig) validateSpecs() *field.Error {
 		key := field.NewPath("spec").Child("collection")
 		value := r.Spec.Collection
 
-		if value == "" {
-			return field.Invalid(key, value, "collection must be specified")
+		if value == "" && len(r.Spec.AllowedCollections) == 0 {
+			return field.Invalid(key, value, "one of collection or allowedCollections must be specified")
+		}
+
+		if value != "" {
+			if err := validateCollectionName(value); err != nil {
+				return field.Invalid(key, value, err.Error())
+			}
+		}
+	}
+
+	{
+		key := field.NewPath("spec").Child("allowedCollections")
+		value := r.Spec.AllowedCollections
+
+		if err := validatePatterns(value); err != nil {
+			return field.Invalid(key, value, err.Error())
 		}
 	}
 
 	{
 		key := field.NewPath("spec").Child("mongourl")
 		value := r.Spec.MongoURL
-		if value == "" {
-			return field.Invalid(key, value, "mongourl must be specified")
+
+		if value == "" && r.Spec.MongoURLSecretRef == nil {
+			return field.Invalid(key, value, "one of mongourl or mongoURLSecretRef must be specified")
+		}
+
+		if value != "" && r.Spec.MongoURLSecretRef != nil {
+			return field.Invalid(key, value, "only one of mongourl or mongoURLSecretRef can be specified")
 		}
 
-		if !r.isValidMongoURL(value) {
+		if value != "" && !r.isValidMongoURL(value) {
 			return field.Invalid(key, value, "mongourl must be a valid connection string url")
 		}
 	}
 
+	if ref := r.Spec.MongoURLSecretRef; ref != nil {
+		key := field.NewPath("spec").Child("mongoURLSecretRef")
+
+		if err := validateSecretName(key, ref.Namespace, ref.Name); err != nil {
+			return err
+		}
+
+		if ref.Key == "" {
+			return field.Required(key.Child("key"), "key must be specified")
+		}
+	}
+
+	if ref := r.Spec.CredentialsSecretRef; ref != nil {
+		key := field.NewPath("spec").Child("credentialsSecretRef")
+
+		if err := validateSecretName(key, ref.Namespace, ref.Name); err != nil {
+			return err
+		}
+	}
+
+	// Validate spec.softDelete
+	if r.Spec.SoftDelete != nil {
+		if err := r.Spec.SoftDelete.validate(); err != nil {
+			key := field.NewPath("spec").Child("softDelete")
+			return field.Invalid(key, r.Spec.SoftDelete, err.Error())
+		}
+	}
+
+	if err := r.Spec.Database.validate(); err != nil {
+		key := field.NewPath("spec").Child("database")
+		return field.Invalid(key, r.Spec.Database, err.Error())
+	}
+
+	for i, rule := range r.Spec.DatabasePolicy {
+		key := field.NewPath("spec").Child("databasePolicy").Index(i)
+
+		if len(rule.Databases) == 0 {
+			return field.Required(key.Child("databases"), "databases must be specified")
+		}
+
+		if err := validatePatterns(rule.Namespaces); err != nil {
+			return field.Invalid(key.Child("namespaces"), rule.Namespaces, err.Error())
+		}
+
+		if err := validatePatterns(rule.Databases); err != nil {
+			return field.Invalid(key.Child("databases"), rule.Databases, err.Error())
+		}
+	}
+
+	if tls := r.Spec.TLS; tls != nil {
+		key := field.NewPath("spec").Child("tls")
+
+		if ref := tls.CASecretRef; ref != nil {
+
+			if err := validateSecretName(key.Child("caSecretRef"), ref.Namespace, ref.Name); err != nil {
+				return err
+			}
+
+			if ref.Key == "" {
+				return field.Required(key.Child("caSecretRef").Child("key"), "key must be specified")
+			}
+		}
+
+		if ref := tls.ClientCertSecretRef; ref != nil {
+			if err := validateSecretName(key.Child("clientCertSecretRef"), ref.Namespace, ref.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if enc := r.Spec.Encryption; enc != nil {
+		key := field.NewPath("spec").Child("encryption")
+		ref := enc.MasterKeySecretRef
+
+		if err := validateSecretName(key.Child("masterKeySecretRef"), ref.Namespace, ref.Name); err != nil {
+			return err
+		}
+
+		if ref.Key == "" {
+			return field.Required(key.Child("masterKeySecretRef").Child("key"), "key must be specified")
+		}
+
+		if ns := enc.KeyVaultNamespace; ns != "" {
+			dbName, collName, ok := strings.Cut(ns, ".")
+			if !ok || validateDatabaseName(dbName) != nil || validateCollectionName(collName) != nil {
+				return field.Invalid(key.Child("keyVaultNamespace"), ns, "keyVaultNamespace must be in database.collection form")
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -136,3 +247,13 @@ func (r *MongoDBConfig) isValidMongoURL(mongoURL string) bool {
 	re := regexp.MustCompile(mongoURLRegexPattern)
 	return len(re.FindStringIndex(mongoURL)) > 0
 }
+
+func validateSecretName(key *field.Path, namespace, name string) *field.Error {
+	if namespace == "" {
+		return field.Required(key.Child("namespace"), "namespace must be specified")
+	}
+	if name == "" {
+		return field.Required(key.Child("name"), "name must be specified")
+	}
+	return nil
+}
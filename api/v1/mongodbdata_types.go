@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -28,22 +29,197 @@ type MongoDBDataSpec struct {
 	// DB is a MongoDBConfig name
 	DB string `json:"db,omitempty"`
 
-	// Data is a MongodDB insertation data to a collection
-	Data MongoDBDataField `json:"data,omitempty"`
+	// Data is a MongodDB insertation data to a collection, it accepts any
+	// json object including nested objects and arrays. Values in extended json v2
+	// like {"$date": ...}, {"$oid": ...} or {"$numberDecimal": ...} are stored as their bson types
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Data runtime.RawExtension `json:"data,omitempty"`
+
+	// DataFrom merges the json or yaml of ConfigMap or Secret keys into the document,
+	// the sources are merged in order after spec.data
+	// +optional
+	DataFrom []MongoDBDataFrom `json:"dataFrom,omitempty"`
+
+	// SecretFields sets document fields to the values of Secret keys, the values
+	// are resolved on every reconcile and never written to the status or events
+	// +optional
+	SecretFields []MongoDBSecretField `json:"secretFields,omitempty"`
+
+	// References set document fields to the ObjectIDs of other MongoDBData, the document
+	// stays Pending until the referenced documents are inserted
+	// +optional
+	References []MongoDBDataReference `json:"references,omitempty"`
+
+	// EncryptedFields are encrypted on the client before the document is written,
+	// the encryption of the MongoDBConfig must be enabled
+	// +optional
+	EncryptedFields []MongoDBEncryptedField `json:"encryptedFields,omitempty"`
+
+	// Database overrides the target database of the MongoDBConfig,
+	// the overridden database must be allowed by the database policy of the MongoDBConfig
+	// +optional
+	Database *MongoDBDatabaseSpec `json:"database,omitempty"`
+
+	// Key is a list of field paths in data which identify the document,
+	// the document is upserted by these fields instead of being inserted
+	// +optional
+	Key []string `json:"key,omitempty"`
+
+	// Adopt takes ownership of an existing document instead of inserting a new one
+	// +optional
+	Adopt *MongoDBDataAdopt `json:"adopt,omitempty"`
+
+	// DeletionPolicy defines what happens to the document when the MongoDBData is deleted,
+	// the deletion policy of the MongoDBConfig is used by default
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// SoftDelete overrides the soft deletion settings of the MongoDBConfig
+	// +optional
+	SoftDelete *SoftDeleteSpec `json:"softDelete,omitempty"`
+
+	// Concurrency guards the updates of the document with a version field, so writes
+	// of other clients between a read and an update are not silently overwritten
+	// +optional
+	Concurrency *ConcurrencySpec `json:"concurrency,omitempty"`
+
+	// Suspend stops all writes to the document, drifts are still reported in the status
+	// and the document is not deleted until the MongoDBData is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// RevisionHistoryLimit is the number of applied document revisions which are kept
+	// for rollbacks, no history is kept when it's 0
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ResyncInterval overrides the resync interval of the MongoDBConfig,
+	// a zero interval disables the resync
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// Collection overrides the default collection of the MongoDBConfig,
+	// it must be allowed by the allowedCollections of the MongoDBConfig
+	// +optional
+	Collection string `json:"collection,omitempty"`
+}
+
+// MongoDBDataFrom is a source of document data, exactly one of configMapKeyRef or secretKeyRef must be set
+type MongoDBDataFrom struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap in the same namespace
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeyReference `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef selects a key of a Secret in the same namespace
+	// +optional
+	SecretKeyRef *LocalSecretKeyReference `json:"secretKeyRef,omitempty"`
+
+	// Path is a dot separated field path where the data is merged,
+	// the data must be an object when it's merged into the root of the document
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// MongoDBSecretField sets a document field to the value of a Secret key
+type MongoDBSecretField struct {
+	// Path is a dot separated field path of the document
+	Path string `json:"path"`
+
+	// SecretKeyRef selects a key of a Secret in the same namespace,
+	// its value is set on the field as a string
+	SecretKeyRef LocalSecretKeyReference `json:"secretKeyRef"`
+}
+
+// MongoDBDataReference sets a document field to the ObjectID of another MongoDBData
+type MongoDBDataReference struct {
+	// Path is a dot separated field path of the document
+	Path string `json:"path"`
+
+	// Namespace of the referenced MongoDBData, the namespace of the resource is used when it's not set
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the referenced MongoDBData
+	Name string `json:"name"`
+}
+
+// ConflictPolicy defines what happens when the document has been written by another client
+// +kubebuilder:validation:Enum=Overwrite;Skip;Fail
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyOverwrite updates the document anyway
+	ConflictPolicyOverwrite ConflictPolicy = "Overwrite"
+
+	// ConflictPolicySkip leaves the document as it is until the spec changes
+	ConflictPolicySkip ConflictPolicy = "Skip"
+
+	// ConflictPolicyFail leaves the document as it is and fails the reconcile until
+	// the document matches the spec again or the policy is changed
+	ConflictPolicyFail ConflictPolicy = "Fail"
+)
+
+// ConcurrencySpec defines the optimistic concurrency guard of a document, the operator
+// increments the version field on every write and only updates the document when the
+// field still has the version it has read
+type ConcurrencySpec struct {
+	// VersionField is the dot separated path of the version counter,
+	// other clients must increment it whenever they change the document
+	// +kubebuilder:default="_version"
+	// +optional
+	VersionField string `json:"versionField,omitempty"`
+
+	// ConflictPolicy defines what happens when the document has been written by another client
+	// +kubebuilder:default=Fail
+	// +optional
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
 }
 
-type MongoDBDataField struct {
-	// +kubebuilder:default="mamad"
-	// +kubebuilder:validation:Optional
-	Firstname string `json:"firstname,omitempty"`
+// EncryptionAlgorithm is the client-side encryption algorithm of a field
+// +kubebuilder:validation:Enum=Deterministic;Random
+type EncryptionAlgorithm string
 
-	// +kubebuilder:validation:Required
-	Lastname string `json:"lastname"`
+const (
+	// EncryptionDeterministic always produces the same ciphertext for a value,
+	// so the field can be queried by equality and used in spec.key
+	EncryptionDeterministic EncryptionAlgorithm = "Deterministic"
+
+	// EncryptionRandom produces a different ciphertext every time, the field can't be queried
+	EncryptionRandom EncryptionAlgorithm = "Random"
+)
 
-	Email string `json:"email,omitempty"`
+// MongoDBEncryptedField is a document field which is encrypted on the client
+type MongoDBEncryptedField struct {
+	// Path is a dot separated field path of the document
+	Path string `json:"path"`
 
-	// +kubebuilder:validation:Maximum=1000
-	Age uint16 `json:"age,omitempty"`
+	// Algorithm of the encryption
+	// +kubebuilder:default=Deterministic
+	// +optional
+	Algorithm EncryptionAlgorithm `json:"algorithm,omitempty"`
+}
+
+// LocalSecretKeyReference selects a key of a Secret in the namespace of the resource
+type LocalSecretKeyReference struct {
+	// Name of the Secret
+	Name string `json:"name"`
+
+	// Key of the Secret data
+	Key string `json:"key"`
+}
+
+// MongoDBDataAdopt selects an existing document, exactly one of objectID or filter must be set
+type MongoDBDataAdopt struct {
+	// ObjectID is the hex encoded _id of the document
+	// +optional
+	ObjectID string `json:"objectID,omitempty"`
+
+	// Filter is a mongodb query in extended json which must match exactly one document
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Filter *runtime.RawExtension `json:"filter,omitempty"`
 }
 
 // MongoDBDataStatus defines the observed state of MongoDBData
@@ -52,14 +228,69 @@ type MongoDBDataStatus struct {
 	State string `json:"state,omitempty"`
 
 	// mongodb record ObjectID
-	ObjectID   string             `json:"object_id,omitempty"`
+	ObjectID string `json:"object_id,omitempty"`
+
+	// Database is the mongodb database of the inserted document
+	Database string `json:"database,omitempty"`
+
+	// Collection is the mongodb collection of the inserted document
+	Collection string `json:"collection,omitempty"`
+
+	// AppliedGeneration is the generation of the MongoDBData which has last been written to mongodb
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+
+	// DataFromHash is the hash of the last applied document which has been merged with spec.dataFrom and spec.references
+	DataFromHash string `json:"dataFromHash,omitempty"`
+
+	// ManagedFields are the dot separated paths of the document fields which are managed by the operator,
+	// only these fields are compared and updated and they are unset when they are removed from the spec
+	ManagedFields []string `json:"managedFields,omitempty"`
+
+	// DocumentVersion is the value of the version field of spec.concurrency in the last write
+	DocumentVersion int64 `json:"documentVersion,omitempty"`
+
+	// Revision is the latest revision of the document in its revision history
+	Revision int64 `json:"revision,omitempty"`
+
+	// RolledBackTo is the revision the document is held at by the rollback annotation
+	RolledBackTo int64 `json:"rolledBackTo,omitempty"`
+
+	// SecretFieldsVersion is the resource versions of the Secrets of the last applied spec.secretFields
+	SecretFieldsVersion string `json:"secretFieldsVersion,omitempty"`
+
+	// LastSyncNow is the last handled value of the sync-now annotation
+	LastSyncNow string `json:"lastSyncNow,omitempty"`
+
+	// LastConfigSyncNow is the last handled sync-now value of the MongoDBConfig
+	LastConfigSyncNow string `json:"lastConfigSyncNow,omitempty"`
+
+	// PlannedWrite is the write which the operator would make to the document in dry-run mode
+	PlannedWrite *MongoDBDataWrite `json:"plannedWrite,omitempty"`
+
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// MongoDBDataWrite is a write to mongodb which is computed but not executed in dry-run mode
+type MongoDBDataWrite struct {
+	// Operation is Insert, Upsert, Update, Delete or SoftDelete
+	Operation string `json:"operation"`
+
+	// Filter is the relaxed extended json of the filter which selects the document
+	// +optional
+	Filter string `json:"filter,omitempty"`
+
+	// Document is the relaxed extended json of the inserted document or of the update,
+	// the values of spec.secretFields are redacted
+	// +optional
+	Document string `json:"document,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // MongoDBData is the Schema for the mongodbdata API
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Current state of the MongoDBData"
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".status.revision",description="Latest revision of the document"
+// +kubebuilder:printcolumn:name="Suspended",type="boolean",JSONPath=".spec.suspend",description="Whether writes to the document are suspended"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
 // +operator-sdk:csv:customresourcedefinitions:displayName="MongoDBData"
 // +kubebuilder:resource:shortName=mdbd
@@ -86,9 +317,15 @@ func init() {
 
 type MongoDBDataConditionType string
 
+// The Conflict condition is true while the document isn't written because its key
+// matches other documents or another client has written a newer version of it
 const (
-	MongoDBDataConditionPending  MongoDBDataConditionType = "Pending"
-	MongoDBDataConditionInserted MongoDBDataConditionType = "Inserted"
-	MongoDBDataConditionDeleting MongoDBDataConditionType = "Deleting"
-	MongoDBDataConditionFailed   MongoDBDataConditionType = "Failed"
+	MongoDBDataConditionPending        MongoDBDataConditionType = "Pending"
+	MongoDBDataConditionInserted       MongoDBDataConditionType = "Inserted"
+	MongoDBDataConditionDeleting       MongoDBDataConditionType = "Deleting"
+	MongoDBDataConditionFailed         MongoDBDataConditionType = "Failed"
+	MongoDBDataConditionConflict       MongoDBDataConditionType = "Conflict"
+	MongoDBDataConditionAdoptionFailed MongoDBDataConditionType = "AdoptionFailed"
+	MongoDBDataConditionDrifted        MongoDBDataConditionType = "Drifted"
+	MongoDBDataConditionSuspended      MongoDBDataConditionType = "Suspended"
 )
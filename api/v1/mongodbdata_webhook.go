@@ -17,21 +17,27 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"errors"
-	"regexp"
+	"fmt"
+	"reflect"
+	"strings"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
 )
 
 // log is for logging in this package.
 var (
-	emailRegexPattern = `^[\w-\.]+@([\w-]+\.)+[\w-]{2,4}$`
-	mongodbdatalog    = logf.Log.WithName("mongodbdata-resource")
+	mongodbdatalog = logf.Log.WithName("mongodbdata-resource")
 )
 
 func (r *MongoDBData) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -55,7 +61,7 @@ func (r *MongoDBData) ValidateCreate() error {
 		return newError(r.ObjectMeta.Name, err)
 	}
 
-	if err := r.validateSpecs(); err != nil {
+	if err := r.validateSpecs(true); err != nil {
 		return newError(r.ObjectMeta.Name, err)
 	}
 
@@ -78,11 +84,26 @@ func (r *MongoDBData) ValidateUpdate(old runtime.Object) error {
 		return errors.New("runtime.Object should be a type of mongov1.MongoDBData")
 	}
 
+	// the finalizer must always be removable, whatever the state of the sources and the MongoDBConfig
+	if !r.ObjectMeta.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
 	if value != oldmdbd.Spec.DB {
 		return field.Forbidden(key, "cannot have a change on db field")
 	}
 
-	if err := r.validateSpecs(); err != nil {
+	if !reflect.DeepEqual(r.Spec.Database, oldmdbd.Spec.Database) {
+		return field.Forbidden(field.NewPath("spec").Child("database"), "cannot have a change on database field")
+	}
+
+	if r.Spec.Collection != oldmdbd.Spec.Collection {
+		return field.Forbidden(field.NewPath("spec").Child("collection"), "cannot have a change on collection field")
+	}
+
+	// the sources and the MongoDBConfig are only read when the spec changes,
+	// so updates of the metadata don't depend on them
+	if err := r.validateSpecs(!reflect.DeepEqual(r.Spec, oldmdbd.Spec)); err != nil {
 		return newError(r.ObjectMeta.Name, err)
 	}
 
@@ -95,34 +116,234 @@ func (r *MongoDBData) ValidateDelete() error {
 	return nil
 }
 
-func (r *MongoDBData) validateSpecs() *field.Error {
+// validateSpecs checks the spec, the sources of spec.dataFrom and the MongoDBConfig
+// are only read when external is set
+func (r *MongoDBData) validateSpecs(external bool) *field.Error {
+
+	// Validate the rollback annotation
+	if _, err := r.RollbackRevision(); err != nil {
+		key := field.NewPath("metadata").Child("annotations").Key(RollbackAnnotation)
+		return field.Invalid(key, r.Annotations[RollbackAnnotation], err.Error())
+	}
+
+	// Validate the dry-run annotation
+	if err := r.validateDryRun(); err != nil {
+		key := field.NewPath("metadata").Child("annotations").Key(DryRunAnnotation)
+		return field.Invalid(key, r.Annotations[DryRunAnnotation], err.Error())
+	}
 
-	// Validate spec.data.lastname
+	// Validate spec.data
 	{
-		key := field.NewPath("spec").Child("data").Child("lastname")
-		value := r.Spec.Data.Lastname
+		key := field.NewPath("spec").Child("data")
+		value := string(r.Spec.Data.Raw)
+
+		if len(r.Spec.Data.Raw) == 0 && len(r.Spec.DataFrom) == 0 {
+			return field.Required(key, "data cannot be empty")
+		}
+
+		if len(r.Spec.Data.Raw) > 0 {
+
+			doc, err := mongodb.NewDocument(r.Spec.Data.Raw)
+			if err != nil {
+				return field.Invalid(key, value, err.Error())
+			}
+
+			for _, e := range doc {
+				if e.Key == "_id" {
+					return field.Forbidden(key.Child("_id"), "_id is managed by the operator")
+				}
+			}
+		}
+
+		// Validate spec.key, the key of a document with dataFrom is validated with its sources
+		if len(r.Spec.Key) > 0 && len(r.Spec.DataFrom) == 0 {
+
+			data, err := mongodb.MarshalDocument(r.Spec.Data.Raw)
+			if err != nil {
+				return field.Invalid(key, value, err.Error())
+			}
+
+			if _, err := mongodb.KeyFilter(data, r.dataKeys()); err != nil {
+				return field.Invalid(field.NewPath("spec").Child("key"), r.Spec.Key, err.Error())
+			}
+		}
+	}
 
-		if value == "" {
-			return field.Invalid(key, value, "lastname cannot be empty")
+	// Validate spec.dataFrom
+	if len(r.Spec.DataFrom) > 0 {
+		if err := r.validateDataFrom(external); err != nil {
+			return err
 		}
 	}
 
-	// Validate spec.data.email
+	// Validate spec.secretFields
 	{
+		key := field.NewPath("spec").Child("secretFields")
+
+		seen := map[string]bool{}
+		for i, secretField := range r.Spec.SecretFields {
+
+			if err := validateIndexField(secretField.Path); err != nil {
+				return field.Invalid(key.Index(i).Child("path"), secretField.Path, err.Error())
+			}
+
+			if secretField.Path == "_id" || strings.HasPrefix(secretField.Path, "_id.") {
+				return field.Forbidden(key.Index(i).Child("path"), "_id is managed by the operator")
+			}
+
+			if seen[secretField.Path] {
+				return field.Duplicate(key.Index(i).Child("path"), secretField.Path)
+			}
+			seen[secretField.Path] = true
+
+			if secretField.SecretKeyRef.Name == "" {
+				return field.Required(key.Index(i).Child("secretKeyRef").Child("name"), "name cannot be empty")
+			}
+
+			if secretField.SecretKeyRef.Key == "" {
+				return field.Required(key.Index(i).Child("secretKeyRef").Child("key"), "key cannot be empty")
+			}
+		}
+	}
+
+	// Validate spec.references
+	if err := r.validateReferences(); err != nil {
+		return err
+	}
+
+	// Validate spec.concurrency
+	if err := r.validateConcurrency(); err != nil {
+		return err
+	}
+
+	// Validate spec.encryptedFields
+	if err := r.validateEncryptedFields(); err != nil {
+		return err
+	}
+
+	// Validate spec.adopt
+	if adopt := r.Spec.Adopt; adopt != nil {
+		key := field.NewPath("spec").Child("adopt")
+
+		if (adopt.ObjectID == "") == (adopt.Filter == nil) {
+			return field.Invalid(key, adopt, "exactly one of objectID or filter must be specified")
+		}
 
-		key := field.NewPath("spec").Child("data").Child("email")
-		value := r.Spec.Data.Email
+		if adopt.ObjectID != "" {
+			if _, err := primitive.ObjectIDFromHex(adopt.ObjectID); err != nil {
+				return field.Invalid(key.Child("objectID"), adopt.ObjectID, err.Error())
+			}
+		}
+
+		if adopt.Filter != nil {
+			if _, err := mongodb.NewFilter(adopt.Filter.Raw); err != nil {
+				return field.Invalid(key.Child("filter"), string(adopt.Filter.Raw), err.Error())
+			}
+		}
+	}
+
+	// Validate spec.softDelete
+	if r.Spec.SoftDelete != nil {
+		if err := r.Spec.SoftDelete.validate(); err != nil {
+			key := field.NewPath("spec").Child("softDelete")
+			return field.Invalid(key, r.Spec.SoftDelete, err.Error())
+		}
+	}
+
+	// Validate spec.database
+	if err := r.Spec.Database.validate(); err != nil {
+		key := field.NewPath("spec").Child("database")
+		return field.Invalid(key, r.Spec.Database, err.Error())
+	}
+
+	// Validate spec.collection
+	if r.Spec.Collection != "" {
+		key := field.NewPath("spec").Child("collection")
+		if err := validateCollectionName(r.Spec.Collection); err != nil {
+			return field.Invalid(key, r.Spec.Collection, err.Error())
+		}
+	}
+
+	// check the database policy and allowed collections of the MongoDBConfig when it exists
+	if external {
+		mongoCfg, err := r.getMongoDBConfig()
+		if err != nil {
+			return field.InternalError(field.NewPath("spec").Child("db"), err)
+		}
+
+		if mongoCfg != nil {
+
+			if _, err := mongoCfg.DatabaseFor(r); err != nil {
+				return field.Forbidden(field.NewPath("spec").Child("database"), err.Error())
+			}
+
+			if _, err := mongoCfg.CollectionFor(r); err != nil {
+				return field.Forbidden(field.NewPath("spec").Child("collection"), err.Error())
+			}
+
+			if len(r.Spec.EncryptedFields) > 0 && !mongoCfg.EncryptionEnabled() {
+				msg := fmt.Sprintf("encryption of MongoDBConfig %s is not enabled", mongoCfg.Name)
+				return field.Forbidden(field.NewPath("spec").Child("encryptedFields"), msg)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateDataFrom checks the sources of spec.dataFrom, when external is set the referenced ConfigMap
+// and Secret keys must exist and their data is merged into the document to validate spec.key
+func (r *MongoDBData) validateDataFrom(external bool) *field.Error {
+	key := field.NewPath("spec").Child("dataFrom")
+
+	for i, source := range r.Spec.DataFrom {
 
-		if value != "" {
-			if !r.isValidEmail(value) {
-				return field.Invalid(key, value, "email is not valid")
+		if (source.ConfigMapKeyRef == nil) == (source.SecretKeyRef == nil) {
+			return field.Invalid(key.Index(i), source, "exactly one of configMapKeyRef or secretKeyRef must be specified")
+		}
+
+		if source.Path != "" {
+			if err := validateIndexField(source.Path); err != nil {
+				return field.Invalid(key.Index(i).Child("path"), source.Path, err.Error())
 			}
 		}
 	}
 
+	if kubeClient == nil || !external {
+		return nil
+	}
+
+	document, err := r.Document(context.Background(), kubeClient)
+	if err != nil {
+		if IsDataFromNotFound(err) {
+			return field.NotFound(key, err.Error())
+		}
+		return field.Invalid(key, len(r.Spec.DataFrom), err.Error())
+	}
+
+	if len(r.Spec.Key) > 0 {
+		if _, err := mongodb.KeyFilter(document, r.dataKeys()); err != nil {
+			return field.Invalid(field.NewPath("spec").Child("key"), r.Spec.Key, err.Error())
+		}
+	}
+
 	return nil
 }
 
+// getMongoDBConfig returns the referenced MongoDBConfig, or nil if it doesn't exist
+func (r *MongoDBData) getMongoDBConfig() (*MongoDBConfig, error) {
+	if kubeClient == nil {
+		return nil, nil
+	}
+
+	mongoCfg := &MongoDBConfig{}
+	if err := kubeClient.Get(context.Background(), client.ObjectKey{Name: r.Spec.DB}, mongoCfg); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	return mongoCfg, nil
+}
+
 func (r *MongoDBData) validateDatabase() *field.Error {
 	key := field.NewPath("spec").Child("db")
 	value := r.Spec.DB
@@ -137,8 +358,3 @@ func (r *MongoDBData) validateDatabase() *field.Error {
 func newError(name string, err *field.Error) error {
 	return apierrors.NewInvalid(GroupKind, name, field.ErrorList{err})
 }
-
-func (r *MongoDBData) isValidEmail(email string) bool {
-	re := regexp.MustCompile(emailRegexPattern)
-	return len(re.FindStringIndex(email)) > 0
-}
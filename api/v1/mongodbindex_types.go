@@ -0,0 +1,193 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// IndexKeyType is the type of an index key
+// +kubebuilder:validation:Enum=Ascending;Descending;Text;Hashed;"2dsphere"
+type IndexKeyType string
+
+const (
+	IndexKeyAscending  IndexKeyType = "Ascending"
+	IndexKeyDescending IndexKeyType = "Descending"
+	IndexKeyText       IndexKeyType = "Text"
+	IndexKeyHashed     IndexKeyType = "Hashed"
+	IndexKey2dsphere   IndexKeyType = "2dsphere"
+)
+
+// IndexRebuildPolicy defines what happens when a live index differs from its spec
+// +kubebuilder:validation:Enum=Recreate;Never
+type IndexRebuildPolicy string
+
+const (
+	// IndexRebuildRecreate drops the live index and creates it again from the spec
+	IndexRebuildRecreate IndexRebuildPolicy = "Recreate"
+
+	// IndexRebuildNever leaves the live index as it is and reports the drift
+	IndexRebuildNever IndexRebuildPolicy = "Never"
+)
+
+// MongoDBIndexSpec defines the desired state of MongoDBIndex
+type MongoDBIndexSpec struct {
+	// DB is a MongoDBConfig name
+	DB string `json:"db"`
+
+	// Database is the mongodb database of the index
+	Database string `json:"database"`
+
+	// Collection is the mongodb collection of the index
+	Collection string `json:"collection"`
+
+	// Name is the index name, mongodb's default name
+	// is generated from the keys when it's not set
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Keys are the indexed fields in order
+	// +kubebuilder:validation:MinItems=1
+	Keys []IndexKey `json:"keys"`
+
+	// Unique rejects documents with a duplicate value of the keys
+	// +optional
+	Unique bool `json:"unique,omitempty"`
+
+	// Sparse only indexes the documents which have the keys
+	// +optional
+	Sparse bool `json:"sparse,omitempty"`
+
+	// PartialFilterExpression is a mongodb query in extended json,
+	// only the matching documents are indexed
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	PartialFilterExpression *runtime.RawExtension `json:"partialFilterExpression,omitempty"`
+
+	// ExpireAfterSeconds makes a TTL index, documents are removed
+	// the given seconds after the date of the key
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ExpireAfterSeconds *int32 `json:"expireAfterSeconds,omitempty"`
+
+	// Collation defines the language specific string comparison of the index
+	// +optional
+	Collation *IndexCollation `json:"collation,omitempty"`
+
+	// RebuildPolicy defines what happens when the live index differs from the spec
+	// +kubebuilder:default=Recreate
+	// +optional
+	RebuildPolicy IndexRebuildPolicy `json:"rebuildPolicy,omitempty"`
+}
+
+// IndexKey is an indexed field
+type IndexKey struct {
+	// Field is a dot separated field path
+	Field string `json:"field"`
+
+	// Type of the key
+	// +kubebuilder:default=Ascending
+	// +optional
+	Type IndexKeyType `json:"type,omitempty"`
+}
+
+// IndexCollation is a mongodb collation
+type IndexCollation struct {
+	// Locale is the ICU locale
+	Locale string `json:"locale"`
+
+	// +optional
+	CaseLevel bool `json:"caseLevel,omitempty"`
+
+	// +kubebuilder:validation:Enum=upper;lower;off
+	// +optional
+	CaseFirst string `json:"caseFirst,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5
+	// +optional
+	Strength int `json:"strength,omitempty"`
+
+	// +optional
+	NumericOrdering bool `json:"numericOrdering,omitempty"`
+
+	// +kubebuilder:validation:Enum=non-ignorable;shifted
+	// +optional
+	Alternate string `json:"alternate,omitempty"`
+
+	// +kubebuilder:validation:Enum=punct;space
+	// +optional
+	MaxVariable string `json:"maxVariable,omitempty"`
+
+	// +optional
+	Backwards bool `json:"backwards,omitempty"`
+}
+
+// MongoDBIndexStatus defines the observed state of MongoDBIndex
+type MongoDBIndexStatus struct {
+	// +kubebuilder:default="Pending"
+	State string `json:"state,omitempty"`
+
+	// Name is the name of the created index
+	Name string `json:"name,omitempty"`
+
+	// Created reports whether the index has been created by the MongoDBIndex,
+	// an index which already existed is never rebuilt or dropped
+	Created bool `json:"created,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// MongoDBIndex is the Schema for the mongodbindexes API
+// +kubebuilder:printcolumn:name="Collection",type="string",JSONPath=".spec.collection",description="Collection of the index"
+// +kubebuilder:printcolumn:name="Index",type="string",JSONPath=".status.name",description="Name of the created index"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Current state of the MongoDBIndex"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// +operator-sdk:csv:customresourcedefinitions:displayName="MongoDBIndex"
+// +kubebuilder:resource:path=mongodbindexes,shortName=mdbi
+type MongoDBIndex struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MongoDBIndexSpec   `json:"spec,omitempty"`
+	Status MongoDBIndexStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MongoDBIndexList contains a list of MongoDBIndex
+type MongoDBIndexList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MongoDBIndex `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MongoDBIndex{}, &MongoDBIndexList{})
+}
+
+type MongoDBIndexConditionType string
+
+const (
+	MongoDBIndexConditionPending MongoDBIndexConditionType = "Pending"
+	MongoDBIndexConditionReady   MongoDBIndexConditionType = "Ready"
+	MongoDBIndexConditionFailed  MongoDBIndexConditionType = "Failed"
+	MongoDBIndexConditionDrifted MongoDBIndexConditionType = "Drifted"
+)
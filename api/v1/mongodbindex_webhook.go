@@ -0,0 +1,182 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// log is for logging in this package.
+var (
+	mongodbindexlog = logf.Log.WithName("mongodbindex-resource")
+)
+
+func (r *MongoDBIndex) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mongo-snappcloud-io-v1-mongodbindex,mutating=false,failurePolicy=fail,sideEffects=None,groups=mongo.snappcloud.io,resources=mongodbindexes,verbs=create;update,versions=v1,name=vmongodbindex.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MongoDBIndex{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBIndex) ValidateCreate() error {
+	mongodbindexlog.Info("validate create", "name", r.ObjectMeta.Name)
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBIndex) ValidateUpdate(old runtime.Object) error {
+	mongodbindexlog.Info("validate update", "name", r.ObjectMeta.Name)
+
+	oldmdbi, ok := old.(*MongoDBIndex)
+	if !ok {
+		return errors.New("runtime.Object should be a type of mongov1.MongoDBIndex")
+	}
+
+	if r.Spec.DB != oldmdbi.Spec.DB {
+		return field.Forbidden(field.NewPath("spec").Child("db"), "cannot have a change on db field")
+	}
+
+	if r.Spec.Database != oldmdbi.Spec.Database {
+		return field.Forbidden(field.NewPath("spec").Child("database"), "cannot have a change on database field")
+	}
+
+	if r.Spec.Collection != oldmdbi.Spec.Collection {
+		return field.Forbidden(field.NewPath("spec").Child("collection"), "cannot have a change on collection field")
+	}
+
+	if r.Spec.Name != oldmdbi.Spec.Name {
+		return field.Forbidden(field.NewPath("spec").Child("name"), "cannot have a change on name field")
+	}
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBIndex) ValidateDelete() error {
+	mongodbindexlog.Info("validate delete", "name", r.ObjectMeta.Name)
+	return nil
+}
+
+func (r *MongoDBIndex) validateSpecs() *field.Error {
+
+	if r.Spec.DB == "" {
+		return field.Invalid(field.NewPath("spec").Child("db"), r.Spec.DB, "db cannot be empty")
+	}
+
+	if err := validateDatabaseName(r.Spec.Database); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("database"), r.Spec.Database, err.Error())
+	}
+
+	if err := validateCollectionName(r.Spec.Collection); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("collection"), r.Spec.Collection, err.Error())
+	}
+
+	// Validate spec.keys
+	{
+		key := field.NewPath("spec").Child("keys")
+
+		if len(r.Spec.Keys) == 0 {
+			return field.Required(key, "keys cannot be empty")
+		}
+
+		seen := map[string]bool{}
+		for i, indexKey := range r.Spec.Keys {
+			if err := validateIndexField(indexKey.Field); err != nil {
+				return field.Invalid(key.Index(i).Child("field"), indexKey.Field, err.Error())
+			}
+			if seen[indexKey.Field] {
+				return field.Duplicate(key.Index(i).Child("field"), indexKey.Field)
+			}
+			seen[indexKey.Field] = true
+		}
+	}
+
+	// Validate spec.expireAfterSeconds
+	if r.Spec.ExpireAfterSeconds != nil {
+		key := field.NewPath("spec").Child("expireAfterSeconds")
+
+		if len(r.Spec.Keys) != 1 {
+			return field.Invalid(key, *r.Spec.ExpireAfterSeconds, "a TTL index must have a single key")
+		}
+	}
+
+	// Validate spec.partialFilterExpression
+	if pfe := r.Spec.PartialFilterExpression; pfe != nil {
+		key := field.NewPath("spec").Child("partialFilterExpression")
+
+		if r.Spec.Sparse {
+			return field.Invalid(key, string(pfe.Raw), "partialFilterExpression cannot be used with sparse")
+		}
+
+		if _, err := mongodb.NewFilter(pfe.Raw); err != nil {
+			return field.Invalid(key, string(pfe.Raw), err.Error())
+		}
+	}
+
+	// check the database policy and allowed collections of the MongoDBConfig when it exists
+	{
+		mongoCfg, err := r.getMongoDBConfig()
+		if err != nil {
+			return field.InternalError(field.NewPath("spec").Child("db"), err)
+		}
+
+		if mongoCfg != nil {
+			if err := mongoCfg.CheckIndex(r); err != nil {
+				return field.Forbidden(field.NewPath("spec"), err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// getMongoDBConfig returns the referenced MongoDBConfig, or nil if it doesn't exist
+func (r *MongoDBIndex) getMongoDBConfig() (*MongoDBConfig, error) {
+	if kubeClient == nil {
+		return nil, nil
+	}
+
+	mongoCfg := &MongoDBConfig{}
+	if err := kubeClient.Get(context.Background(), client.ObjectKey{Name: r.Spec.DB}, mongoCfg); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	return mongoCfg, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MongoDBRoleSpec defines the desired state of MongoDBRole
+type MongoDBRoleSpec struct {
+	// DB is a MongoDBConfig name
+	DB string `json:"db"`
+
+	// Database is the mongodb database where the role is created
+	Database string `json:"database"`
+
+	// RoleName is the name of the role, the name of the MongoDBRole is used by default
+	// +optional
+	RoleName string `json:"roleName,omitempty"`
+
+	// Privileges are the actions which the role allows on resources
+	// +optional
+	Privileges []RolePrivilege `json:"privileges,omitempty"`
+
+	// Roles are the roles which the role inherits
+	// +optional
+	Roles []RoleReference `json:"roles,omitempty"`
+}
+
+// RolePrivilege allows actions on a resource
+type RolePrivilege struct {
+	// Resource the actions are allowed on
+	Resource PrivilegeResource `json:"resource"`
+
+	// Actions are the allowed privilege actions, e.g. find, insert, update
+	// +kubebuilder:validation:MinItems=1
+	Actions []string `json:"actions"`
+}
+
+// PrivilegeResource is the cluster or a database and collection,
+// an empty database or collection matches all of them
+type PrivilegeResource struct {
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// +optional
+	Collection string `json:"collection,omitempty"`
+
+	// Cluster is the resource of the cluster wide actions
+	// +optional
+	Cluster bool `json:"cluster,omitempty"`
+}
+
+// RoleReference is a role of a database
+type RoleReference struct {
+	// Role name
+	Role string `json:"role"`
+
+	// Database of the role
+	Database string `json:"database"`
+}
+
+// MongoDBRoleStatus defines the observed state of MongoDBRole
+type MongoDBRoleStatus struct {
+	// +kubebuilder:default="Pending"
+	State string `json:"state,omitempty"`
+
+	// Created reports whether the role has been created by the MongoDBRole,
+	// a role which already existed is never updated or dropped
+	Created bool `json:"created,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// MongoDBRole is the Schema for the mongodbroles API
+// +kubebuilder:printcolumn:name="Database",type="string",JSONPath=".spec.database",description="Database of the role"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Current state of the MongoDBRole"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// +operator-sdk:csv:customresourcedefinitions:displayName="MongoDBRole"
+// +kubebuilder:resource:shortName=mdbr
+type MongoDBRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MongoDBRoleSpec   `json:"spec,omitempty"`
+	Status MongoDBRoleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MongoDBRoleList contains a list of MongoDBRole
+type MongoDBRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MongoDBRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MongoDBRole{}, &MongoDBRoleList{})
+}
+
+type MongoDBRoleConditionType string
+
+const (
+	MongoDBRoleConditionPending MongoDBRoleConditionType = "Pending"
+	MongoDBRoleConditionReady   MongoDBRoleConditionType = "Ready"
+	MongoDBRoleConditionFailed  MongoDBRoleConditionType = "Failed"
+)
@@ -0,0 +1,164 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var (
+	mongodbrolelog = logf.Log.WithName("mongodbrole-resource")
+)
+
+func (r *MongoDBRole) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mongo-snappcloud-io-v1-mongodbrole,mutating=false,failurePolicy=fail,sideEffects=None,groups=mongo.snappcloud.io,resources=mongodbroles,verbs=create;update,versions=v1,name=vmongodbrole.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MongoDBRole{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBRole) ValidateCreate() error {
+	mongodbrolelog.Info("validate create", "name", r.ObjectMeta.Name)
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBRole) ValidateUpdate(old runtime.Object) error {
+	mongodbrolelog.Info("validate update", "name", r.ObjectMeta.Name)
+
+	oldmdbr, ok := old.(*MongoDBRole)
+	if !ok {
+		return errors.New("runtime.Object should be a type of mongov1.MongoDBRole")
+	}
+
+	if r.Spec.DB != oldmdbr.Spec.DB {
+		return field.Forbidden(field.NewPath("spec").Child("db"), "cannot have a change on db field")
+	}
+
+	if r.Spec.Database != oldmdbr.Spec.Database {
+		return field.Forbidden(field.NewPath("spec").Child("database"), "cannot have a change on database field")
+	}
+
+	if r.Spec.RoleName != oldmdbr.Spec.RoleName {
+		return field.Forbidden(field.NewPath("spec").Child("roleName"), "cannot have a change on roleName field")
+	}
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBRole) ValidateDelete() error {
+	mongodbrolelog.Info("validate delete", "name", r.ObjectMeta.Name)
+	return nil
+}
+
+func (r *MongoDBRole) validateSpecs() *field.Error {
+
+	if r.Spec.DB == "" {
+		return field.Invalid(field.NewPath("spec").Child("db"), r.Spec.DB, "db cannot be empty")
+	}
+
+	if err := validateDatabaseName(r.Spec.Database); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("database"), r.Spec.Database, err.Error())
+	}
+
+	if len(r.Spec.Privileges) == 0 && len(r.Spec.Roles) == 0 {
+		return field.Required(field.NewPath("spec").Child("privileges"), "one of privileges or roles must be specified")
+	}
+
+	// Validate spec.privileges
+	for i, privilege := range r.Spec.Privileges {
+		key := field.NewPath("spec").Child("privileges").Index(i)
+
+		if privilege.Resource.Cluster && (privilege.Resource.Database != "" || privilege.Resource.Collection != "") {
+			return field.Invalid(key.Child("resource"), privilege.Resource, "cluster cannot be used with database or collection")
+		}
+
+		if len(privilege.Actions) == 0 {
+			return field.Required(key.Child("actions"), "actions cannot be empty")
+		}
+	}
+
+	// Validate spec.roles
+	if err := validateRoleReferences(field.NewPath("spec").Child("roles"), r.Spec.Roles); err != nil {
+		return err
+	}
+
+	// check the database policy of the MongoDBConfig when it exists
+	{
+		mongoCfg, err := getMongoDBConfig(r.Spec.DB)
+		if err != nil {
+			return field.InternalError(field.NewPath("spec").Child("db"), err)
+		}
+
+		if mongoCfg != nil {
+			if err := mongoCfg.CheckRole(r); err != nil {
+				return field.Forbidden(field.NewPath("spec"), err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRoleReferences(key *field.Path, roles []RoleReference) *field.Error {
+	for i, role := range roles {
+		if role.Role == "" {
+			return field.Required(key.Index(i).Child("role"), "role cannot be empty")
+		}
+		if err := validateDatabaseName(role.Database); err != nil {
+			return field.Invalid(key.Index(i).Child("database"), role.Database, err.Error())
+		}
+	}
+	return nil
+}
+
+// getMongoDBConfig returns the MongoDBConfig with the given name, or nil if it doesn't exist
+func getMongoDBConfig(name string) (*MongoDBConfig, error) {
+	if kubeClient == nil {
+		return nil, nil
+	}
+
+	mongoCfg := &MongoDBConfig{}
+	if err := kubeClient.Get(context.Background(), client.ObjectKey{Name: name}, mongoCfg); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	return mongoCfg, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MongoDBUserSpec defines the desired state of MongoDBUser
+type MongoDBUserSpec struct {
+	// DB is a MongoDBConfig name
+	DB string `json:"db"`
+
+	// Database is the authentication database of the user
+	Database string `json:"database"`
+
+	// Username is the name of the user, the name of the MongoDBUser is used by default
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Roles are the roles granted to the user
+	// +optional
+	Roles []RoleReference `json:"roles,omitempty"`
+
+	// SecretName is the secret in the namespace of the MongoDBUser where the generated
+	// password is kept, it defaults to <name>-mongodb-user and is owned by the MongoDBUser
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// PasswordLength is the length of the generated password
+	// +kubebuilder:default=32
+	// +kubebuilder:validation:Minimum=16
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	PasswordLength int `json:"passwordLength,omitempty"`
+}
+
+// MongoDBUserStatus defines the observed state of MongoDBUser
+type MongoDBUserStatus struct {
+	// +kubebuilder:default="Pending"
+	State string `json:"state,omitempty"`
+
+	// SecretName is the secret holding the credentials of the user
+	SecretName string `json:"secretName,omitempty"`
+
+	// PasswordVersion is the resource version of the secret whose password has been set
+	PasswordVersion string `json:"passwordVersion,omitempty"`
+
+	// Roles are the roles which are actually granted to the user in mongodb
+	Roles []RoleReference `json:"roles,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// MongoDBUser is the Schema for the mongodbusers API
+// +kubebuilder:printcolumn:name="Database",type="string",JSONPath=".spec.database",description="Authentication database of the user"
+// +kubebuilder:printcolumn:name="Secret",type="string",JSONPath=".status.secretName",description="Secret holding the credentials"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Current state of the MongoDBUser"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// +operator-sdk:csv:customresourcedefinitions:displayName="MongoDBUser"
+// +kubebuilder:resource:shortName=mdbu
+type MongoDBUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MongoDBUserSpec   `json:"spec,omitempty"`
+	Status MongoDBUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MongoDBUserList contains a list of MongoDBUser
+type MongoDBUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MongoDBUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MongoDBUser{}, &MongoDBUserList{})
+}
+
+type MongoDBUserConditionType string
+
+const (
+	MongoDBUserConditionPending MongoDBUserConditionType = "Pending"
+	MongoDBUserConditionReady   MongoDBUserConditionType = "Ready"
+	MongoDBUserConditionFailed  MongoDBUserConditionType = "Failed"
+)
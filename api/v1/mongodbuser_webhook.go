@@ -0,0 +1,123 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var (
+	mongodbuserlog = logf.Log.WithName("mongodbuser-resource")
+)
+
+func (r *MongoDBUser) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mongo-snappcloud-io-v1-mongodbuser,mutating=false,failurePolicy=fail,sideEffects=None,groups=mongo.snappcloud.io,resources=mongodbusers,verbs=create;update,versions=v1,name=vmongodbuser.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MongoDBUser{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBUser) ValidateCreate() error {
+	mongodbuserlog.Info("validate create", "name", r.ObjectMeta.Name)
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBUser) ValidateUpdate(old runtime.Object) error {
+	mongodbuserlog.Info("validate update", "name", r.ObjectMeta.Name)
+
+	oldmdbu, ok := old.(*MongoDBUser)
+	if !ok {
+		return errors.New("runtime.Object should be a type of mongov1.MongoDBUser")
+	}
+
+	if r.Spec.DB != oldmdbu.Spec.DB {
+		return field.Forbidden(field.NewPath("spec").Child("db"), "cannot have a change on db field")
+	}
+
+	if r.Spec.Database != oldmdbu.Spec.Database {
+		return field.Forbidden(field.NewPath("spec").Child("database"), "cannot have a change on database field")
+	}
+
+	if r.Spec.Username != oldmdbu.Spec.Username {
+		return field.Forbidden(field.NewPath("spec").Child("username"), "cannot have a change on username field")
+	}
+
+	if r.Spec.SecretName != oldmdbu.Spec.SecretName {
+		return field.Forbidden(field.NewPath("spec").Child("secretName"), "cannot have a change on secretName field")
+	}
+
+	if err := r.validateSpecs(); err != nil {
+		return newError(r.ObjectMeta.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *MongoDBUser) ValidateDelete() error {
+	mongodbuserlog.Info("validate delete", "name", r.ObjectMeta.Name)
+	return nil
+}
+
+func (r *MongoDBUser) validateSpecs() *field.Error {
+
+	if r.Spec.DB == "" {
+		return field.Invalid(field.NewPath("spec").Child("db"), r.Spec.DB, "db cannot be empty")
+	}
+
+	if err := validateDatabaseName(r.Spec.Database); err != nil {
+		return field.Invalid(field.NewPath("spec").Child("database"), r.Spec.Database, err.Error())
+	}
+
+	// Validate spec.roles
+	if err := validateRoleReferences(field.NewPath("spec").Child("roles"), r.Spec.Roles); err != nil {
+		return err
+	}
+
+	// check the database policy of the MongoDBConfig when it exists
+	{
+		mongoCfg, err := getMongoDBConfig(r.Spec.DB)
+		if err != nil {
+			return field.InternalError(field.NewPath("spec").Child("db"), err)
+		}
+
+		if mongoCfg != nil {
+			if err := mongoCfg.CheckUser(r); err != nil {
+				return field.Forbidden(field.NewPath("spec"), err.Error())
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReferenceValues returns the ObjectIDs of the MongoDBData of spec.references by their paths,
+// it's a not found error while a referenced MongoDBData doesn't exist or isn't inserted yet
+func (r *MongoDBData) ReferenceValues(ctx context.Context, c client.Reader) (bson.D, error) {
+
+	values := bson.D{}
+	for _, ref := range r.Spec.References {
+		key := r.referenceKey(ref)
+
+		target := &MongoDBData{}
+		if err := c.Get(ctx, key, target); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, &dataFromNotFoundError{fmt.Sprintf("MongoDBData %s doesn't exist", key)}
+			}
+			return nil, err
+		}
+
+		if target.Status.ObjectID == "" {
+			return nil, &dataFromNotFoundError{fmt.Sprintf("MongoDBData %s is not inserted yet", key)}
+		}
+
+		oid, err := primitive.ObjectIDFromHex(target.Status.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("MongoDBData %s has an invalid ObjectID: %v", key, err)
+		}
+
+		values = append(values, bson.E{Key: ref.Path, Value: oid})
+	}
+
+	return values, nil
+}
+
+// ReferenceRefs returns the MongoDBData of spec.references as namespace/name
+func (r *MongoDBData) ReferenceRefs() []string {
+	refs := make([]string, 0, len(r.Spec.References))
+	for _, ref := range r.Spec.References {
+		refs = append(refs, r.referenceKey(ref).String())
+	}
+	return refs
+}
+
+func (r *MongoDBData) referenceKey(ref MongoDBDataReference) client.ObjectKey {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = r.Namespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: ref.Name}
+}
+
+// dataKeys returns the fields of spec.key which are not set by spec.references,
+// the referenced ObjectIDs are only known once their documents are inserted
+func (r *MongoDBData) dataKeys() []string {
+	keys := []string{}
+	for _, key := range r.Spec.Key {
+
+		referenced := false
+		for _, ref := range r.Spec.References {
+			if key == ref.Path || strings.HasPrefix(key, ref.Path+".") {
+				referenced = true
+				break
+			}
+		}
+
+		if !referenced {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// validateReferences checks the paths and names of spec.references
+func (r *MongoDBData) validateReferences() *field.Error {
+	key := field.NewPath("spec").Child("references")
+
+	seen := map[string]bool{}
+	for i, ref := range r.Spec.References {
+
+		if err := validateIndexField(ref.Path); err != nil {
+			return field.Invalid(key.Index(i).Child("path"), ref.Path, err.Error())
+		}
+
+		if ref.Path == "_id" || strings.HasPrefix(ref.Path, "_id.") {
+			return field.Forbidden(key.Index(i).Child("path"), "_id is managed by the operator")
+		}
+
+		if seen[ref.Path] {
+			return field.Duplicate(key.Index(i).Child("path"), ref.Path)
+		}
+		seen[ref.Path] = true
+
+		if ref.Name == "" {
+			return field.Required(key.Index(i).Child("name"), "name cannot be empty")
+		}
+
+		if target := r.referenceKey(ref); target.Namespace == r.Namespace && target.Name == r.Name {
+			return field.Invalid(key.Index(i).Child("name"), ref.Name, "a MongoDBData cannot reference itself")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReferenceValues(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	oid := primitive.NewObjectID()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&MongoDBData{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "author"},
+			Status:     MongoDBDataStatus{ObjectID: oid.Hex()},
+		},
+		&MongoDBData{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "publisher"},
+			Status:     MongoDBDataStatus{ObjectID: oid.Hex()},
+		},
+		&MongoDBData{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "pending"},
+		},
+		&MongoDBData{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "invalid"},
+			Status:     MongoDBDataStatus{ObjectID: "not-an-object-id"},
+		},
+	).Build()
+
+	tests := []struct {
+		name         string
+		references   []MongoDBDataReference
+		want         bson.D
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{
+			name: "no references",
+			want: bson.D{},
+		},
+		{
+			name: "same and other namespace",
+			references: []MongoDBDataReference{
+				{Path: "authorId", Name: "author"},
+				{Path: "publisher.id", Namespace: "team-b", Name: "publisher"},
+			},
+			want: bson.D{{Key: "authorId", Value: oid}, {Key: "publisher.id", Value: oid}},
+		},
+		{
+			name:         "missing mongodbdata",
+			references:   []MongoDBDataReference{{Path: "authorId", Name: "other"}},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:         "not inserted yet",
+			references:   []MongoDBDataReference{{Path: "authorId", Name: "pending"}},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:       "invalid object id",
+			references: []MongoDBDataReference{{Path: "authorId", Name: "invalid"}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "book"},
+				Spec:       MongoDBDataSpec{References: tt.references},
+			}
+
+			got, err := mongoData.ReferenceValues(context.Background(), c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReferenceValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if IsDataFromNotFound(err) != tt.wantNotFound {
+				t.Errorf("IsDataFromNotFound() = %v, want %v", IsDataFromNotFound(err), tt.wantNotFound)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReferenceValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferenceRefs(t *testing.T) {
+	mongoData := &MongoDBData{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "book"},
+		Spec: MongoDBDataSpec{References: []MongoDBDataReference{
+			{Path: "authorId", Name: "author"},
+			{Path: "publisherId", Namespace: "team-b", Name: "publisher"},
+		}},
+	}
+
+	want := []string{"team-a/author", "team-b/publisher"}
+	if got := mongoData.ReferenceRefs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReferenceRefs() = %v, want %v", got, want)
+	}
+}
+
+func TestDataKeys(t *testing.T) {
+	mongoData := &MongoDBData{Spec: MongoDBDataSpec{
+		Key:        []string{"name", "authorId", "publisher.id", "publisherName"},
+		References: []MongoDBDataReference{{Path: "authorId", Name: "author"}, {Path: "publisher", Name: "publisher"}},
+	}}
+
+	want := []string{"name", "publisherName"}
+	if got := mongoData.dataKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("dataKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		references []MongoDBDataReference
+		wantErr    bool
+	}{
+		{
+			name:       "valid",
+			references: []MongoDBDataReference{{Path: "authorId", Name: "author"}, {Path: "meta.publisherId", Name: "publisher"}},
+		},
+		{
+			name:       "self reference in another namespace",
+			references: []MongoDBDataReference{{Path: "bookId", Namespace: "team-b", Name: "book"}},
+		},
+		{
+			name:       "invalid path",
+			references: []MongoDBDataReference{{Path: "$author", Name: "author"}},
+			wantErr:    true,
+		},
+		{
+			name:       "id path",
+			references: []MongoDBDataReference{{Path: "_id", Name: "author"}},
+			wantErr:    true,
+		},
+		{
+			name:       "duplicate path",
+			references: []MongoDBDataReference{{Path: "authorId", Name: "author"}, {Path: "authorId", Name: "other"}},
+			wantErr:    true,
+		},
+		{
+			name:       "empty name",
+			references: []MongoDBDataReference{{Path: "authorId"}},
+			wantErr:    true,
+		},
+		{
+			name:       "self reference",
+			references: []MongoDBDataReference{{Path: "bookId", Name: "book"}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "book"},
+				Spec:       MongoDBDataSpec{References: tt.references},
+			}
+
+			if err := mongoData.validateReferences(); (err != nil) != tt.wantErr {
+				t.Errorf("validateReferences() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
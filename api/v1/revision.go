@@ -0,0 +1,71 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RollbackAnnotation holds the document at the given revision of its history instead
+// of the spec, the spec is applied again when the annotation is removed
+const RollbackAnnotation = "mongo.snappcloud.io/rollback-to"
+
+// revisionsSecretSuffix is appended to the name of the MongoDBData for its revisions secret
+const revisionsSecretSuffix = "-mongodb-data-revisions"
+
+// RevisionsSecretName returns the name of the secret holding the revision history of the document,
+// it's a secret since the document can contain the data of Secrets from spec.dataFrom. A name which
+// would be too long for a secret is truncated and made unique with its hash
+func (r *MongoDBData) RevisionsSecretName() string {
+	name := r.Name
+	if len(name)+len(revisionsSecretSuffix) > validation.DNS1123SubdomainMaxLength {
+		sum := sha256.Sum256([]byte(name))
+		hash := hex.EncodeToString(sum[:])[:8]
+		name = name[:validation.DNS1123SubdomainMaxLength-len(revisionsSecretSuffix)-len(hash)-1]
+		name = strings.TrimRight(name, ".-") + "-" + hash
+	}
+	return name + revisionsSecretSuffix
+}
+
+// RevisionHistoryLimit returns the number of document revisions which are kept
+func (r *MongoDBData) RevisionHistoryLimit() int {
+	if r.Spec.RevisionHistoryLimit == nil {
+		return 10
+	}
+	return int(*r.Spec.RevisionHistoryLimit)
+}
+
+// RollbackRevision returns the revision of the rollback annotation, or 0 if it's not set
+func (r *MongoDBData) RollbackRevision() (int64, error) {
+	value, ok := r.Annotations[RollbackAnnotation]
+	if !ok {
+		return 0, nil
+	}
+
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || revision < 1 {
+		return 0, fmt.Errorf("annotation %s must be a revision number, got %q", RollbackAnnotation, value)
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestRevisionsSecretName(t *testing.T) {
+	long := strings.Repeat("a", 240)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "short name",
+			data: "example",
+			want: "example-mongodb-data-revisions",
+		},
+		{
+			name: "longest name which isn't truncated",
+			data: long[:validation.DNS1123SubdomainMaxLength-len(revisionsSecretSuffix)],
+			want: long[:validation.DNS1123SubdomainMaxLength-len(revisionsSecretSuffix)] + revisionsSecretSuffix,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{ObjectMeta: metav1.ObjectMeta{Name: tt.data}}
+			if got := mongoData.RevisionsSecretName(); got != tt.want {
+				t.Errorf("RevisionsSecretName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevisionsSecretNameTruncated(t *testing.T) {
+	names := []string{
+		strings.Repeat("a", 240),
+		strings.Repeat("a", 239) + "b",
+		strings.Repeat("a", 200) + "." + strings.Repeat("b", 39),
+	}
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		mongoData := &MongoDBData{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		got := mongoData.RevisionsSecretName()
+
+		if errs := validation.IsDNS1123Subdomain(got); len(errs) > 0 {
+			t.Errorf("RevisionsSecretName() = %s is not a valid secret name: %v", got, errs)
+		}
+		if !strings.HasSuffix(got, revisionsSecretSuffix) {
+			t.Errorf("RevisionsSecretName() = %s doesn't end with %s", got, revisionsSecretSuffix)
+		}
+		if seen[got] {
+			t.Errorf("RevisionsSecretName() = %s is not unique", got)
+		}
+		seen[got] = true
+	}
+}
+
+func TestRollbackRevision(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "no annotation",
+			want: 0,
+		},
+		{
+			name:        "revision",
+			annotations: map[string]string{RollbackAnnotation: "3"},
+			want:        3,
+		},
+		{
+			name:        "zero",
+			annotations: map[string]string{RollbackAnnotation: "0"},
+			wantErr:     true,
+		},
+		{
+			name:        "not a number",
+			annotations: map[string]string{RollbackAnnotation: "latest"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+
+			got, err := mongoData.RollbackRevision()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RollbackRevision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RollbackRevision() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevisionHistoryLimit(t *testing.T) {
+	limit := int32(3)
+
+	tests := []struct {
+		name  string
+		limit *int32
+		want  int
+	}{
+		{
+			name: "default",
+			want: 10,
+		},
+		{
+			name:  "set",
+			limit: &limit,
+			want:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{RevisionHistoryLimit: tt.limit}}
+			if got := mongoData.RevisionHistoryLimit(); got != tt.want {
+				t.Errorf("RevisionHistoryLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
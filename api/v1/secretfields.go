@@ -0,0 +1,74 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretFieldValues returns the values of spec.secretFields by their paths, and a version
+// which changes whenever one of their Secrets changes. The errors never contain the values
+func (r *MongoDBData) SecretFieldValues(ctx context.Context, c client.Reader) (bson.D, string, error) {
+
+	values := bson.D{}
+	versions := []string{}
+	secrets := map[string]*corev1.Secret{}
+
+	for _, secretField := range r.Spec.SecretFields {
+		ref := secretField.SecretKeyRef
+
+		secret, ok := secrets[ref.Name]
+		if !ok {
+
+			secret = &corev1.Secret{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: ref.Name}, secret); err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil, "", &dataFromNotFoundError{fmt.Sprintf("Secret %s doesn't exist", ref.Name)}
+				}
+				return nil, "", err
+			}
+
+			secrets[ref.Name] = secret
+			versions = append(versions, ref.Name+"@"+secret.ResourceVersion)
+		}
+
+		data, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, "", &dataFromNotFoundError{fmt.Sprintf("key %s is not found in Secret %s", ref.Key, ref.Name)}
+		}
+
+		values = append(values, bson.E{Key: secretField.Path, Value: string(data)})
+	}
+
+	return values, strings.Join(versions, ","), nil
+}
+
+// SecretFieldPaths returns the document field paths of spec.secretFields
+func (r *MongoDBData) SecretFieldPaths() []string {
+	paths := make([]string, 0, len(r.Spec.SecretFields))
+	for _, secretField := range r.Spec.SecretFields {
+		paths = append(paths, secretField.Path)
+	}
+	return paths
+}
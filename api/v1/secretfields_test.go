@@ -0,0 +1,107 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSecretFieldValues(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "creds", ResourceVersion: "7"},
+			Data:       map[string][]byte{"password": []byte("hunter2"), "token": []byte("abc")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "keys", ResourceVersion: "3"},
+			Data:       map[string][]byte{"api": []byte("def")},
+		},
+	).Build()
+
+	secretField := func(path, name, key string) MongoDBSecretField {
+		return MongoDBSecretField{Path: path, SecretKeyRef: LocalSecretKeyReference{Name: name, Key: key}}
+	}
+
+	tests := []struct {
+		name         string
+		secretFields []MongoDBSecretField
+		want         bson.D
+		wantErr      bool
+	}{
+		{
+			name: "no secret fields",
+			want: bson.D{},
+		},
+		{
+			name: "values of several secrets",
+			secretFields: []MongoDBSecretField{
+				secretField("auth.password", "creds", "password"),
+				secretField("auth.token", "creds", "token"),
+				secretField("api", "keys", "api"),
+			},
+			want: bson.D{
+				{Key: "auth.password", Value: "hunter2"},
+				{Key: "auth.token", Value: "abc"},
+				{Key: "api", Value: "def"},
+			},
+		},
+		{
+			name:         "missing secret",
+			secretFields: []MongoDBSecretField{secretField("password", "other", "password")},
+			wantErr:      true,
+		},
+		{
+			name:         "missing key",
+			secretFields: []MongoDBSecretField{secretField("password", "creds", "other")},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+				Spec:       MongoDBDataSpec{SecretFields: tt.secretFields},
+			}
+
+			got, version, err := mongoData.SecretFieldValues(context.Background(), c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretFieldValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsDataFromNotFound(err) || strings.Contains(err.Error(), "hunter2") {
+					t.Errorf("SecretFieldValues() error = %v, want a not found error without values", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SecretFieldValues() = %#v, want %#v", got, tt.want)
+			}
+			if len(tt.secretFields) > 0 && version == "" {
+				t.Errorf("SecretFieldValues() version is empty")
+			}
+		})
+	}
+}
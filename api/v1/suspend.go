@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// SyncNowAnnotation requests an immediate reconcile, every new value of the annotation,
+// like the current time, syncs the resource once even when it's suspended
+const SyncNowAnnotation = "mongo.snappcloud.io/sync-now"
+
+// SyncNowRequested reports whether the sync-now annotation has a value which hasn't been handled yet
+func (r *MongoDBData) SyncNowRequested() bool {
+	value := r.Annotations[SyncNowAnnotation]
+	return value != "" && value != r.Status.LastSyncNow
+}
+
+// ConfigSyncNowRequested reports whether the MongoDBConfig has a sync-now value which hasn't been
+// handled by the MongoDBData yet, a MongoDBData which has never been applied has nothing to sync
+func (r *MongoDBData) ConfigSyncNowRequested(mongoCfg *MongoDBConfig) bool {
+	value := mongoCfg.SyncNowValue()
+	return value != "" && value != r.Status.LastConfigSyncNow && r.Status.AppliedGeneration > 0
+}
+
+// IsSuspended reports whether the writes to the document are suspended
+// by the MongoDBData or by its MongoDBConfig
+func (r *MongoDBData) IsSuspended(mongoCfg *MongoDBConfig) bool {
+	return r.Spec.Suspend || mongoCfg.Spec.Suspend
+}
+
+// SuspendedBy returns the reason of the suspension of the document
+func (r *MongoDBData) SuspendedBy(mongoCfg *MongoDBConfig) string {
+	if r.Spec.Suspend {
+		return "spec.suspend"
+	}
+	return "MongoDBConfig " + mongoCfg.Name
+}
+
+// SyncNowRequested reports whether the sync-now annotation has a value which hasn't been handled yet
+func (r *MongoDBConfig) SyncNowRequested() bool {
+	value := r.Annotations[SyncNowAnnotation]
+	return value != "" && value != r.Status.LastSyncNow
+}
+
+// SyncNowValue returns the sync-now value which its MongoDBData have to handle,
+// the last handled one is kept once the annotation has been removed
+func (r *MongoDBConfig) SyncNowValue() string {
+	if value := r.Annotations[SyncNowAnnotation]; value != "" {
+		return value
+	}
+	return r.Status.LastSyncNow
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncNowRequested(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		lastSyncNow string
+		want        bool
+	}{
+		{
+			name: "no annotation",
+			want: false,
+		},
+		{
+			name:        "empty annotation",
+			annotations: map[string]string{SyncNowAnnotation: ""},
+			want:        false,
+		},
+		{
+			name:        "new value",
+			annotations: map[string]string{SyncNowAnnotation: "2022-08-01T10:00:00Z"},
+			lastSyncNow: "2022-07-01T10:00:00Z",
+			want:        true,
+		},
+		{
+			name:        "handled value",
+			annotations: map[string]string{SyncNowAnnotation: "2022-08-01T10:00:00Z"},
+			lastSyncNow: "2022-08-01T10:00:00Z",
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Status:     MongoDBDataStatus{LastSyncNow: tt.lastSyncNow},
+			}
+			if got := mongoData.SyncNowRequested(); got != tt.want {
+				t.Errorf("MongoDBData.SyncNowRequested() = %v, want %v", got, tt.want)
+			}
+
+			mongoCfg := &MongoDBConfig{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Status:     MongoDBConfigStatus{LastSyncNow: tt.lastSyncNow},
+			}
+			if got := mongoCfg.SyncNowRequested(); got != tt.want {
+				t.Errorf("MongoDBConfig.SyncNowRequested() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigSyncNowRequested(t *testing.T) {
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		lastSyncNow       string
+		lastConfigSyncNow string
+		appliedGeneration int64
+		want              bool
+	}{
+		{
+			name:              "no sync-now value",
+			appliedGeneration: 1,
+			want:              false,
+		},
+		{
+			name:              "new annotation",
+			annotations:       map[string]string{SyncNowAnnotation: "2022-08-01T10:00:00Z"},
+			lastConfigSyncNow: "2022-07-01T10:00:00Z",
+			appliedGeneration: 1,
+			want:              true,
+		},
+		{
+			name:              "handled annotation",
+			annotations:       map[string]string{SyncNowAnnotation: "2022-08-01T10:00:00Z"},
+			lastConfigSyncNow: "2022-08-01T10:00:00Z",
+			appliedGeneration: 1,
+			want:              false,
+		},
+		{
+			name:              "removed annotation which was handled by the MongoDBConfig only",
+			lastSyncNow:       "2022-08-01T10:00:00Z",
+			appliedGeneration: 1,
+			want:              true,
+		},
+		{
+			name:        "never applied",
+			annotations: map[string]string{SyncNowAnnotation: "2022-08-01T10:00:00Z"},
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &MongoDBConfig{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Status:     MongoDBConfigStatus{LastSyncNow: tt.lastSyncNow},
+			}
+			mongoData := &MongoDBData{Status: MongoDBDataStatus{
+				LastConfigSyncNow: tt.lastConfigSyncNow,
+				AppliedGeneration: tt.appliedGeneration,
+			}}
+			if got := mongoData.ConfigSyncNowRequested(mongoCfg); got != tt.want {
+				t.Errorf("ConfigSyncNowRequested() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSuspended(t *testing.T) {
+	tests := []struct {
+		name          string
+		dataSuspend   bool
+		configSuspend bool
+		want          bool
+		wantBy        string
+	}{
+		{
+			name: "not suspended",
+			want: false,
+		},
+		{
+			name:        "suspended by the MongoDBData",
+			dataSuspend: true,
+			want:        true,
+			wantBy:      "spec.suspend",
+		},
+		{
+			name:          "suspended by the MongoDBConfig",
+			configSuspend: true,
+			want:          true,
+			wantBy:        "MongoDBConfig db",
+		},
+		{
+			name:          "suspended by both",
+			dataSuspend:   true,
+			configSuspend: true,
+			want:          true,
+			wantBy:        "spec.suspend",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &MongoDBData{Spec: MongoDBDataSpec{Suspend: tt.dataSuspend}}
+			mongoCfg := &MongoDBConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "db"},
+				Spec:       MongoDBConfigSpec{Suspend: tt.configSuspend},
+			}
+
+			if got := mongoData.IsSuspended(mongoCfg); got != tt.want {
+				t.Errorf("IsSuspended() = %v, want %v", got, tt.want)
+			}
+			if !tt.want {
+				return
+			}
+			if got := mongoData.SuspendedBy(mongoCfg); got != tt.wantBy {
+				t.Errorf("SuspendedBy() = %s, want %s", got, tt.wantBy)
+			}
+		})
+	}
+}
@@ -26,27 +26,885 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappedCollectionSpec) DeepCopyInto(out *CappedCollectionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappedCollectionSpec.
+func (in *CappedCollectionSpec) DeepCopy() *CappedCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CappedCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeStreamSpec) DeepCopyInto(out *ChangeStreamSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeStreamSpec.
+func (in *ChangeStreamSpec) DeepCopy() *ChangeStreamSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeStreamSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusteredIndexSpec) DeepCopyInto(out *ClusteredIndexSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusteredIndexSpec.
+func (in *ClusteredIndexSpec) DeepCopy() *ClusteredIndexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusteredIndexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConcurrencySpec) DeepCopyInto(out *ConcurrencySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConcurrencySpec.
+func (in *ConcurrencySpec) DeepCopy() *ConcurrencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConcurrencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsSecretReference) DeepCopyInto(out *CredentialsSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsSecretReference.
+func (in *CredentialsSecretReference) DeepCopy() *CredentialsSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabasePolicyRule) DeepCopyInto(out *DatabasePolicyRule) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabasePolicyRule.
+func (in *DatabasePolicyRule) DeepCopy() *DatabasePolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabasePolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionSpec) DeepCopyInto(out *EncryptionSpec) {
+	*out = *in
+	out.MasterKeySecretRef = in.MasterKeySecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionSpec.
+func (in *EncryptionSpec) DeepCopy() *EncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexCollation) DeepCopyInto(out *IndexCollation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexCollation.
+func (in *IndexCollation) DeepCopy() *IndexCollation {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexCollation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexKey) DeepCopyInto(out *IndexKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexKey.
+func (in *IndexKey) DeepCopy() *IndexKey {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONDocument) DeepCopyInto(out *JSONDocument) {
+	*out = *in
+	in.RawExtension.DeepCopyInto(&out.RawExtension)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONDocument.
+func (in *JSONDocument) DeepCopy() *JSONDocument {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONDocument)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalSecretKeyReference) DeepCopyInto(out *LocalSecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretKeyReference.
+func (in *LocalSecretKeyReference) DeepCopy() *LocalSecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalSecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBBulkData) DeepCopyInto(out *MongoDBBulkData) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBBulkData.
+func (in *MongoDBBulkData) DeepCopy() *MongoDBBulkData {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBBulkData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBBulkData) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBBulkDataList) DeepCopyInto(out *MongoDBBulkDataList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MongoDBBulkData, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBBulkDataList.
+func (in *MongoDBBulkDataList) DeepCopy() *MongoDBBulkDataList {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBBulkDataList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBBulkDataList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBBulkDataSpec) DeepCopyInto(out *MongoDBBulkDataSpec) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Documents != nil {
+		in, out := &in.Documents, &out.Documents
+		*out = make([]JSONDocument, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+	if in.SoftDelete != nil {
+		in, out := &in.SoftDelete, &out.SoftDelete
+		*out = new(SoftDeleteSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBBulkDataSpec.
+func (in *MongoDBBulkDataSpec) DeepCopy() *MongoDBBulkDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBBulkDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBBulkDataStatus) DeepCopyInto(out *MongoDBBulkDataStatus) {
+	*out = *in
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBBulkDataStatus.
+func (in *MongoDBBulkDataStatus) DeepCopy() *MongoDBBulkDataStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBBulkDataStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBCollection) DeepCopyInto(out *MongoDBCollection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBCollection.
+func (in *MongoDBCollection) DeepCopy() *MongoDBCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBCollection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBCollectionList) DeepCopyInto(out *MongoDBCollectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MongoDBCollection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBCollectionList.
+func (in *MongoDBCollectionList) DeepCopy() *MongoDBCollectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBCollectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBCollectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBCollectionSpec) DeepCopyInto(out *MongoDBCollectionSpec) {
+	*out = *in
+	if in.JSONSchema != nil {
+		in, out := &in.JSONSchema, &out.JSONSchema
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capped != nil {
+		in, out := &in.Capped, &out.Capped
+		*out = new(CappedCollectionSpec)
+		**out = **in
+	}
+	if in.TimeSeries != nil {
+		in, out := &in.TimeSeries, &out.TimeSeries
+		*out = new(TimeSeriesCollectionSpec)
+		**out = **in
+	}
+	if in.ClusteredIndex != nil {
+		in, out := &in.ClusteredIndex, &out.ClusteredIndex
+		*out = new(ClusteredIndexSpec)
+		**out = **in
+	}
+	if in.ExpireAfterSeconds != nil {
+		in, out := &in.ExpireAfterSeconds, &out.ExpireAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Collation != nil {
+		in, out := &in.Collation, &out.Collation
+		*out = new(IndexCollation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBCollectionSpec.
+func (in *MongoDBCollectionSpec) DeepCopy() *MongoDBCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBCollectionStatus) DeepCopyInto(out *MongoDBCollectionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBCollectionStatus.
+func (in *MongoDBCollectionStatus) DeepCopy() *MongoDBCollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBCollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MongoDBConfig) DeepCopyInto(out *MongoDBConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfig.
+func (in *MongoDBConfig) DeepCopy() *MongoDBConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBConfigList) DeepCopyInto(out *MongoDBConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MongoDBConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfigList.
+func (in *MongoDBConfigList) DeepCopy() *MongoDBConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBConfigSpec) DeepCopyInto(out *MongoDBConfigSpec) {
+	*out = *in
+	if in.MongoURLSecretRef != nil {
+		in, out := &in.MongoURLSecretRef, &out.MongoURLSecretRef
+		*out = new(SecretKeyReference)
+		**out = **in
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(CredentialsSecretReference)
+		**out = **in
+	}
+	if in.SoftDelete != nil {
+		in, out := &in.SoftDelete, &out.SoftDelete
+		*out = new(SoftDeleteSpec)
+		**out = **in
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(MongoDBTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChangeStream != nil {
+		in, out := &in.ChangeStream, &out.ChangeStream
+		*out = new(ChangeStreamSpec)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(EncryptionSpec)
+		**out = **in
+	}
+	if in.AllowedCollections != nil {
+		in, out := &in.AllowedCollections, &out.AllowedCollections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(MongoDBDatabaseSpec)
+		**out = **in
+	}
+	if in.DatabasePolicy != nil {
+		in, out := &in.DatabasePolicy, &out.DatabasePolicy
+		*out = make([]DatabasePolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfigSpec.
+func (in *MongoDBConfigSpec) DeepCopy() *MongoDBConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBConfigStatus) DeepCopyInto(out *MongoDBConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfigStatus.
+func (in *MongoDBConfigStatus) DeepCopy() *MongoDBConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBData) DeepCopyInto(out *MongoDBData) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBData.
+func (in *MongoDBData) DeepCopy() *MongoDBData {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBData) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDataAdopt) DeepCopyInto(out *MongoDBDataAdopt) {
+	*out = *in
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataAdopt.
+func (in *MongoDBDataAdopt) DeepCopy() *MongoDBDataAdopt {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDataAdopt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDataFrom) DeepCopyInto(out *MongoDBDataFrom) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(LocalSecretKeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataFrom.
+func (in *MongoDBDataFrom) DeepCopy() *MongoDBDataFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDataFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDataList) DeepCopyInto(out *MongoDBDataList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MongoDBData, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataList.
+func (in *MongoDBDataList) DeepCopy() *MongoDBDataList {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDataList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBDataList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDataReference) DeepCopyInto(out *MongoDBDataReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataReference.
+func (in *MongoDBDataReference) DeepCopy() *MongoDBDataReference {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDataReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDataSpec) DeepCopyInto(out *MongoDBDataSpec) {
+	*out = *in
+	in.Data.DeepCopyInto(&out.Data)
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = make([]MongoDBDataFrom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecretFields != nil {
+		in, out := &in.SecretFields, &out.SecretFields
+		*out = make([]MongoDBSecretField, len(*in))
+		copy(*out, *in)
+	}
+	if in.References != nil {
+		in, out := &in.References, &out.References
+		*out = make([]MongoDBDataReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.EncryptedFields != nil {
+		in, out := &in.EncryptedFields, &out.EncryptedFields
+		*out = make([]MongoDBEncryptedField, len(*in))
+		copy(*out, *in)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(MongoDBDatabaseSpec)
+		**out = **in
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Adopt != nil {
+		in, out := &in.Adopt, &out.Adopt
+		*out = new(MongoDBDataAdopt)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SoftDelete != nil {
+		in, out := &in.SoftDelete, &out.SoftDelete
+		*out = new(SoftDeleteSpec)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(ConcurrencySpec)
+		**out = **in
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataSpec.
+func (in *MongoDBDataSpec) DeepCopy() *MongoDBDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDataStatus) DeepCopyInto(out *MongoDBDataStatus) {
+	*out = *in
+	if in.ManagedFields != nil {
+		in, out := &in.ManagedFields, &out.ManagedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedWrite != nil {
+		in, out := &in.PlannedWrite, &out.PlannedWrite
+		*out = new(MongoDBDataWrite)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataStatus.
+func (in *MongoDBDataStatus) DeepCopy() *MongoDBDataStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDataStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDataWrite) DeepCopyInto(out *MongoDBDataWrite) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataWrite.
+func (in *MongoDBDataWrite) DeepCopy() *MongoDBDataWrite {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDataWrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBDatabaseSpec) DeepCopyInto(out *MongoDBDatabaseSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDatabaseSpec.
+func (in *MongoDBDatabaseSpec) DeepCopy() *MongoDBDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBEncryptedField) DeepCopyInto(out *MongoDBEncryptedField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBEncryptedField.
+func (in *MongoDBEncryptedField) DeepCopy() *MongoDBEncryptedField {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBEncryptedField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBIndex) DeepCopyInto(out *MongoDBIndex) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfig.
-func (in *MongoDBConfig) DeepCopy() *MongoDBConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBIndex.
+func (in *MongoDBIndex) DeepCopy() *MongoDBIndex {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBConfig)
+	out := new(MongoDBIndex)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MongoDBConfig) DeepCopyObject() runtime.Object {
+func (in *MongoDBIndex) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -54,31 +912,31 @@ func (in *MongoDBConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBConfigList) DeepCopyInto(out *MongoDBConfigList) {
+func (in *MongoDBIndexList) DeepCopyInto(out *MongoDBIndexList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MongoDBConfig, len(*in))
+		*out = make([]MongoDBIndex, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfigList.
-func (in *MongoDBConfigList) DeepCopy() *MongoDBConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBIndexList.
+func (in *MongoDBIndexList) DeepCopy() *MongoDBIndexList {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBConfigList)
+	out := new(MongoDBIndexList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MongoDBConfigList) DeepCopyObject() runtime.Object {
+func (in *MongoDBIndexList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -86,22 +944,42 @@ func (in *MongoDBConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBConfigSpec) DeepCopyInto(out *MongoDBConfigSpec) {
+func (in *MongoDBIndexSpec) DeepCopyInto(out *MongoDBIndexSpec) {
 	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]IndexKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.PartialFilterExpression != nil {
+		in, out := &in.PartialFilterExpression, &out.PartialFilterExpression
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpireAfterSeconds != nil {
+		in, out := &in.ExpireAfterSeconds, &out.ExpireAfterSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Collation != nil {
+		in, out := &in.Collation, &out.Collation
+		*out = new(IndexCollation)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfigSpec.
-func (in *MongoDBConfigSpec) DeepCopy() *MongoDBConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBIndexSpec.
+func (in *MongoDBIndexSpec) DeepCopy() *MongoDBIndexSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBConfigSpec)
+	out := new(MongoDBIndexSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBConfigStatus) DeepCopyInto(out *MongoDBConfigStatus) {
+func (in *MongoDBIndexStatus) DeepCopyInto(out *MongoDBIndexStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -112,37 +990,37 @@ func (in *MongoDBConfigStatus) DeepCopyInto(out *MongoDBConfigStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBConfigStatus.
-func (in *MongoDBConfigStatus) DeepCopy() *MongoDBConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBIndexStatus.
+func (in *MongoDBIndexStatus) DeepCopy() *MongoDBIndexStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBConfigStatus)
+	out := new(MongoDBIndexStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBData) DeepCopyInto(out *MongoDBData) {
+func (in *MongoDBRole) DeepCopyInto(out *MongoDBRole) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBData.
-func (in *MongoDBData) DeepCopy() *MongoDBData {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBRole.
+func (in *MongoDBRole) DeepCopy() *MongoDBRole {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBData)
+	out := new(MongoDBRole)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MongoDBData) DeepCopyObject() runtime.Object {
+func (in *MongoDBRole) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -150,46 +1028,180 @@ func (in *MongoDBData) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBDataField) DeepCopyInto(out *MongoDBDataField) {
+func (in *MongoDBRoleList) DeepCopyInto(out *MongoDBRoleList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MongoDBRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataField.
-func (in *MongoDBDataField) DeepCopy() *MongoDBDataField {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBRoleList.
+func (in *MongoDBRoleList) DeepCopy() *MongoDBRoleList {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBDataField)
+	out := new(MongoDBRoleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBDataList) DeepCopyInto(out *MongoDBDataList) {
+func (in *MongoDBRoleSpec) DeepCopyInto(out *MongoDBRoleSpec) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]RolePrivilege, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBRoleSpec.
+func (in *MongoDBRoleSpec) DeepCopy() *MongoDBRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBRoleStatus) DeepCopyInto(out *MongoDBRoleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBRoleStatus.
+func (in *MongoDBRoleStatus) DeepCopy() *MongoDBRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBSecretField) DeepCopyInto(out *MongoDBSecretField) {
+	*out = *in
+	out.SecretKeyRef = in.SecretKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBSecretField.
+func (in *MongoDBSecretField) DeepCopy() *MongoDBSecretField {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBSecretField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBTLSConfig) DeepCopyInto(out *MongoDBTLSConfig) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(SecretKeyReference)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBTLSConfig.
+func (in *MongoDBTLSConfig) DeepCopy() *MongoDBTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBUser) DeepCopyInto(out *MongoDBUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBUser.
+func (in *MongoDBUser) DeepCopy() *MongoDBUser {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MongoDBUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBUserList) DeepCopyInto(out *MongoDBUserList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MongoDBData, len(*in))
+		*out = make([]MongoDBUser, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataList.
-func (in *MongoDBDataList) DeepCopy() *MongoDBDataList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBUserList.
+func (in *MongoDBUserList) DeepCopy() *MongoDBUserList {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBDataList)
+	out := new(MongoDBUserList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MongoDBDataList) DeepCopyObject() runtime.Object {
+func (in *MongoDBUserList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -197,24 +1209,33 @@ func (in *MongoDBDataList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBDataSpec) DeepCopyInto(out *MongoDBDataSpec) {
+func (in *MongoDBUserSpec) DeepCopyInto(out *MongoDBUserSpec) {
 	*out = *in
-	out.Data = in.Data
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataSpec.
-func (in *MongoDBDataSpec) DeepCopy() *MongoDBDataSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBUserSpec.
+func (in *MongoDBUserSpec) DeepCopy() *MongoDBUserSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBDataSpec)
+	out := new(MongoDBUserSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MongoDBDataStatus) DeepCopyInto(out *MongoDBDataStatus) {
+func (in *MongoDBUserStatus) DeepCopyInto(out *MongoDBUserStatus) {
 	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -224,12 +1245,123 @@ func (in *MongoDBDataStatus) DeepCopyInto(out *MongoDBDataStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBDataStatus.
-func (in *MongoDBDataStatus) DeepCopy() *MongoDBDataStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBUserStatus.
+func (in *MongoDBUserStatus) DeepCopy() *MongoDBUserStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MongoDBDataStatus)
+	out := new(MongoDBUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivilegeResource) DeepCopyInto(out *PrivilegeResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivilegeResource.
+func (in *PrivilegeResource) DeepCopy() *PrivilegeResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivilegeResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolePrivilege) DeepCopyInto(out *RolePrivilege) {
+	*out = *in
+	out.Resource = in.Resource
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolePrivilege.
+func (in *RolePrivilege) DeepCopy() *RolePrivilege {
+	if in == nil {
+		return nil
+	}
+	out := new(RolePrivilege)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleReference) DeepCopyInto(out *RoleReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleReference.
+func (in *RoleReference) DeepCopy() *RoleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SoftDeleteSpec) DeepCopyInto(out *SoftDeleteSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SoftDeleteSpec.
+func (in *SoftDeleteSpec) DeepCopy() *SoftDeleteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SoftDeleteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeSeriesCollectionSpec) DeepCopyInto(out *TimeSeriesCollectionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeSeriesCollectionSpec.
+func (in *TimeSeriesCollectionSpec) DeepCopy() *TimeSeriesCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeSeriesCollectionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
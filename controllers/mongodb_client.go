@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	corev1 "k8s.io/api/core/v1"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// mongoConnection is the resolved connection settings of a MongoDBConfig
+type mongoConnection struct {
+	opts mongodb.ConnectionOptions
+
+	// encryption is set when client-side field level encryption is enabled
+	encryption *mongodb.EncryptionOptions
+
+	// version changes whenever the spec of the MongoDBConfig or one of its secrets changes,
+	// status and metadata updates of the MongoDBConfig keep the connection
+	version string
+}
+
+// secretError is returned when a secret referenced by a MongoDBConfig cannot be resolved
+type secretError struct {
+	err error
+}
+
+func (e *secretError) Error() string {
+	return e.err.Error()
+}
+
+// getMongoClient returns the shared mongodb client of the given MongoDBConfig
+func getMongoClient(
+	ctx context.Context,
+	c client.Client,
+	clients *mongodb.ClientManager,
+	mongoCfg *mongov1.MongoDBConfig,
+) (*mongo.Client, error) {
+
+	conn, err := resolveMongoConnection(ctx, c, mongoCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.Get(ctx, mongoCfg.UID, conn.version, conn.opts)
+}
+
+// getEncrypter returns the shared field encrypter of the given MongoDBConfig
+func getEncrypter(
+	ctx context.Context,
+	c client.Client,
+	clients *mongodb.ClientManager,
+	mongoCfg *mongov1.MongoDBConfig,
+) (*mongodb.Encrypter, error) {
+
+	conn, err := resolveMongoConnection(ctx, c, mongoCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn.encryption == nil {
+		return nil, fmt.Errorf("encryption of MongoDBConfig %s is not enabled", mongoCfg.Name)
+	}
+
+	if _, err := clients.Get(ctx, mongoCfg.UID, conn.version, conn.opts); err != nil {
+		return nil, err
+	}
+
+	return clients.Encrypter(ctx, mongoCfg.UID, conn.version, *conn.encryption)
+}
+
+// resolveMongoConnection assembles the mongodb connection url of the given
+// MongoDBConfig from its spec and referenced secrets
+func resolveMongoConnection(ctx context.Context, c client.Client, mongoCfg *mongov1.MongoDBConfig) (*mongoConnection, error) {
+
+	versions := []string{strconv.FormatInt(mongoCfg.Generation, 10)}
+	mongoURL := mongoCfg.Spec.MongoURL
+
+	if ref := mongoCfg.Spec.MongoURLSecretRef; ref != nil {
+
+		secret, err := getSecret(ctx, c, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := secretValue(secret, ref.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		mongoURL = value
+		versions = append(versions, secret.ResourceVersion)
+	}
+
+	if ref := mongoCfg.Spec.CredentialsSecretRef; ref != nil {
+
+		secret, err := getSecret(ctx, c, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		username, err := secretValue(secret, ref.UsernameKey)
+		if err != nil {
+			return nil, err
+		}
+
+		password, err := secretValue(secret, ref.PasswordKey)
+		if err != nil {
+			return nil, err
+		}
+
+		mongoURL, err = mongodb.BuildURL(mongoURL, username, password)
+		if err != nil {
+			return nil, &secretError{err}
+		}
+
+		versions = append(versions, secret.ResourceVersion)
+	}
+
+	if mongoURL == "" {
+		return nil, fmt.Errorf("no mongodb connection url specified")
+	}
+
+	opts := mongodb.ConnectionOptions{URL: mongoURL}
+
+	if tlsCfg := mongoCfg.Spec.TLS; tlsCfg != nil {
+
+		opts.TLS = &mongodb.TLSOptions{
+			InsecureSkipVerify: tlsCfg.InsecureSkipVerify,
+			ServerName:         tlsCfg.ServerName,
+		}
+
+		if ref := tlsCfg.CASecretRef; ref != nil {
+
+			secret, err := getSecret(ctx, c, ref.Namespace, ref.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			ca, err := secretValue(secret, ref.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			opts.TLS.CA = []byte(ca)
+			versions = append(versions, secret.ResourceVersion)
+		}
+
+		if ref := tlsCfg.ClientCertSecretRef; ref != nil {
+
+			secret, err := getSecret(ctx, c, ref.Namespace, ref.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			cert, err := secretValue(secret, corev1.TLSCertKey)
+			if err != nil {
+				return nil, err
+			}
+
+			key, err := secretValue(secret, corev1.TLSPrivateKeyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			opts.TLS.ClientCert = []byte(cert)
+			opts.TLS.ClientKey = []byte(key)
+			versions = append(versions, secret.ResourceVersion)
+		}
+	}
+
+	var encryption *mongodb.EncryptionOptions
+	if enc := mongoCfg.Spec.Encryption; enc != nil {
+
+		ref := enc.MasterKeySecretRef
+		secret, err := getSecret(ctx, c, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := secretValue(secret, ref.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		masterKey, err := mongodb.ParseMasterKey([]byte(value))
+		if err != nil {
+			return nil, &secretError{fmt.Errorf("secret %s/%s key %s: %v", ref.Namespace, ref.Name, ref.Key, err)}
+		}
+
+		encryption = &mongodb.EncryptionOptions{
+			MasterKey:         masterKey,
+			KeyVaultNamespace: enc.KeyVaultNamespace,
+			KeyAltName:        enc.KeyAltName,
+		}
+		versions = append(versions, secret.ResourceVersion)
+	}
+
+	return &mongoConnection{
+		opts:       opts,
+		encryption: encryption,
+		version:    strings.Join(versions, "/"),
+	}, nil
+}
+
+func getSecret(ctx context.Context, c client.Client, namespace, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, k8sTypes.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, &secretError{fmt.Errorf("could not get secret %s/%s: %v", namespace, name, err)}
+	}
+	return secret, nil
+}
+
+func secretValue(secret *corev1.Secret, key string) (string, error) {
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", &secretError{fmt.Errorf("secret %s/%s has no key %s", secret.Namespace, secret.Name, key)}
+	}
+	return string(value), nil
+}
@@ -0,0 +1,390 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/go-logr/logr"
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+	"github.com/pingcap/errors"
+)
+
+const (
+	// configMapRefIndexKey indexes the MongoDBBulkData by their referenced ConfigMaps
+	configMapRefIndexKey = ".spec.configMapRef.name"
+
+	// maxBulkDataErrors is the number of document errors which are kept in the status
+	maxBulkDataErrors = 10
+)
+
+var (
+	mongoDBBulkDataFinalizerName = "mongo.snappcloud.io/mongodb-bulkdata-finalizer"
+)
+
+// MongoDBBulkDataReconciler reconciles a MongoDBBulkData object
+type MongoDBBulkDataReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MongoClients is the shared mongodb client pool
+	MongoClients *mongodb.ClientManager
+}
+
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbbulkdata,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbbulkdata/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbbulkdata/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile upserts the documents of a MongoDBBulkData by their keys and prunes
+// the documents which have been removed from it under the prune policy
+func (r *MongoDBBulkDataReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	log := r.Log.WithValues("mongodb-bulkdata", req.NamespacedName)
+	log.Info("Reconciling MongoDBBulkData")
+
+	bulkData := &mongov1.MongoDBBulkData{}
+	if err := r.Client.Get(ctx, req.NamespacedName, bulkData); err != nil {
+		if errors.IsNotFound(err) {
+			// don't requeue on deletions, which yield a non-found object
+			log.Info("ignoring", "reason", "not found", "err", err)
+		}
+		return requeue(client.IgnoreNotFound(err))
+	}
+
+	// Check if the MongoDBConfig exists
+	mongoCfg := &mongov1.MongoDBConfig{}
+	if err := r.Client.Get(ctx, k8sTypes.NamespacedName{Name: bulkData.Spec.DB}, mongoCfg); err != nil {
+
+		if errors.IsNotFound(err) {
+
+			message := fmt.Sprintf("MongoDBConfig with name %s doesn't exists", bulkData.Spec.DB)
+			if err := r.setEventStatusPending(ctx, bulkData, message); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			log.Info("ignoring", "reason", message)
+			return requeueWithDelay(20 * time.Second)
+		}
+
+		log.Error(err, fmt.Sprintf("failed to get the mongo-config %s", bulkData.Spec.DB))
+		return requeue(err)
+	}
+
+	// get the shared mongodb client of the MongoDBConfig
+	mongoClient, err := getMongoClient(ctx, r.Client, r.MongoClients, mongoCfg)
+	if err != nil {
+
+		if err := r.setEventStatusPending(ctx, bulkData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	collection := mongoClient.Database(bulkData.Spec.Database).Collection(bulkData.Spec.Collection)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if bulkData.ObjectMeta.DeletionTimestamp.IsZero() {
+
+		// register our finalizer, so the documents get pruned on deletion
+		if controllerutil.AddFinalizer(bulkData, mongoDBBulkDataFinalizerName) {
+			if err := r.Client.Update(ctx, bulkData); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+	} else {
+
+		if controllerutil.ContainsFinalizer(bulkData, mongoDBBulkDataFinalizerName) {
+
+			// all of the owned documents are pruned
+			if _, err := r.pruneDocuments(ctx, collection, bulkData, mongoCfg, bulkData.PruneFilter(nil)); err != nil {
+				log.Error(err, "unable to prune the documents from mongodb")
+				return requeue(err)
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(bulkData, mongoDBBulkDataFinalizerName)
+			if err := r.Client.Update(ctx, bulkData); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return doNotRequeue()
+	}
+
+	if err := mongoCfg.CheckBulkData(bulkData); err != nil {
+
+		if err := r.setEventStatusFailed(ctx, bulkData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	raws, pending, err := r.loadDocuments(ctx, bulkData)
+	if err != nil {
+
+		setStatus := r.setEventStatusFailed
+		if pending {
+			setStatus = r.setEventStatusPending
+		}
+
+		if err := setStatus(ctx, bulkData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		// the ConfigMap is watched, so it's retried when it changes
+		return doNotRequeue()
+	}
+
+	docs, docErrs := bulkData.BulkDocuments(raws)
+
+	result, err := mongodb.BulkUpsert(ctx, collection, docs, mongov1.BulkDataOwnerField, bulkData.Owner())
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, bulkData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	// the removed documents can't be told apart from the invalid ones,
+	// so nothing is pruned until all of the documents are valid
+	var pruned int64
+	if len(docErrs) == 0 {
+		if pruned, err = r.pruneDocuments(ctx, collection, bulkData, mongoCfg, bulkData.PruneFilter(docs)); err != nil {
+			log.Error(err, "unable to prune the documents from mongodb")
+			return requeue(err)
+		}
+	}
+
+	failed := result.Failed + int64(len(docErrs))
+	errs := append(docErrs, result.Errors...)
+	if len(errs) > maxBulkDataErrors {
+		errs = errs[:maxBulkDataErrors]
+	}
+
+	state := mongov1.MongoDBBulkDataConditionApplied
+	if failed > 0 {
+		state = mongov1.MongoDBBulkDataConditionPartiallyApplied
+	}
+
+	// the counts of an apply which didn't write anything don't replace the last ones
+	written := result.Inserted+result.Updated+pruned > 0
+	cond := apimeta.FindStatusCondition(bulkData.Status.Conditions, string(state))
+	if written ||
+		cond == nil || cond.ObservedGeneration != bulkData.Generation ||
+		bulkData.Status.State != string(state) ||
+		bulkData.Status.Documents != int64(len(raws)) ||
+		bulkData.Status.Failed != failed {
+
+		bulkData.Status.Documents = int64(len(raws))
+		bulkData.Status.Inserted = result.Inserted
+		bulkData.Status.Updated = result.Updated
+		bulkData.Status.Pruned = pruned
+		bulkData.Status.Failed = failed
+		bulkData.Status.Errors = errs
+
+		msg := fmt.Sprintf(
+			"%d documents applied: %d inserted, %d updated, %d pruned, %d failed",
+			len(raws), result.Inserted, result.Updated, pruned, failed,
+		)
+
+		setStatus := r.setEventStatusApplied
+		if state == mongov1.MongoDBBulkDataConditionPartiallyApplied {
+			setStatus = r.setEventStatusPartiallyApplied
+		}
+
+		if err := setStatus(ctx, bulkData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	return r.resync(mongoCfg)
+}
+
+// loadDocuments returns the raw json documents of the MongoDBBulkData,
+// pending is true when the ConfigMap or its key doesn't exist yet
+func (r *MongoDBBulkDataReconciler) loadDocuments(ctx context.Context, bulkData *mongov1.MongoDBBulkData) (raws [][]byte, pending bool, err error) {
+
+	ref := bulkData.Spec.ConfigMapRef
+	if ref == nil {
+		for _, doc := range bulkData.Spec.Documents {
+			raws = append(raws, doc.Raw)
+		}
+		return raws, false, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, k8sTypes.NamespacedName{Namespace: bulkData.Namespace, Name: ref.Name}, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, true, fmt.Errorf("ConfigMap with name %s doesn't exists", ref.Name)
+		}
+		return nil, false, err
+	}
+
+	data, ok := configMap.Data[ref.Key]
+	if !ok {
+		binaryData, ok := configMap.BinaryData[ref.Key]
+		if !ok {
+			return nil, true, fmt.Errorf("key %s is not found in ConfigMap %s", ref.Key, ref.Name)
+		}
+		data = string(binaryData)
+	}
+
+	raws, err = mongodb.ParseDocuments([]byte(data))
+	return raws, false, err
+}
+
+// pruneDocuments applies the prune policy to the documents of the filter, retained documents
+// are released by removing the owner field, so they are only pruned once
+func (r *MongoDBBulkDataReconciler) pruneDocuments(
+	ctx context.Context,
+	coll *mongo.Collection,
+	bulkData *mongov1.MongoDBBulkData,
+	mongoCfg *mongov1.MongoDBConfig,
+	filter bson.D,
+) (int64, error) {
+
+	release := bson.M{mongov1.BulkDataOwnerField: ""}
+
+	switch mongoCfg.PrunePolicyFor(bulkData) {
+	case mongov1.DeletionPolicyRetain:
+
+		res, err := coll.UpdateMany(ctx, filter, bson.M{"$unset": release})
+		if err != nil {
+			return 0, err
+		}
+		return res.ModifiedCount, nil
+
+	case mongov1.DeletionPolicySoftDelete:
+
+		softDelete := mongoCfg.BulkDataSoftDeleteFor(bulkData)
+
+		var value interface{} = true
+		if softDelete.Type == mongov1.SoftDeleteTypeTimestamp {
+			value = primitive.NewDateTimeFromTime(time.Now())
+		}
+
+		res, err := coll.UpdateMany(ctx, filter, bson.M{"$set": bson.M{softDelete.Field: value}, "$unset": release})
+		if err != nil {
+			return 0, err
+		}
+		return res.ModifiedCount, nil
+	}
+
+	res, err := coll.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// resync requeues the MongoDBBulkData after the resync interval of the MongoDBConfig,
+// so documents which are changed directly in mongodb are corrected
+func (r *MongoDBBulkDataReconciler) resync(mongoCfg *mongov1.MongoDBConfig) (ctrl.Result, error) {
+	if mongoCfg.Spec.ResyncInterval == nil || mongoCfg.Spec.ResyncInterval.Duration <= 0 {
+		return doNotRequeue()
+	}
+	return requeueWithDelay(mongoCfg.Spec.ResyncInterval.Duration)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MongoDBBulkDataReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	// index the MongoDBBulkData by their ConfigMaps, so changes of a ConfigMap are applied
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&mongov1.MongoDBBulkData{},
+		configMapRefIndexKey,
+		func(obj client.Object) []string {
+			bulkData := obj.(*mongov1.MongoDBBulkData)
+			if bulkData.Spec.ConfigMapRef == nil {
+				return nil
+			}
+			return []string{bulkData.Spec.ConfigMapRef.Name}
+		},
+	); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mongov1.MongoDBBulkData{}).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.findBulkDataForConfigMap),
+		).
+		Complete(r)
+}
+
+// findBulkDataForConfigMap returns the MongoDBBulkData which are referencing the ConfigMap
+func (r *MongoDBBulkDataReconciler) findBulkDataForConfigMap(configMap client.Object) []reconcile.Request {
+
+	bulkDataList := &mongov1.MongoDBBulkDataList{}
+	if err := r.Client.List(
+		context.Background(),
+		bulkDataList,
+		client.InNamespace(configMap.GetNamespace()),
+		client.MatchingFields{configMapRefIndexKey: configMap.GetName()},
+	); err != nil {
+		r.Log.Error(err, "unable to list the MongoDBBulkData of the ConfigMap", "configmap", configMap.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(bulkDataList.Items))
+	for _, bulkData := range bulkDataList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: k8sTypes.NamespacedName{Namespace: bulkData.Namespace, Name: bulkData.Name},
+		})
+	}
+
+	return requests
+}
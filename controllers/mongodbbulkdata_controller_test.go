@@ -0,0 +1,103 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+)
+
+func TestLoadDocuments(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "seed"},
+		Data:       map[string]string{"docs.json": `[{"code":"a"},{"code":"b"}]`},
+		BinaryData: map[string][]byte{"docs.ndjson": []byte("{\"code\":\"c\"}\n")},
+	}
+
+	tests := []struct {
+		name        string
+		spec        mongov1.MongoDBBulkDataSpec
+		want        []string
+		wantPending bool
+		wantErr     bool
+	}{
+		{
+			name: "inline documents",
+			spec: mongov1.MongoDBBulkDataSpec{Documents: []mongov1.JSONDocument{
+				{RawExtension: runtime.RawExtension{Raw: []byte(`{"code":"a"}`)}},
+			}},
+			want: []string{`{"code":"a"}`},
+		},
+		{
+			name: "configmap data",
+			spec: mongov1.MongoDBBulkDataSpec{ConfigMapRef: &mongov1.ConfigMapKeyReference{Name: "seed", Key: "docs.json"}},
+			want: []string{`{"code":"a"}`, `{"code":"b"}`},
+		},
+		{
+			name: "configmap binary data",
+			spec: mongov1.MongoDBBulkDataSpec{ConfigMapRef: &mongov1.ConfigMapKeyReference{Name: "seed", Key: "docs.ndjson"}},
+			want: []string{`{"code":"c"}`},
+		},
+		{
+			name:        "missing configmap",
+			spec:        mongov1.MongoDBBulkDataSpec{ConfigMapRef: &mongov1.ConfigMapKeyReference{Name: "other", Key: "docs.json"}},
+			wantPending: true,
+			wantErr:     true,
+		},
+		{
+			name:        "missing key",
+			spec:        mongov1.MongoDBBulkDataSpec{ConfigMapRef: &mongov1.ConfigMapKeyReference{Name: "seed", Key: "other"}},
+			wantPending: true,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newTestScheme(t)
+			r := &MongoDBBulkDataReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+				Scheme: scheme,
+			}
+
+			bulkData := &mongov1.MongoDBBulkData{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}, Spec: tt.spec}
+			raws, pending, err := r.loadDocuments(context.Background(), bulkData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadDocuments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if pending != tt.wantPending {
+				t.Errorf("loadDocuments() pending = %v, want %v", pending, tt.wantPending)
+			}
+
+			var got []string
+			for _, raw := range raws {
+				got = append(got, string(raw))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadDocuments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
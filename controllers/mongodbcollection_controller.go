@@ -0,0 +1,271 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/go-logr/logr"
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+	"github.com/pingcap/errors"
+)
+
+var (
+	mongoDBCollectionFinalizerName = "mongo.snappcloud.io/mongodb-collection-finalizer"
+)
+
+// MongoDBCollectionReconciler reconciles a MongoDBCollection object
+type MongoDBCollectionReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MongoClients is the shared mongodb client pool
+	MongoClients *mongodb.ClientManager
+}
+
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbcollections,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbcollections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbcollections/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates the collection of a MongoDBCollection and applies its
+// validator and options with collMod whenever they differ from the spec
+func (r *MongoDBCollectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	log := r.Log.WithValues("mongodb-collection", req.NamespacedName)
+	log.Info("Reconciling MongoDBCollection")
+
+	mongoColl := &mongov1.MongoDBCollection{}
+	if err := r.Client.Get(ctx, req.NamespacedName, mongoColl); err != nil {
+		if errors.IsNotFound(err) {
+			// don't requeue on deletions, which yield a non-found object
+			log.Info("ignoring", "reason", "not found", "err", err)
+		}
+		return requeue(client.IgnoreNotFound(err))
+	}
+
+	// Check if the MongoDBConfig exists
+	mongoCfg := &mongov1.MongoDBConfig{}
+	if err := r.Client.Get(ctx, k8sTypes.NamespacedName{Name: mongoColl.Spec.DB}, mongoCfg); err != nil {
+
+		if errors.IsNotFound(err) {
+
+			message := fmt.Sprintf("MongoDBConfig with name %s doesn't exists", mongoColl.Spec.DB)
+			if err := r.setEventStatusPending(ctx, mongoColl, message); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			log.Info("ignoring", "reason", message)
+			return requeueWithDelay(20 * time.Second)
+		}
+
+		log.Error(err, fmt.Sprintf("failed to get the mongo-config %s", mongoColl.Spec.DB))
+		return requeue(err)
+	}
+
+	// get the shared mongodb client of the MongoDBConfig
+	mongoClient, err := getMongoClient(ctx, r.Client, r.MongoClients, mongoCfg)
+	if err != nil {
+
+		if err := r.setEventStatusPending(ctx, mongoColl, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	database := mongoClient.Database(mongoColl.Spec.Database)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if mongoColl.ObjectMeta.DeletionTimestamp.IsZero() {
+
+		// register our finalizer, so the collection can be dropped on deletion
+		if controllerutil.AddFinalizer(mongoColl, mongoDBCollectionFinalizerName) {
+			if err := r.Client.Update(ctx, mongoColl); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+	} else {
+
+		if controllerutil.ContainsFinalizer(mongoColl, mongoDBCollectionFinalizerName) {
+
+			// only a collection created by this MongoDBCollection is dropped,
+			// and only while the MongoDBConfig still allows it
+			if mongoColl.Spec.DeletionPolicy == mongov1.CollectionDeletionDrop && mongoColl.Status.Created {
+
+				if err := mongoCfg.CheckCollection(mongoColl); err != nil {
+					log.Info("not dropping the collection", "reason", err.Error())
+				} else if err := mongodb.DropCollection(ctx, database, mongoColl.Spec.Collection); err != nil {
+					log.Error(err, "unable to drop the collection from mongodb")
+					return requeue(err)
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(mongoColl, mongoDBCollectionFinalizerName)
+			if err := r.Client.Update(ctx, mongoColl); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return doNotRequeue()
+	}
+
+	if err := mongoCfg.CheckCollection(mongoColl); err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoColl, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	spec, err := mongoColl.CollectionSpec()
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoColl, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	live, exists, err := mongodb.FindCollection(ctx, database, spec.Name)
+	if err != nil {
+		log.Error(err, "unable to list the collections of the database")
+		return requeue(err)
+	}
+
+	msg := ""
+
+	if !exists {
+
+		if err := mongodb.CreateCollection(ctx, database, spec); err != nil {
+			return r.failed(ctx, log, mongoColl, err)
+		}
+
+		mongoColl.Status.Created = true
+		msg = "Collection has been created"
+
+	} else {
+
+		diff, err := mongodb.CollectionDiff(live, spec)
+		if err != nil {
+			log.Error(err, "unable to compare the collection with its spec")
+			return requeue(err)
+		}
+
+		if len(diff) > 0 {
+
+			if err := mongodb.ModifyCollection(ctx, database, spec); err != nil {
+				return r.failed(ctx, log, mongoColl, err)
+			}
+
+			msg = fmt.Sprintf("Collection differed from spec in %s, it has been modified", strings.Join(diff, ", "))
+		}
+
+		// the options which are only set on creation can't be corrected with collMod
+		immutable, err := mongodb.ImmutableCollectionDiff(live, spec)
+		if err != nil {
+			log.Error(err, "unable to compare the collection with its spec")
+			return requeue(err)
+		}
+
+		drifted := apimeta.IsStatusConditionTrue(mongoColl.Status.Conditions, string(mongov1.MongoDBCollectionConditionImmutableDrift))
+		if len(immutable) > 0 {
+
+			driftMsg := fmt.Sprintf("Collection differs from spec in %s, which can only be set when it's created",
+				strings.Join(immutable, ", "))
+			cond := apimeta.FindStatusCondition(mongoColl.Status.Conditions, string(mongov1.MongoDBCollectionConditionImmutableDrift))
+			if !drifted || cond.Message != driftMsg {
+				if err := r.setEventImmutableDrift(ctx, mongoColl, driftMsg); err != nil {
+					log.Error(err, "unable to update target's status object")
+					return requeue(err)
+				}
+			}
+
+		} else if drifted {
+			if err := r.setEventImmutableInSync(ctx, mongoColl, "Collection matches the creation options of spec"); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+		}
+	}
+
+	if msg != "" || !mongoColl.IsReady() {
+		if msg == "" {
+			msg = "Collection matches spec"
+		}
+		if err := r.setEventStatusReady(ctx, mongoColl, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	// re-read the collection periodically, so changes made directly in mongodb are corrected
+	if mongoCfg.Spec.ResyncInterval != nil && mongoCfg.Spec.ResyncInterval.Duration > 0 {
+		return requeueWithDelay(mongoCfg.Spec.ResyncInterval.Duration)
+	}
+
+	return doNotRequeue()
+}
+
+// failed reports a failed create or collMod command
+func (r *MongoDBCollectionReconciler) failed(
+	ctx context.Context,
+	log logr.Logger,
+	mongoColl *mongov1.MongoDBCollection,
+	err error,
+) (ctrl.Result, error) {
+
+	if err := r.setEventStatusFailed(ctx, mongoColl, err.Error()); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	return requeueWithDelay(30 * time.Second)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MongoDBCollectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mongov1.MongoDBCollection{}).
+		Complete(r)
+}
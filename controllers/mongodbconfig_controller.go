@@ -18,13 +18,23 @@ package controllers
 
 import (
 	"context"
+	"strconv"
 	"time"
 
+	"go.mongodb.org/mongo-driver/mongo"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
@@ -33,7 +43,7 @@ import (
 )
 
 var (
-	mongoDBConfigFinalizerName = "mongo.snappcloud.io/mongodb-data-finalizer"
+	mongoDBConfigFinalizerName = "mongo.snappcloud.io/mongodb-config-finalizer"
 )
 
 // MongoDBConfigReconciler reconciles a MongoDBConfig object
@@ -42,12 +52,19 @@ type MongoDBConfigReconciler struct {
 	Log      logr.Logger
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// MongoClients is the shared mongodb client pool
+	MongoClients *mongodb.ClientManager
+
+	// ChangeStreams runs the change streams of the MongoDBConfigs
+	ChangeStreams *mongodb.ChangeStreamManager
 }
 
 // +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbconfigs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbconfigs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbconfigs/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -72,11 +89,85 @@ func (r *MongoDBConfigReconciler) Reconcile(ctx context.Context, req reconcile.R
 		return requeue(client.IgnoreNotFound(err))
 	}
 
+	// a new value of the sync-now annotation is recorded, the MongoDBData of the MongoDBConfig
+	// compare it with the last value they have handled
+	if mongoCfg.SyncNowRequested() {
+
+		mongoCfg.Status.LastSyncNow = mongoCfg.Annotations[mongov1.SyncNowAnnotation]
+		if err := r.Client.Status().Update(ctx, mongoCfg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	if err := r.setSuspendedCondition(ctx, mongoCfg); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	if err := r.setEncryptionCondition(ctx, mongoCfg); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if mongoCfg.ObjectMeta.DeletionTimestamp.IsZero() {
+
+		// register our finalizer, so the cached client gets disconnected on deletion
+		if controllerutil.AddFinalizer(mongoCfg, mongoDBConfigFinalizerName) {
+			if err := r.Client.Update(ctx, mongoCfg); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+	} else {
+
+		if controllerutil.ContainsFinalizer(mongoCfg, mongoDBConfigFinalizerName) {
+
+			// the finalizer is held until the MongoDBConfig is resumed
+			if mongoCfg.Spec.Suspend {
+				log.Info("ignoring", "reason", "deletion is suspended")
+				return doNotRequeue()
+			}
+
+			r.ChangeStreams.Stop(mongoCfg.UID)
+
+			if err := r.MongoClients.Remove(ctx, mongoCfg.UID); err != nil {
+				log.Error(err, "unable to disconnect from mongodb")
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(mongoCfg, mongoDBConfigFinalizerName)
+			if err := r.Client.Update(ctx, mongoCfg); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return doNotRequeue()
+	}
+
 	// try to connect to the mongodb url
-	// create a new mongodb client for connection validation
-	if _, err := mongodb.NewClientWithContext(ctx, mongoCfg.Spec.MongoURL); err != nil {
+	// get the shared mongodb client for connection validation
+	mongoClient, err := getMongoClient(ctx, r.Client, r.MongoClients, mongoCfg)
+	if err == nil {
+		err = mongodb.Ping(ctx, mongoClient)
+	}
+
+	if err != nil {
 
-		if err := r.setEventStatusError(ctx, mongoCfg, err.Error()); err != nil {
+		setStatus := r.setEventStatusError
+		if _, ok := err.(*secretError); ok {
+			setStatus = r.setEventStatusSecretError
+		} else if mongodb.IsAuthError(err) {
+			setStatus = r.setEventStatusAuthError
+		} else if mongodb.IsHandshakeError(err) {
+			setStatus = r.setEventStatusHandshakeError
+		}
+
+		if err := setStatus(ctx, mongoCfg, err.Error()); err != nil {
 			log.Error(err, "unable to update target's status object")
 			return requeue(err)
 		}
@@ -84,23 +175,107 @@ func (r *MongoDBConfigReconciler) Reconcile(ctx context.Context, req reconcile.R
 		return requeueWithDelay(30 * time.Second)
 	}
 
-	if mongoCfg.Status.Ready != string(mongov1.Ready) {
+	if mongoCfg.Status.Ready != string(metav1.ConditionTrue) {
 		if err := r.setEventStatusReady(ctx, mongoCfg, "Successfully connected to MongoDB database"); err != nil {
 			log.Error(err, "unable to update target's status object")
 			return requeue(err)
 		}
 	}
 
+	r.ensureChangeStream(mongoCfg, mongoClient)
+
 	return doNotRequeue()
 }
 
+// setSuspendedCondition reports whether the writes of the MongoDBConfig are suspended,
+// the condition is only updated when the suspension changes
+func (r *MongoDBConfigReconciler) setSuspendedCondition(ctx context.Context, mongoCfg *mongov1.MongoDBConfig) error {
+
+	suspended := apimeta.IsStatusConditionTrue(mongoCfg.Status.Conditions, string(mongov1.Suspended))
+	if mongoCfg.Spec.Suspend && !suspended {
+		return r.setEventSuspended(ctx, mongoCfg, "Writes to the MongoDBData documents are suspended")
+	}
+
+	if !mongoCfg.Spec.Suspend && suspended {
+		return r.setEventResumed(ctx, mongoCfg, "Writes to the MongoDBData documents are resumed")
+	}
+
+	return nil
+}
+
+// setEncryptionCondition reports whether spec.encryption can't be used by this build of the operator,
+// the condition is only updated when it changes
+func (r *MongoDBConfigReconciler) setEncryptionCondition(ctx context.Context, mongoCfg *mongov1.MongoDBConfig) error {
+
+	unsupported := mongoCfg.EncryptionEnabled() && !mongodb.EncryptionSupported()
+	reported := apimeta.IsStatusConditionTrue(mongoCfg.Status.Conditions, string(mongov1.EncryptionUnsupported))
+
+	if unsupported && !reported {
+		return r.setEventEncryptionUnsupported(ctx, mongoCfg, mongodb.ErrEncryptionNotSupported.Error())
+	}
+
+	if !unsupported && reported {
+		return r.setEventEncryptionSupported(ctx, mongoCfg, "Encryption of the MongoDBConfig can be used")
+	}
+
+	return nil
+}
+
+// ensureChangeStream starts or stops the change stream of the given MongoDBConfig
+func (r *MongoDBConfigReconciler) ensureChangeStream(mongoCfg *mongov1.MongoDBConfig, mongoClient *mongo.Client) {
+
+	if !mongoCfg.ChangeStreamEnabled() {
+		r.ChangeStreams.Stop(mongoCfg.UID)
+		return
+	}
+
+	cfg := mongoCfg.DeepCopy()
+	r.ChangeStreams.Ensure(
+		cfg.UID,
+		strconv.FormatInt(cfg.Generation, 10),
+		mongoClient,
+		mongodb.ChangeStreamOptions{
+			Collections:           append([]string{cfg.Spec.Collection}, cfg.Spec.AllowedCollections...),
+			ExcludedDatabases:     append([]string{cfg.Spec.ChangeStream.ResumeTokenDatabase}, mongov1.ReservedDatabases...),
+			ResumeTokenDatabase:   cfg.Spec.ChangeStream.ResumeTokenDatabase,
+			ResumeTokenCollection: cfg.Spec.ChangeStream.ResumeTokenCollection,
+		},
+	)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *MongoDBConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&mongov1.MongoDBConfig{}).
+		// reconcile the MongoDBConfigs which are referencing a changed secret
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findConfigsForSecret),
+		).
 		Complete(r)
 }
 
+// findConfigsForSecret returns the MongoDBConfigs which are referencing the given secret
+func (r *MongoDBConfigReconciler) findConfigsForSecret(secret client.Object) []reconcile.Request {
+
+	mongoCfgs := &mongov1.MongoDBConfigList{}
+	if err := r.Client.List(context.Background(), mongoCfgs); err != nil {
+		r.Log.Error(err, "unable to list MongoDBConfigs")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, mongoCfg := range mongoCfgs.Items {
+		if mongoCfg.ReferencesSecret(secret.GetNamespace(), secret.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: k8sTypes.NamespacedName{Name: mongoCfg.Name},
+			})
+		}
+	}
+
+	return requests
+}
+
 func doNotRequeue() (ctrl.Result, error) {
 	return ctrl.Result{}, nil
 }
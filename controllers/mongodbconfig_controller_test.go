@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+func TestSetEncryptionCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		encryption *mongov1.EncryptionSpec
+		reported   bool
+		want       bool
+	}{
+		{
+			name: "encryption not used",
+			want: false,
+		},
+		{
+			name:       "encryption used",
+			encryption: &mongov1.EncryptionSpec{},
+			want:       !mongodb.EncryptionSupported(),
+		},
+		{
+			name:     "encryption removed after it was reported",
+			reported: true,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoCfg := &mongov1.MongoDBConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "db"},
+				Spec:       mongov1.MongoDBConfigSpec{Encryption: tt.encryption},
+			}
+			if tt.reported {
+				mongoCfg.Status.Conditions = []metav1.Condition{{
+					Type:   string(mongov1.EncryptionUnsupported),
+					Status: metav1.ConditionTrue,
+					Reason: string(mongov1.EncryptionUnsupported),
+				}}
+			}
+
+			scheme := newTestScheme(t)
+			r := &MongoDBConfigReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(mongoCfg).Build(),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			if err := r.setEncryptionCondition(context.Background(), mongoCfg); err != nil {
+				t.Fatalf("setEncryptionCondition() error = %v", err)
+			}
+
+			got := apimeta.IsStatusConditionTrue(mongoCfg.Status.Conditions, string(mongov1.EncryptionUnsupported))
+			if got != tt.want {
+				t.Errorf("EncryptionUnsupported = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
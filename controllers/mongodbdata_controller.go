@@ -18,21 +18,33 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	// nolint
 	k8sTypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
@@ -44,17 +56,41 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+const (
+	// objectIDIndexKey indexes the MongoDBData by their document ids
+	objectIDIndexKey = ".status.object_id"
+
+	// collectionTargetIndexKey indexes the MongoDBCollections by their db, database and collection
+	collectionTargetIndexKey = ".spec.target"
+
+	// sourceIndexKey indexes the MongoDBData by the ConfigMaps and Secrets of their dataFrom and secretFields
+	sourceIndexKey = ".spec.sources"
+
+	// referenceIndexKey indexes the MongoDBData by the MongoDBData of their references
+	referenceIndexKey = ".spec.references"
+
+	// configIndexKey indexes the MongoDBData by their MongoDBConfig
+	configIndexKey = ".spec.db"
+
+	// changeEventBufferSize is the number of change events which can wait for the controller
+	changeEventBufferSize = 1024
+)
+
 var (
 	mongoDBDataFinalizerName = "mongo.snappcloud.io/mongodb-data-finalizer"
 	histogramVec             = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Name: "mongodb_data_latency_histogram",
 		Help: "Histogram of response time for Reconcile in seconds",
 	}, []string{"name", "state"})
+	driftCorrectionsVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongodb_data_drift_corrections_total",
+		Help: "Number of documents which were changed or deleted directly in mongodb and corrected",
+	}, []string{"name", "correction"})
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(histogramVec)
+	metrics.Registry.MustRegister(histogramVec, driftCorrectionsVec)
 }
 
 // MongoDBDataReconciler reconciles a MongoDBData object
@@ -63,12 +99,25 @@ type MongoDBDataReconciler struct {
 	Log      logr.Logger
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// MongoClients is the shared mongodb client pool
+	MongoClients *mongodb.ClientManager
+
+	// ChangeStreams delivers the changes of the managed documents
+	ChangeStreams *mongodb.ChangeStreamManager
+
+	// DryRun computes the writes of all MongoDBData and reports them instead of executing them,
+	// it's set by the --data-dry-run flag and doesn't apply to the other controllers
+	DryRun bool
 }
 
 // +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbdata,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbdata/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbdata/finalizers,verbs=update
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbcollections,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -134,8 +183,8 @@ func (r *MongoDBDataReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// create a new mongodb client
-	mongoClient, err := mongodb.NewClient(mongoCfg.Spec.MongoURL)
+	// get the shared mongodb client of the MongoDBConfig
+	mongoClient, err := getMongoClient(ctx, r.Client, r.MongoClients, mongoCfg)
 	if err != nil {
 
 		if err := r.setEventStatusPending(ctx, mongoData, err.Error()); err != nil {
@@ -146,11 +195,27 @@ func (r *MongoDBDataReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return requeueWithDelay(30 * time.Second)
 	}
 
-	var (
-		// using resource namespace as database name
-		db         = mongoClient.Database(req.Namespace)
-		collection = db.Collection(mongoCfg.Spec.Collection)
-	)
+	// a new value of the sync-now annotation of the MongoDBData or of its MongoDBConfig syncs the
+	// document once, even when it's suspended, the value is recorded as handled once the document has been written
+	syncNow := mongoData.SyncNowRequested() || mongoData.ConfigSyncNowRequested(mongoCfg)
+
+	// writes are skipped while the MongoDBData or its MongoDBConfig is suspended,
+	// drifts of the document are still reported
+	suspended := mongoData.IsSuspended(mongoCfg) && !syncNow
+	if !syncNow {
+		if err := r.setSuspendedCondition(ctx, mongoData, mongoCfg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	// the write planned in dry-run mode is not reported anymore once dry-run is turned off
+	if !r.isDryRun(mongoData) {
+		if err := r.clearPlannedWrite(ctx, mongoData); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
 
 	// examine DeletionTimestamp to determine if object is under deletion
 	if mongoData.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -174,12 +239,38 @@ func (r *MongoDBDataReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 		if controllerutil.ContainsFinalizer(mongoData, mongoDBDataFinalizerName) {
 
+			// the finalizer is held and the document is kept until the writes are resumed
+			if suspended {
+				log.Info("ignoring", "reason", "deletion is suspended")
+				return doNotRequeue()
+			}
+
 			// our finalizer is present, so lets handle any external dependency
-			if err := r.deleteDocument(ctx, collection, mongoData); err != nil {
-				// if fail to delete the external dependency here, return with error
-				// so that it can be retried
-				log.Error(err, "unable to remove object from mongodb")
-				return requeue(err)
+			// there is nothing to delete if the document is never inserted
+			if mongoData.Status.ObjectID != "" {
+
+				collection, err := documentCollection(mongoClient, mongoCfg, mongoData)
+				if err != nil {
+					log.Error(err, "unable to resolve the document collection")
+					return requeue(err)
+				}
+
+				if err := r.deleteDocument(ctx, collection, mongoData, mongoCfg); err != nil {
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					log.Error(err, "unable to remove object from mongodb")
+					return requeue(err)
+				}
+
+				// the finalizer is held while the deletion is only planned, by the dry-run
+				// annotation or by the --data-dry-run flag, so the document can still be deleted
+				if r.isDryRun(mongoData) {
+					if err := r.setEventDeletionHeld(ctx, mongoData, r.dryRunHeldMessage(mongoData)); err != nil {
+						log.Error(err, "unable to update target's status object")
+						return requeue(err)
+					}
+					return doNotRequeue()
+				}
 			}
 
 			// remove our finalizer from the list and update it.
@@ -195,8 +286,66 @@ func (r *MongoDBDataReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return doNotRequeue()
 	}
 
-	// marshal the current MongoDBData object into bson.M for further mongodb operations
-	data, err := bson.Marshal(mongoData.Spec.Data)
+	collection, err := documentCollection(mongoClient, mongoCfg, mongoData)
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	// merge the current MongoDBData spec.data with spec.dataFrom into bson for further mongodb operations
+	data, err := mongoData.Document(ctx, r.Client)
+	if err != nil {
+
+		if err := r.setEventStatusPending(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	// resolve spec.references to the ObjectIDs of the referenced MongoDBData
+	references, err := mongoData.ReferenceValues(ctx, r.Client)
+	if err != nil {
+
+		cond := apimeta.FindStatusCondition(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionPending))
+		if cond == nil || cond.Message != err.Error() {
+			if err := r.setEventStatusPending(ctx, mongoData, err.Error()); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	if data, err = mergeFields(data, references); err != nil {
+		log.Error(err, "unable to merge the references into the document")
+		return requeue(err)
+	}
+
+	// the document is held at a revision of its history while it's rolled back
+	rollback, err := mongoData.RollbackRevision()
+	if err == nil && rollback > 0 {
+		data, err = r.revisionDocument(ctx, mongoData, rollback)
+	}
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	// resolve spec.secretFields, their values are never written to the status or events
+	secrets, err := resolveSecretFields(ctx, r.Client, mongoData)
 	if err != nil {
 
 		if err := r.setEventStatusPending(ctx, mongoData, err.Error()); err != nil {
@@ -207,6 +356,24 @@ func (r *MongoDBDataReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return requeueWithDelay(30 * time.Second)
 	}
 
+	if data, err = secrets.merge(data); err != nil {
+		log.Error(err, "unable to merge the secret fields into the document")
+		return requeue(err)
+	}
+
+	// the document stays in plaintext to be compared with mongodb,
+	// spec.encryptedFields are only encrypted when it's written
+	encrypted, err := r.resolveEncryptedFields(ctx, mongoCfg, mongoData)
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
 	// check if mongodbData state is Inserted and there is a record on database
 	// then update the record
 	if mongoData.Status.State == string(mongov1.MongoDBDataConditionInserted) {
@@ -214,17 +381,32 @@ func (r *MongoDBDataReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// if document exists on database, MongoDBData.Status.ObjectID should not be empty
 		// then we should update the document
 		if mongoData.Status.ObjectID != "" {
-			return r.findAndUpdateDocumentIfNeeded(ctx, log, collection, mongoData, mongoCfg, data)
+			return r.findAndUpdateDocumentIfNeeded(ctx, log, collection, mongoData, mongoCfg, data, secrets, encrypted, suspended)
 		}
 	}
 
-	if mongoData.Status.State == string(mongov1.MongoDBDataConditionPending) {
+	// wait for the collection to be created with its validator and options
+	msg, err := r.collectionNotReady(ctx, mongoData, collection)
+	if err != nil {
+		log.Error(err, "unable to list MongoDBCollections")
+		return requeue(err)
+	}
+
+	if msg != "" {
+
+		cond := apimeta.FindStatusCondition(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionPending))
+		if cond == nil || cond.Message != msg {
+			if err := r.setEventStatusPending(ctx, mongoData, msg); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+		}
 
-		// check if mongodbData state is not Inserted, insert the document to mongodb collection
-		return r.insertDocument(ctx, log, collection, mongoData, mongoCfg, data)
+		return requeueWithDelay(20 * time.Second)
 	}
 
-	return doNotRequeue()
+	// check if mongodbData state is not Inserted, insert the document to mongodb collection
+	return r.insertDocument(ctx, log, collection, mongoData, mongoCfg, data, secrets, encrypted, suspended)
 }
 
 // updateDocument will update the current MongoDBData document from database
@@ -235,6 +417,9 @@ func (r *MongoDBDataReconciler) findAndUpdateDocumentIfNeeded(
 	mongoData *mongov1.MongoDBData,
 	mongoCfg *mongov1.MongoDBConfig,
 	document []byte,
+	secrets secretFields,
+	encrypted encryptedFields,
+	suspended bool,
 ) (ctrl.Result, error) {
 
 	updateID, err := primitive.ObjectIDFromHex(mongoData.Status.ObjectID)
@@ -243,27 +428,20 @@ func (r *MongoDBDataReconciler) findAndUpdateDocumentIfNeeded(
 		return requeue(err)
 	}
 
-	curser := collection.FindOne(ctx, bson.M{"_id": updateID})
+	// the spec is only recorded as applied once it's written
+	applied := newAppliedSpec(mongoData, mongoCfg, document, secrets)
 
-	// decode find spec.Data into bson.M for comparison
-	var find bson.M
-	if err := curser.Decode(&find); err != nil {
-		log.Error(err, "could not unmarshal mongodb find document into bson.M")
-		return requeue(err)
-	}
-
-	// decode resource spec.Data into bson.M for comparison
-	var specData bson.M
-	if err := bson.Unmarshal(document, &specData); err != nil {
-		log.Error(err, "could not unmarshal spec.data bson bytes into bson.M")
-		return requeue(err)
-	}
+	// the spec has not changed since the last apply, so any difference
+	// between the spec and the document is a drift made directly in mongodb
+	dataApplied := r.isDataApplied(mongoData, document)
+	specApplied := dataApplied && mongoData.Status.SecretFieldsVersion == secrets.version
 
-	// if current object is not equel with database document
-	// we should update the document on db
-	if !reflect.DeepEqual(find, specData) {
+	// only the Secrets of spec.secretFields have changed, so only their fields are updated,
+	// a versioned document is compared and updated with its version below
+	if dataApplied && !specApplied && len(secrets.values) > 0 && mongoData.VersionField() == "" &&
+		!suspended && !r.isDryRun(mongoData) {
 
-		result, err := collection.UpdateByID(ctx, updateID, bson.M{"$set": specData})
+		stored, err := encrypted.encrypt(ctx, document)
 		if err != nil {
 
 			if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
@@ -274,56 +452,68 @@ func (r *MongoDBDataReconciler) findAndUpdateDocumentIfNeeded(
 			return requeueWithDelay(30 * time.Second)
 		}
 
-		if result.ModifiedCount == 1 {
+		values, err := secrets.stored(stored)
+		if err != nil {
+			log.Error(err, "unable to read the secret fields of the document")
+			return requeue(err)
+		}
 
-			msg := "Document updated successfully"
-			if err := r.setEventStatusInserted(ctx, mongoData, msg); err != nil {
+		result, err := collection.UpdateByID(ctx, updateID, bson.D{{Key: "$set", Value: values}})
+		if err != nil {
 
+			if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
 				log.Error(err, "unable to update target's status object")
 				return requeue(err)
 			}
-		}
 
-	}
+			return requeueWithDelay(30 * time.Second)
+		}
 
-	return doNotRequeue()
-}
+		// a deleted document is inserted again below
+		if result.MatchedCount == 1 {
 
-// insertDocument will insert current MongoDBData document into database
-func (r *MongoDBDataReconciler) insertDocument(
-	ctx context.Context,
-	log logr.Logger,
-	collection *mongo.Collection,
-	mongoData *mongov1.MongoDBData,
-	mongoCfg *mongov1.MongoDBConfig,
-	document []byte,
-) (ctrl.Result, error) {
+			applied.mark(mongoData)
+			if err := r.setEventStatusInserted(ctx, mongoData, "Secret fields updated successfully"); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
 
-	fmt.Println(mongoData.Status.ObjectID)
+			return r.resync(mongoCfg, mongoData)
+		}
+	}
 
-	// check if we have the document with ObjectID, then ignore the insert
-	if mongoData.Status.ObjectID != "" {
+	stored, err := encrypted.encrypt(ctx, document)
+	if err != nil {
 
-		findOID, err := primitive.ObjectIDFromHex(mongoData.Status.ObjectID)
-		if err != nil {
-			log.Error(err, "unable to decode object_id")
-			return requeue(client.IgnoreNotFound(err))
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
 		}
 
-		count, err := collection.CountDocuments(ctx, bson.M{"_id": findOID})
-		if err != nil {
-			log.Error(err, "unable to count document with ObjectID ", findOID)
-			return requeue(client.IgnoreNotFound(err))
-		}
+		return requeueWithDelay(30 * time.Second)
+	}
 
-		fmt.Println(count)
+	raw, err := collection.FindOne(ctx, bson.M{"_id": updateID}).DecodeBytes()
+	if err != nil {
 
-		if count > 0 {
-			return doNotRequeue()
+		if err == mongo.ErrNoDocuments {
+
+			if suspended {
+				msg := "Document was deleted from mongodb, it's not inserted again while the writes are suspended"
+				return r.suspendedDrift(ctx, log, mongoData, mongoCfg, msg)
+			}
+
+			// the document has been deleted directly from mongodb
+			return r.reinsertDocument(ctx, log, collection, mongoData, mongoCfg, updateID, stored, applied)
 		}
+
+		log.Error(err, "unable to find the document")
+		return requeue(err)
 	}
 
-	result, err := collection.InsertOne(ctx, document)
+	// encrypted fields are compared in plaintext, their ciphertext
+	// changes on every write with the Random algorithm
+	plain, found, err := encrypted.decrypt(ctx, raw)
 	if err != nil {
 
 		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
@@ -334,35 +524,1469 @@ func (r *MongoDBDataReconciler) insertDocument(
 		return requeueWithDelay(30 * time.Second)
 	}
 
-	if result.InsertedID != nil {
+	// only the fields of the document which are managed by the operator are compared,
+	// fields which are written by other applications, including _id, are left alone
+	paths, err := mongodb.FieldPaths(stored)
+	if err != nil {
+		log.Error(err, "could not read the field paths of the document")
+		return requeue(err)
+	}
 
-		mongoData.Status.ObjectID = result.InsertedID.(primitive.ObjectID).Hex()
-		msg := fmt.Sprintf("MongoDBData successfully Inserted into %s collection", mongoCfg.Spec.Collection)
-		if err := r.setEventStatusInserted(ctx, mongoData, msg); err != nil {
+	// the comparison is type-aware, a value written as another bson type is a drift too
+	diff := mongodb.FieldDiff(plain, document, paths)
+	for _, path := range encrypted.diff(found, paths) {
+		diff = mongodb.AppendPath(diff, path)
+	}
+
+	removed := mongodb.RemovedPaths(mongoData.Status.ManagedFields, paths)
+	update, err := fieldsUpdate(stored, diff, removed)
+	if err != nil {
+		log.Error(err, "could not build the update of the document")
+		return requeue(err)
+	}
 
+	// the version field of spec.concurrency is incremented by every client which writes the document,
+	// a version other than the one of the last write means that another client has written it
+	versionField := mongoData.VersionField()
+	liveVersion, versionFilter := documentVersion(raw, versionField)
+	conflict := versionField != "" && liveVersion != mongoData.Status.DocumentVersion
+
+	// the fields of the document have changed, so the managed fields are recorded even without a write,
+	// the version of another client is taken over when it hasn't changed any of the managed fields
+	// and a document which already matches the spec is recorded as applied
+	inSync := len(update) == 0 && (!specApplied || applied.syncRequested)
+	if len(update) == 0 && (conflict || inSync || !reflect.DeepEqual(mongoData.Status.ManagedFields, paths)) {
+		mongoData.Status.ManagedFields = paths
+		if versionField != "" {
+			mongoData.Status.DocumentVersion = liveVersion
+		}
+		applied.mark(mongoData)
+		if err := r.Client.Status().Update(ctx, mongoData); err != nil {
 			log.Error(err, "unable to update target's status object")
 			return requeue(err)
 		}
 	}
 
-	return doNotRequeue()
-}
+	// if the managed fields of the database document are not equal with spec.data
+	// or they are not encrypted as specified, we should update the document on db
+	if len(update) > 0 {
+
+		if suspended {
+			msg := fmt.Sprintf("Document differs from spec.data in %s, it's not updated while the writes are suspended",
+				strings.Join(append(diff, removed...), ", "))
+			return r.suspendedDrift(ctx, log, mongoData, mongoCfg, msg)
+		}
+
+		filter := bson.D{{Key: "_id", Value: updateID}}
+		if versionField != "" {
+
+			policy := mongoData.ConflictPolicy()
+			if conflict && (policy == mongov1.ConflictPolicyFail || (policy == mongov1.ConflictPolicySkip && specApplied)) {
+				msg := fmt.Sprintf("Document has been written by another client at version %d", liveVersion)
+				return r.versionConflict(ctx, log, mongoData, mongoCfg, msg)
+			}
+
+			filter = append(filter, versionFilter)
+			update = setVersion(update, versionField, liveVersion+1)
+		}
+
+		if r.isDryRun(mongoData) {
+
+			redacted, err := r.redactSecrets(ctx, mongoData, stored)
+			if err != nil {
+				log.Error(err, "could not redact the secret values of the document")
+				return requeue(err)
+			}
+
+			planned, err := fieldsUpdate(redacted, diff, removed)
+			if err != nil {
+				log.Error(err, "could not build the update of the document")
+				return requeue(err)
+			}
+
+			if versionField != "" {
+				planned = setVersion(planned, versionField, liveVersion+1)
+			}
+
+			if err := r.planWrite(ctx, mongoData, "Update", filter, planned); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			return r.resync(mongoCfg, mongoData)
+		}
+
+		result, err := collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+
+			if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			return requeueWithDelay(30 * time.Second)
+		}
+
+		if versionField != "" {
+
+			// the version has changed since the document was read
+			if result.MatchedCount == 0 {
+
+				if mongoData.ConflictPolicy() == mongov1.ConflictPolicyOverwrite {
+					return ctrl.Result{Requeue: true}, nil
+				}
+
+				msg := fmt.Sprintf("Document has been written by another client since version %d was read", liveVersion)
+				return r.versionConflict(ctx, log, mongoData, mongoCfg, msg)
+			}
+
+			mongoData.Status.DocumentVersion = liveVersion + 1
+		}
+
+		mongoData.Status.ManagedFields = paths
+		applied.mark(mongoData)
+
+		if result.ModifiedCount == 1 {
+
+			if specApplied {
+
+				driftCorrectionsVec.WithLabelValues(mongoData.ObjectMeta.Name, "updated").Inc()
+
+				msg := "Document was changed in mongodb and has been updated to match spec.data"
+				if err := r.setEventDrifted(ctx, mongoData, msg); err != nil {
+					log.Error(err, "unable to update target's status object")
+					return requeue(err)
+				}
+
+			} else {
+
+				msg := "Document updated successfully"
+				if rollback := mongoData.Status.RolledBackTo; rollback > 0 {
+					msg = fmt.Sprintf("Document rolled back to revision %d", rollback)
+				}
+
+				if err := r.setEventStatusInserted(ctx, mongoData, msg); err != nil {
+
+					log.Error(err, "unable to update target's status object")
+					return requeue(err)
+				}
+			}
+
+			return r.resync(mongoCfg, mongoData)
+		}
 
-// deleteDocument will delete the current MongoDBData document from database
-func (r *MongoDBDataReconciler) deleteDocument(ctx context.Context, coll *mongo.Collection, adapter *mongov1.MongoDBData) (err error) {
-	objectID, err := primitive.ObjectIDFromHex(adapter.Status.ObjectID)
-	if err != nil {
-		return err
 	}
-	_, err = coll.DeleteOne(ctx, bson.M{"_id": objectID})
-	return client.IgnoreNotFound(err)
+
+	// the document matches spec.data again
+	if apimeta.IsStatusConditionTrue(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionDrifted)) {
+		if err := r.setEventInSync(ctx, mongoData, "Document matches spec.data"); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	// the document is written with its version again
+	if apimeta.IsStatusConditionTrue(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionConflict)) {
+		msg := fmt.Sprintf("Document version %d matches the last write", mongoData.Status.DocumentVersion)
+		if err := r.setEventVersionResolved(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	// there is nothing left to write in dry-run mode
+	if err := r.clearPlannedWrite(ctx, mongoData); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	// the document is in sync with the spec, so it's recorded in the revision history
+	if mongoData.Status.RolledBackTo == 0 && !suspended && !r.isDryRun(mongoData) {
+		if err := r.recordRevision(ctx, mongoData, document); err != nil {
+			log.Error(err, "unable to record the document revision")
+			return requeue(err)
+		}
+	}
+
+	return r.resync(mongoCfg, mongoData)
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *MongoDBDataReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&mongov1.MongoDBData{}).
+// suspendedDrift reports a drift of the document which is not corrected while the writes are suspended
+func (r *MongoDBDataReconciler) suspendedDrift(
+	ctx context.Context,
+	log logr.Logger,
+	mongoData *mongov1.MongoDBData,
+	mongoCfg *mongov1.MongoDBConfig,
+	msg string,
+) (ctrl.Result, error) {
+
+	cond := apimeta.FindStatusCondition(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionDrifted))
+	if cond == nil || cond.Message != msg ||
+		!apimeta.IsStatusConditionTrue(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionDrifted)) {
+		if err := r.setEventDrifted(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	return r.resync(mongoCfg, mongoData)
+}
+
+// setSuspendedCondition reports whether the writes to the document are suspended,
+// the condition is only updated when the suspension changes
+func (r *MongoDBDataReconciler) setSuspendedCondition(
+	ctx context.Context,
+	mongoData *mongov1.MongoDBData,
+	mongoCfg *mongov1.MongoDBConfig,
+) error {
+
+	suspended := apimeta.IsStatusConditionTrue(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionSuspended))
+	if !mongoData.IsSuspended(mongoCfg) {
+		if suspended {
+			return r.setEventResumed(ctx, mongoData, "Writes to the document are resumed")
+		}
+		return nil
+	}
+
+	msg := fmt.Sprintf("Writes to the document are suspended by %s", mongoData.SuspendedBy(mongoCfg))
+	cond := apimeta.FindStatusCondition(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionSuspended))
+	if !suspended || cond.Message != msg {
+		return r.setEventSuspended(ctx, mongoData, msg)
+	}
+
+	return nil
+}
+
+// versionConflict reports that the document has been written by another client, the document
+// is left as it is and the reconcile fails with the Fail conflict policy
+func (r *MongoDBDataReconciler) versionConflict(
+	ctx context.Context,
+	log logr.Logger,
+	mongoData *mongov1.MongoDBData,
+	mongoCfg *mongov1.MongoDBConfig,
+	msg string,
+) (ctrl.Result, error) {
+
+	cond := apimeta.FindStatusCondition(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionConflict))
+	if cond == nil || cond.Message != msg ||
+		!apimeta.IsStatusConditionTrue(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionConflict)) {
+		if err := r.setEventVersionConflict(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	if mongoData.ConflictPolicy() == mongov1.ConflictPolicyFail {
+		return requeue(fmt.Errorf("%s", msg))
+	}
+
+	return r.resync(mongoCfg, mongoData)
+}
+
+// documentVersion returns the version field of the document and the filter which matches it,
+// the version is 0 when the document has no such field
+func documentVersion(document bson.Raw, field string) (int64, bson.E) {
+	if field == "" {
+		return 0, bson.E{}
+	}
+
+	value, err := document.LookupErr(strings.Split(field, ".")...)
+	if err != nil {
+		return 0, bson.E{Key: field, Value: bson.D{{Key: "$exists", Value: false}}}
+	}
+
+	version, _ := value.AsInt64OK()
+	return version, bson.E{Key: field, Value: value}
+}
+
+// setVersion adds the version field to the $set of the update
+func setVersion(update bson.D, field string, version int64) bson.D {
+	for i, e := range update {
+		if e.Key == "$set" {
+			update[i].Value = append(e.Value.(bson.D), bson.E{Key: field, Value: version})
+			return update
+		}
+	}
+	return append(update, bson.E{Key: "$set", Value: bson.D{{Key: field, Value: version}}})
+}
+
+// withVersion returns the bson document with the version field of spec.concurrency,
+// the document is left as it is when spec.concurrency is not set
+func withVersion(mongoData *mongov1.MongoDBData, document []byte, version int64) ([]byte, error) {
+	field := mongoData.VersionField()
+	if field == "" {
+		return document, nil
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+
+	doc, err := mongodb.MergeDocument(doc, field, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.Marshal(doc)
+}
+
+// reinsertDocument will insert the current MongoDBData document again with its
+// previous ObjectID, after it has been deleted directly from mongodb
+func (r *MongoDBDataReconciler) reinsertDocument(
+	ctx context.Context,
+	log logr.Logger,
+	collection *mongo.Collection,
+	mongoData *mongov1.MongoDBData,
+	mongoCfg *mongov1.MongoDBConfig,
+	objectID primitive.ObjectID,
+	document []byte,
+	applied appliedSpec,
+) (ctrl.Result, error) {
+
+	paths, err := mongodb.FieldPaths(document)
+	if err != nil {
+		log.Error(err, "could not read the field paths of the document")
+		return requeue(err)
+	}
+
+	versioned, err := withVersion(mongoData, document, mongoData.Status.DocumentVersion+1)
+	if err != nil {
+		log.Error(err, "could not set the version field of the document")
+		return requeue(err)
+	}
+
+	if r.isDryRun(mongoData) {
+
+		redacted, err := r.redactSecrets(ctx, mongoData, versioned)
+		if err != nil {
+			log.Error(err, "could not redact the secret values of the document")
+			return requeue(err)
+		}
+
+		var planned bson.D
+		if err := bson.Unmarshal(redacted, &planned); err != nil {
+			log.Error(err, "could not unmarshal spec.data bson bytes into bson.D")
+			return requeue(err)
+		}
+
+		if err := r.planWrite(ctx, mongoData, "Insert", nil, append(bson.D{{Key: "_id", Value: objectID}}, planned...)); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return r.resync(mongoCfg, mongoData)
+	}
+
+	var specData bson.D
+	if err := bson.Unmarshal(versioned, &specData); err != nil {
+		log.Error(err, "could not unmarshal spec.data bson bytes into bson.D")
+		return requeue(err)
+	}
+
+	if _, err := collection.InsertOne(ctx, append(bson.D{{Key: "_id", Value: objectID}}, specData...)); err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	mongoData.Status.ManagedFields = paths
+	if mongoData.VersionField() != "" {
+		mongoData.Status.DocumentVersion++
+	}
+	applied.mark(mongoData)
+
+	driftCorrectionsVec.WithLabelValues(mongoData.ObjectMeta.Name, "reinserted").Inc()
+
+	msg := "Document was deleted from mongodb and has been inserted again"
+	if err := r.setEventDrifted(ctx, mongoData, msg); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	return r.resync(mongoCfg, mongoData)
+}
+
+// isDataApplied reports whether the current generation of the MongoDBData, the current
+// data of its spec.dataFrom and its rollback have already been written to mongodb
+func (r *MongoDBDataReconciler) isDataApplied(mongoData *mongov1.MongoDBData, document []byte) bool {
+	rollback, _ := mongoData.RollbackRevision()
+	return mongoData.Status.AppliedGeneration == mongoData.Generation &&
+		mongoData.Status.DataFromHash == dataFromHash(mongoData, document) &&
+		mongoData.Status.RolledBackTo == rollback
+}
+
+// appliedSpec is what is recorded in the status once the spec has been written to mongodb
+type appliedSpec struct {
+	generation          int64
+	dataFromHash        string
+	secretFieldsVersion string
+	rolledBackTo        int64
+	syncNow             string
+
+	// configSyncNow is the sync-now value of the MongoDBConfig, any write handles it
+	configSyncNow string
+
+	// syncRequested is set when one of the sync-now values hasn't been handled yet
+	syncRequested bool
+}
+
+// newAppliedSpec returns the applied spec of the given document of the MongoDBData
+func newAppliedSpec(mongoData *mongov1.MongoDBData, mongoCfg *mongov1.MongoDBConfig, document []byte, secrets secretFields) appliedSpec {
+	rollback, _ := mongoData.RollbackRevision()
+	applied := appliedSpec{
+		generation:          mongoData.Generation,
+		dataFromHash:        dataFromHash(mongoData, document),
+		secretFieldsVersion: secrets.version,
+		rolledBackTo:        rollback,
+		configSyncNow:       mongoCfg.SyncNowValue(),
+		syncRequested:       mongoData.ConfigSyncNowRequested(mongoCfg),
+	}
+	if mongoData.SyncNowRequested() {
+		applied.syncNow = mongoData.Annotations[mongov1.SyncNowAnnotation]
+		applied.syncRequested = true
+	}
+	return applied
+}
+
+// mark records the spec as written to mongodb, it's only called after a successful write
+// or when the document already matches the spec, an adopted document is not applied
+// until it's updated with spec.data
+func (a appliedSpec) mark(mongoData *mongov1.MongoDBData) {
+	mongoData.Status.AppliedGeneration = a.generation
+	mongoData.Status.DataFromHash = a.dataFromHash
+	mongoData.Status.SecretFieldsVersion = a.secretFieldsVersion
+	mongoData.Status.RolledBackTo = a.rolledBackTo
+	if a.syncNow != "" {
+		mongoData.Status.LastSyncNow = a.syncNow
+	}
+	if a.configSyncNow != "" {
+		mongoData.Status.LastConfigSyncNow = a.configSyncNow
+	}
+}
+
+// dataFromHash returns the hash of the document when it's merged with spec.dataFrom and
+// spec.references, changes of the ConfigMaps, Secrets and referenced MongoDBData don't change
+// the generation of the MongoDBData. The secret fields are left out, so their values can't
+// be derived from the status
+func dataFromHash(mongoData *mongov1.MongoDBData, document []byte) string {
+	if len(mongoData.Spec.DataFrom) == 0 && len(mongoData.Spec.References) == 0 {
+		return ""
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(document, &doc); err != nil {
+		return ""
+	}
+
+	data, err := bson.Marshal(mongodb.RemoveFields(doc, mongoData.SecretFieldPaths()))
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// secretFields are the resolved values of spec.secretFields
+type secretFields struct {
+	// values are the field values by their dot separated paths
+	values bson.D
+
+	// version changes whenever one of the Secrets changes
+	version string
+}
+
+// resolveSecretFields reads the values of spec.secretFields from their Secrets
+func resolveSecretFields(ctx context.Context, c client.Reader, mongoData *mongov1.MongoDBData) (secretFields, error) {
+	values, version, err := mongoData.SecretFieldValues(ctx, c)
+	return secretFields{values: values, version: version}, err
+}
+
+// merge sets the secret fields in the bson document
+func (s secretFields) merge(document []byte) ([]byte, error) {
+	return mergeFields(document, s.values)
+}
+
+// mergeFields sets the values on their dot separated paths in the bson document
+func mergeFields(document []byte, values bson.D) ([]byte, error) {
+	if len(values) == 0 {
+		return document, nil
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+
+	for _, e := range values {
+		var err error
+		if doc, err = mongodb.MergeDocument(doc, e.Key, e.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return bson.Marshal(doc)
+}
+
+// fieldsUpdate returns the update which sets the given paths to their values in the bson
+// document and unsets the removed paths, it's empty when there is nothing to change
+func fieldsUpdate(document []byte, set, unset []string) (bson.D, error) {
+	update := bson.D{}
+
+	if len(set) > 0 {
+		fields := bson.D{}
+		for _, path := range set {
+
+			value, err := bson.Raw(document).LookupErr(strings.Split(path, ".")...)
+			if err != nil {
+				return nil, fmt.Errorf("field %s is not found in document", path)
+			}
+
+			fields = append(fields, bson.E{Key: path, Value: value})
+		}
+		update = append(update, bson.E{Key: "$set", Value: fields})
+	}
+
+	if len(unset) > 0 {
+		fields := bson.D{}
+		for _, path := range unset {
+			fields = append(fields, bson.E{Key: path, Value: ""})
+		}
+		update = append(update, bson.E{Key: "$unset", Value: fields})
+	}
+
+	return update, nil
+}
+
+// stored returns the secret fields by their paths as they are in the bson document,
+// so the values of encrypted fields are written encrypted
+func (s secretFields) stored(document []byte) (bson.D, error) {
+	values := bson.D{}
+	for _, e := range s.values {
+
+		value, err := bson.Raw(document).LookupErr(strings.Split(e.Key, ".")...)
+		if err != nil {
+			return nil, fmt.Errorf("secret field %s is not found in document", e.Key)
+		}
+
+		values = append(values, bson.E{Key: e.Key, Value: value})
+	}
+	return values, nil
+}
+
+// encryptedFields encrypts and decrypts the spec.encryptedFields of a MongoDBData,
+// the document is left as it is when there are no encrypted fields
+type encryptedFields struct {
+	encrypter *mongodb.Encrypter
+	fields    []mongodb.EncryptedField
+}
+
+// resolveEncryptedFields returns the encrypter of the MongoDBConfig for spec.encryptedFields
+func (r *MongoDBDataReconciler) resolveEncryptedFields(
+	ctx context.Context,
+	mongoCfg *mongov1.MongoDBConfig,
+	mongoData *mongov1.MongoDBData,
+) (encryptedFields, error) {
+
+	if len(mongoData.Spec.EncryptedFields) == 0 {
+		return encryptedFields{}, nil
+	}
+
+	encrypter, err := getEncrypter(ctx, r.Client, r.MongoClients, mongoCfg)
+	if err != nil {
+		return encryptedFields{}, err
+	}
+
+	return encryptedFields{encrypter: encrypter, fields: mongoData.EncryptedFields()}, nil
+}
+
+// encrypt returns the bson document with its encrypted fields encrypted
+func (e encryptedFields) encrypt(ctx context.Context, document []byte) ([]byte, error) {
+	if e.encrypter == nil {
+		return document, nil
+	}
+	return e.encrypter.Encrypt(ctx, document, e.fields)
+}
+
+// diff returns the managed paths which are not encrypted with the algorithm of spec.encryptedFields,
+// the fields which are no longer encrypted in the spec are written again in plaintext
+func (e encryptedFields) diff(found []mongodb.EncryptedField, paths []string) []string {
+	algorithms := map[string]string{}
+	for _, field := range found {
+		algorithms[field.Path] = field.Algorithm
+	}
+
+	desired := map[string]string{}
+	for _, field := range e.fields {
+		desired[field.Path] = field.Algorithm
+	}
+
+	diff := []string{}
+	for _, path := range paths {
+		if algorithms[path] != desired[path] {
+			diff = append(diff, path)
+		}
+	}
+	return diff
+}
+
+// decrypt returns the bson document in plaintext and the fields which were encrypted
+func (e encryptedFields) decrypt(ctx context.Context, document []byte) ([]byte, []mongodb.EncryptedField, error) {
+	if e.encrypter == nil {
+		return document, nil, nil
+	}
+	return e.encrypter.Decrypt(ctx, document)
+}
+
+// resync requeues the MongoDBData after its resync interval, if resync is enabled
+func (r *MongoDBDataReconciler) resync(mongoCfg *mongov1.MongoDBConfig, mongoData *mongov1.MongoDBData) (ctrl.Result, error) {
+	if interval := mongoCfg.ResyncIntervalFor(mongoData); interval > 0 {
+		return requeueWithDelay(interval)
+	}
+	return doNotRequeue()
+}
+
+// insertDocument will insert current MongoDBData document into database
+func (r *MongoDBDataReconciler) insertDocument(
+	ctx context.Context,
+	log logr.Logger,
+	collection *mongo.Collection,
+	mongoData *mongov1.MongoDBData,
+	mongoCfg *mongov1.MongoDBConfig,
+	document []byte,
+	secrets secretFields,
+	encrypted encryptedFields,
+	suspended bool,
+) (ctrl.Result, error) {
+
+	// check if we have the document with ObjectID, then ignore the insert
+	if mongoData.Status.ObjectID != "" {
+
+		findOID, err := primitive.ObjectIDFromHex(mongoData.Status.ObjectID)
+		if err != nil {
+			log.Error(err, "unable to decode object_id")
+			return requeue(client.IgnoreNotFound(err))
+		}
+
+		count, err := collection.CountDocuments(ctx, bson.M{"_id": findOID})
+		if err != nil {
+			log.Error(err, "unable to count document with ObjectID ", findOID)
+			return requeue(client.IgnoreNotFound(err))
+		}
+
+		if count > 0 {
+
+			// the document is already there, bring it up to date
+			if mongoData.Status.State != string(mongov1.MongoDBDataConditionInserted) {
+
+				msg := fmt.Sprintf("MongoDBData already exists in %s collection", collection.Name())
+				if err := r.setEventStatusInserted(ctx, mongoData, msg); err != nil {
+					log.Error(err, "unable to update target's status object")
+					return requeue(err)
+				}
+			}
+
+			return r.findAndUpdateDocumentIfNeeded(ctx, log, collection, mongoData, mongoCfg, document, secrets, encrypted, suspended)
+		}
+	}
+
+	// the document is neither inserted nor adopted while the writes are suspended
+	if suspended {
+
+		msg := "Document is not inserted while the writes are suspended"
+		cond := apimeta.FindStatusCondition(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionPending))
+		if cond == nil || cond.Message != msg {
+			if err := r.setEventStatusPending(ctx, mongoData, msg); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+		}
+
+		return doNotRequeue()
+	}
+
+	// the spec is only recorded as applied once it's written
+	applied := newAppliedSpec(mongoData, mongoCfg, document, secrets)
+
+	stored, err := encrypted.encrypt(ctx, document)
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	// take ownership of an existing document instead of inserting a new one
+	if mongoData.Spec.Adopt != nil {
+		return r.adoptDocument(ctx, log, collection, mongoData)
+	}
+
+	paths, err := mongodb.FieldPaths(stored)
+	if err != nil {
+		log.Error(err, "could not read the field paths of the document")
+		return requeue(err)
+	}
+	mongoData.Status.ManagedFields = paths
+
+	// documents with a natural key are upserted, so they can be found
+	// again when the status of the MongoDBData is lost
+	if len(mongoData.Spec.Key) > 0 {
+		return r.upsertDocument(ctx, log, collection, mongoData, mongoCfg, stored, applied)
+	}
+
+	// the inserted document starts with the next version of spec.concurrency
+	stored, err = withVersion(mongoData, stored, mongoData.Status.DocumentVersion+1)
+	if err != nil {
+		log.Error(err, "could not set the version field of the document")
+		return requeue(err)
+	}
+
+	if r.isDryRun(mongoData) {
+
+		redacted, err := r.redactSecrets(ctx, mongoData, stored)
+		if err != nil {
+			log.Error(err, "could not redact the secret values of the document")
+			return requeue(err)
+		}
+
+		if err := r.planWrite(ctx, mongoData, "Insert", nil, bson.Raw(redacted)); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	result, err := collection.InsertOne(ctx, stored)
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	if result.InsertedID != nil {
+
+		if mongoData.VersionField() != "" {
+			mongoData.Status.DocumentVersion++
+		}
+		applied.mark(mongoData)
+
+		mongoData.Status.ObjectID = result.InsertedID.(primitive.ObjectID).Hex()
+		mongoData.Status.Database = collection.Database().Name()
+		mongoData.Status.Collection = collection.Name()
+		msg := fmt.Sprintf("MongoDBData successfully Inserted into %s collection", collection.Name())
+		if err := r.setEventStatusInserted(ctx, mongoData, msg); err != nil {
+
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	return r.resync(mongoCfg, mongoData)
+}
+
+// adoptDocument will find an existing document selected by spec.adopt and
+// record its ObjectID, the document is then reconciled like an inserted one
+func (r *MongoDBDataReconciler) adoptDocument(
+	ctx context.Context,
+	log logr.Logger,
+	collection *mongo.Collection,
+	mongoData *mongov1.MongoDBData,
+) (ctrl.Result, error) {
+
+	filter, err := adoptFilter(mongoData.Spec.Adopt)
+	if err != nil {
+
+		if err := r.setEventStatusAdoptionFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	projection := bson.M{"_id": 1}
+	if versionField := mongoData.VersionField(); versionField != "" {
+		projection[versionField] = 1
+	}
+
+	// find at most two documents, that is enough to detect an ambiguous filter
+	curser, err := collection.Find(ctx, filter, options.Find().SetLimit(2).SetProjection(projection))
+	if err != nil {
+		log.Error(err, "unable to find the document to adopt")
+		return requeue(err)
+	}
+
+	var matches []bson.Raw
+	if err := curser.All(ctx, &matches); err != nil {
+		log.Error(err, "unable to decode the document to adopt")
+		return requeue(err)
+	}
+
+	if len(matches) != 1 {
+
+		msg := fmt.Sprintf("Adopt filter matches no document in %s collection", collection.Name())
+		if len(matches) > 1 {
+			msg = fmt.Sprintf("Adopt filter matches several documents in %s collection", collection.Name())
+		}
+
+		if err := r.setEventStatusAdoptionFailed(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	oid, ok := matches[0].Lookup("_id").ObjectIDOK()
+	if !ok {
+
+		msg := fmt.Sprintf("Document _id %v is not an ObjectID", matches[0].Lookup("_id"))
+		if err := r.setEventStatusAdoptionFailed(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	// a document is never shared, another MongoDBData would keep writing it
+	owner, err := r.otherDocumentOwner(ctx, mongoData, collection, oid)
+	if err != nil {
+		log.Error(err, "unable to find the owner of the document to adopt")
+		return requeue(err)
+	}
+
+	if owner != nil {
+
+		msg := fmt.Sprintf("Document %s is already owned by MongoDBData %s/%s", oid.Hex(), owner.Namespace, owner.Name)
+		if err := r.setEventStatusAdoptionFailed(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	mongoData.Status.ObjectID = oid.Hex()
+	mongoData.Status.Database = collection.Database().Name()
+	mongoData.Status.Collection = collection.Name()
+	mongoData.Status.DocumentVersion, _ = documentVersion(matches[0], mongoData.VersionField())
+
+	msg := fmt.Sprintf("MongoDBData successfully adopted document %s in %s collection", oid.Hex(), collection.Name())
+	if err := r.setEventStatusInserted(ctx, mongoData, msg); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	// the adopted document gets updated with spec.data on the next reconcile
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// adoptFilter returns the mongodb filter of the given spec.adopt
+func adoptFilter(adopt *mongov1.MongoDBDataAdopt) (interface{}, error) {
+	if adopt.ObjectID != "" {
+
+		oid, err := primitive.ObjectIDFromHex(adopt.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("adopt objectID %s is not valid: %v", adopt.ObjectID, err)
+		}
+
+		return bson.M{"_id": oid}, nil
+	}
+
+	if adopt.Filter != nil {
+		return mongodb.NewFilter(adopt.Filter.Raw)
+	}
+
+	return nil, fmt.Errorf("one of adopt objectID or filter must be specified")
+}
+
+// upsertDocument will find the current MongoDBData document by its natural key
+// then update it, or insert it if there is no document with that key
+func (r *MongoDBDataReconciler) upsertDocument(
+	ctx context.Context,
+	log logr.Logger,
+	collection *mongo.Collection,
+	mongoData *mongov1.MongoDBData,
+	mongoCfg *mongov1.MongoDBConfig,
+	document []byte,
+	applied appliedSpec,
+) (ctrl.Result, error) {
+
+	filter, err := mongodb.KeyFilter(document, mongoData.Spec.Key)
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	// find at most two documents, that is enough to detect a conflict
+	curser, err := collection.Find(ctx, filter, options.Find().SetLimit(2).SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		log.Error(err, "unable to find documents by key")
+		return requeue(err)
+	}
+
+	var matches []bson.M
+	if err := curser.All(ctx, &matches); err != nil {
+		log.Error(err, "unable to decode documents found by key")
+		return requeue(err)
+	}
+
+	if len(matches) > 1 {
+
+		msg := fmt.Sprintf("Key %v matches several documents in %s collection", mongoData.Spec.Key, collection.Name())
+		if err := r.setEventStatusConflict(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	// the document found by key must not be owned by another MongoDBData
+	if len(matches) == 1 {
+
+		oid, _ := matches[0]["_id"].(primitive.ObjectID)
+		owner, err := r.otherDocumentOwner(ctx, mongoData, collection, oid)
+		if err != nil {
+			log.Error(err, "unable to find the owner of the document found by key")
+			return requeue(err)
+		}
+
+		if owner != nil {
+
+			msg := fmt.Sprintf("Key %v matches the document of MongoDBData %s/%s", mongoData.Spec.Key, owner.Namespace, owner.Name)
+			if err := r.setEventStatusConflict(ctx, mongoData, msg); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			return requeueWithDelay(30 * time.Second)
+		}
+	}
+
+	// the version field is incremented, its new value is taken over on the next reconcile
+	update := bson.M{"$set": bson.Raw(document)}
+	if versionField := mongoData.VersionField(); versionField != "" {
+		update["$inc"] = bson.M{versionField: 1}
+	}
+
+	if r.isDryRun(mongoData) {
+
+		redacted, err := r.redactSecrets(ctx, mongoData, document)
+		if err != nil {
+			log.Error(err, "could not redact the secret values of the document")
+			return requeue(err)
+		}
+
+		planned := bson.M{"$set": bson.Raw(redacted)}
+		if inc, ok := update["$inc"]; ok {
+			planned["$inc"] = inc
+		}
+
+		// the key may be a field which comes from a Secret too
+		plannedFilter, err := r.redactFilter(ctx, mongoData, filter)
+		if err != nil {
+			log.Error(err, "could not redact the secret values of the key filter")
+			return requeue(err)
+		}
+
+		if err := r.planWrite(ctx, mongoData, "Upsert", plannedFilter, planned); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoData, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	var (
+		objectID interface{}
+		msg      string
+	)
+
+	if result.UpsertedID != nil {
+		objectID = result.UpsertedID
+		msg = fmt.Sprintf("MongoDBData successfully Inserted into %s collection", collection.Name())
+	} else {
+		objectID = matches[0]["_id"]
+		msg = fmt.Sprintf("MongoDBData successfully found by key in %s collection", collection.Name())
+	}
+
+	oid, ok := objectID.(primitive.ObjectID)
+	if !ok {
+
+		msg := fmt.Sprintf("Document _id %v is not an ObjectID", objectID)
+		if err := r.setEventStatusFailed(ctx, mongoData, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	mongoData.Status.ObjectID = oid.Hex()
+	mongoData.Status.Database = collection.Database().Name()
+	mongoData.Status.Collection = collection.Name()
+	applied.mark(mongoData)
+
+	// the key matches a single document again
+	if apimeta.IsStatusConditionTrue(mongoData.Status.Conditions, string(mongov1.MongoDBDataConditionConflict)) {
+		apimeta.SetStatusCondition(&mongoData.Status.Conditions, metav1.Condition{
+			Type:               string(mongov1.MongoDBDataConditionConflict),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(mongov1.MongoDBDataConditionConflict),
+			Message:            "Key matches a single document",
+			ObservedGeneration: mongoData.Generation,
+		})
+	}
+
+	if err := r.setEventStatusInserted(ctx, mongoData, msg); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	return r.resync(mongoCfg, mongoData)
+}
+
+// deleteDocument will delete the current MongoDBData document from database
+// according to its deletion policy
+func (r *MongoDBDataReconciler) deleteDocument(
+	ctx context.Context,
+	coll *mongo.Collection,
+	adapter *mongov1.MongoDBData,
+	mongoCfg *mongov1.MongoDBConfig,
+) (err error) {
+
+	policy := mongoCfg.DeletionPolicyFor(adapter)
+	if policy == mongov1.DeletionPolicyRetain {
+		return nil
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(adapter.Status.ObjectID)
+	if err != nil {
+		return err
+	}
+
+	if policy == mongov1.DeletionPolicySoftDelete {
+
+		softDelete := mongoCfg.SoftDeleteFor(adapter)
+
+		var value interface{} = true
+		if softDelete.Type == mongov1.SoftDeleteTypeTimestamp {
+			value = primitive.NewDateTimeFromTime(time.Now())
+		}
+
+		update := bson.M{"$set": bson.M{softDelete.Field: value}}
+		if r.isDryRun(adapter) {
+
+			// the timestamp is planned as $currentDate, so the planned write doesn't change on every reconcile
+			planned := update
+			if softDelete.Type == mongov1.SoftDeleteTypeTimestamp {
+				planned = bson.M{"$currentDate": bson.M{softDelete.Field: true}}
+			}
+
+			return r.planWrite(ctx, adapter, "SoftDelete", bson.M{"_id": objectID}, planned)
+		}
+
+		_, err = coll.UpdateByID(ctx, objectID, update)
+		return err
+	}
+
+	if r.isDryRun(adapter) {
+		return r.planWrite(ctx, adapter, "Delete", bson.M{"_id": objectID}, nil)
+	}
+
+	_, err = coll.DeleteOne(ctx, bson.M{"_id": objectID})
+	return client.IgnoreNotFound(err)
+}
+
+// documentCollection returns the mongodb collection of the given MongoDBData document
+func documentCollection(
+	mongoClient *mongo.Client,
+	mongoCfg *mongov1.MongoDBConfig,
+	mongoData *mongov1.MongoDBData,
+) (*mongo.Collection, error) {
+
+	var err error
+
+	// the document stays in the database and collection it was inserted into
+	dbName := mongoData.Status.Database
+	if dbName == "" {
+		if dbName, err = mongoCfg.DatabaseFor(mongoData); err != nil {
+			return nil, err
+		}
+	}
+
+	collName := mongoData.Status.Collection
+	if collName == "" {
+		if collName, err = mongoCfg.CollectionFor(mongoData); err != nil {
+			return nil, err
+		}
+	}
+
+	return mongoClient.Database(dbName).Collection(collName), nil
+}
+
+// collectionNotReady returns a message when the collection of the document is declared
+// by a MongoDBCollection which blocks its data and is not Ready yet
+func (r *MongoDBDataReconciler) collectionNotReady(
+	ctx context.Context,
+	mongoData *mongov1.MongoDBData,
+	collection *mongo.Collection,
+) (string, error) {
+
+	target := mongov1.CollectionTarget(mongoData.Spec.DB, collection.Database().Name(), collection.Name())
+
+	mongoColls := &mongov1.MongoDBCollectionList{}
+	if err := r.Client.List(ctx, mongoColls, client.MatchingFields{collectionTargetIndexKey: target}); err != nil {
+		return "", err
+	}
+
+	for _, mongoColl := range mongoColls.Items {
+		if mongoColl.Spec.BlockDataUntilReady && !mongoColl.IsReady() {
+			return fmt.Sprintf("MongoDBCollection %s/%s is not ready", mongoColl.Namespace, mongoColl.Name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MongoDBDataReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	// index the MongoDBCollections by their collections, so the MongoDBData can wait for them
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&mongov1.MongoDBCollection{},
+		collectionTargetIndexKey,
+		func(obj client.Object) []string {
+			mongoColl := obj.(*mongov1.MongoDBCollection)
+			return []string{mongov1.CollectionTarget(mongoColl.Spec.DB, mongoColl.Spec.Database, mongoColl.Spec.Collection)}
+		},
+	); err != nil {
+		return err
+	}
+
+	// index the MongoDBData by their document ids, so change events can be mapped to them
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&mongov1.MongoDBData{},
+		objectIDIndexKey,
+		func(obj client.Object) []string {
+			mongoData := obj.(*mongov1.MongoDBData)
+			if mongoData.Status.ObjectID == "" {
+				return nil
+			}
+			return []string{mongoData.Status.ObjectID}
+		},
+	); err != nil {
+		return err
+	}
+
+	// index the MongoDBData by their ConfigMaps and Secrets, so changes of the sources are applied
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&mongov1.MongoDBData{},
+		sourceIndexKey,
+		func(obj client.Object) []string {
+			return obj.(*mongov1.MongoDBData).SourceRefs()
+		},
+	); err != nil {
+		return err
+	}
+
+	// index the MongoDBData by their MongoDBConfigs, so suspension and sync-now reach them
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&mongov1.MongoDBData{},
+		configIndexKey,
+		func(obj client.Object) []string {
+			return []string{obj.(*mongov1.MongoDBData).Spec.DB}
+		},
+	); err != nil {
+		return err
+	}
+
+	// index the MongoDBData by the MongoDBData they reference, so new ObjectIDs are applied
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&mongov1.MongoDBData{},
+		referenceIndexKey,
+		func(obj client.Object) []string {
+			return obj.(*mongov1.MongoDBData).ReferenceRefs()
+		},
+	); err != nil {
+		return err
+	}
+
+	changes := make(chan event.GenericEvent, changeEventBufferSize)
+	r.ChangeStreams.SetHandler(func(ctx context.Context, ev mongodb.ChangeEvent) {
+		for _, mongoData := range r.findDataForChange(ctx, ev) {
+			select {
+			case changes <- event.GenericEvent{Object: mongoData}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mongov1.MongoDBData{}).
 		// registers an InstrumentedEnqueueRequest prometheus metric for mongov1.MongoDBData
 		Watches(&source.Kind{Type: &mongov1.MongoDBData{}}, &handler.InstrumentedEnqueueRequestForObject{}).
+		// reconcile the MongoDBData whose documents have been changed in mongodb
+		Watches(&source.Channel{Source: changes}, &handler.InstrumentedEnqueueRequestForObject{}).
+		// reconcile the MongoDBData whose dataFrom or secretFields sources have been changed
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, crhandler.EnqueueRequestsFromMapFunc(r.findDataForSource("ConfigMap"))).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, crhandler.EnqueueRequestsFromMapFunc(r.findDataForSource("Secret"))).
+		// reconcile the MongoDBData which are referencing a MongoDBData whose ObjectID has changed
+		Watches(
+			&source.Kind{Type: &mongov1.MongoDBData{}},
+			crhandler.EnqueueRequestsFromMapFunc(r.findDataForReference),
+			builder.WithPredicates(objectIDChangedPredicate()),
+		).
+		// reconcile the MongoDBData of a MongoDBConfig which has been suspended, resumed or synced
+		Watches(
+			&source.Kind{Type: &mongov1.MongoDBConfig{}},
+			crhandler.EnqueueRequestsFromMapFunc(r.findDataForConfig),
+			builder.WithPredicates(predicate.Or(suspendChangedPredicate(), syncNowChangedPredicate())),
+		).
 		Complete(r)
 }
+
+// findDataForConfig returns the MongoDBData of the given MongoDBConfig
+func (r *MongoDBDataReconciler) findDataForConfig(obj client.Object) []reconcile.Request {
+
+	mongoDataList, err := listDataForConfig(context.Background(), r.Client, obj.GetName())
+	if err != nil {
+		r.Log.Error(err, "unable to list MongoDBData", "config", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(mongoDataList.Items))
+	for _, mongoData := range mongoDataList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: k8sTypes.NamespacedName{Namespace: mongoData.Namespace, Name: mongoData.Name},
+		})
+	}
+
+	return requests
+}
+
+// listDataForConfig lists the MongoDBData of the MongoDBConfig with the given name
+func listDataForConfig(ctx context.Context, c client.Client, name string) (*mongov1.MongoDBDataList, error) {
+	mongoDataList := &mongov1.MongoDBDataList{}
+	err := c.List(ctx, mongoDataList, client.MatchingFields{configIndexKey: name})
+	return mongoDataList, err
+}
+
+// suspendChangedPredicate passes the updates of MongoDBConfigs which change their suspension
+func suspendChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCfg, ok := e.ObjectOld.(*mongov1.MongoDBConfig)
+			if !ok {
+				return false
+			}
+			newCfg, ok := e.ObjectNew.(*mongov1.MongoDBConfig)
+			if !ok {
+				return false
+			}
+			return oldCfg.Spec.Suspend != newCfg.Spec.Suspend
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// syncNowChangedPredicate passes the MongoDBConfig updates which change its sync-now value
+func syncNowChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCfg, ok := e.ObjectOld.(*mongov1.MongoDBConfig)
+			if !ok {
+				return false
+			}
+			newCfg, ok := e.ObjectNew.(*mongov1.MongoDBConfig)
+			if !ok {
+				return false
+			}
+			return oldCfg.SyncNowValue() != newCfg.SyncNowValue()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// findDataForSource returns a function which maps a ConfigMap or Secret
+// to the MongoDBData which are referencing it in their dataFrom or secretFields
+func (r *MongoDBDataReconciler) findDataForSource(kind string) crhandler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+
+		mongoDataList := &mongov1.MongoDBDataList{}
+		if err := r.Client.List(
+			context.Background(),
+			mongoDataList,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{sourceIndexKey: mongov1.SourceRef(kind, obj.GetName())},
+		); err != nil {
+			r.Log.Error(err, "unable to list MongoDBData", "kind", kind, "name", obj.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(mongoDataList.Items))
+		for _, mongoData := range mongoDataList.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: k8sTypes.NamespacedName{Namespace: mongoData.Namespace, Name: mongoData.Name},
+			})
+		}
+
+		return requests
+	}
+}
+
+// findDataForReference returns the MongoDBData which are referencing the given MongoDBData
+func (r *MongoDBDataReconciler) findDataForReference(obj client.Object) []reconcile.Request {
+
+	mongoDataList := &mongov1.MongoDBDataList{}
+	if err := r.Client.List(
+		context.Background(),
+		mongoDataList,
+		client.MatchingFields{referenceIndexKey: client.ObjectKeyFromObject(obj).String()},
+	); err != nil {
+		r.Log.Error(err, "unable to list MongoDBData", "reference", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(mongoDataList.Items))
+	for _, mongoData := range mongoDataList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: k8sTypes.NamespacedName{Namespace: mongoData.Namespace, Name: mongoData.Name},
+		})
+	}
+
+	return requests
+}
+
+// objectIDChangedPredicate passes the creations and deletions of MongoDBData
+// and the updates which change their ObjectID
+func objectIDChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldData, ok := e.ObjectOld.(*mongov1.MongoDBData)
+			if !ok {
+				return false
+			}
+			newData, ok := e.ObjectNew.(*mongov1.MongoDBData)
+			if !ok {
+				return false
+			}
+			return oldData.Status.ObjectID != newData.Status.ObjectID
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// findDataForChange returns the MongoDBData which are owning the changed document
+func (r *MongoDBDataReconciler) findDataForChange(ctx context.Context, ev mongodb.ChangeEvent) []*mongov1.MongoDBData {
+	owners, err := r.findDataForDocument(ctx, ev.Database, ev.Collection, ev.DocumentID)
+	if err != nil {
+		r.Log.Error(err, "unable to list MongoDBData")
+		return nil
+	}
+	return owners
+}
+
+// findDataForDocument returns the MongoDBData which are owning the given document
+func (r *MongoDBDataReconciler) findDataForDocument(
+	ctx context.Context,
+	database, collection string,
+	objectID primitive.ObjectID,
+) ([]*mongov1.MongoDBData, error) {
+
+	mongoDataList := &mongov1.MongoDBDataList{}
+	if err := r.Client.List(
+		ctx,
+		mongoDataList,
+		client.MatchingFields{objectIDIndexKey: objectID.Hex()},
+	); err != nil {
+		return nil, err
+	}
+
+	owners := []*mongov1.MongoDBData{}
+	for i := range mongoDataList.Items {
+		mongoData := &mongoDataList.Items[i]
+		if mongoData.Status.Database == database && mongoData.Status.Collection == collection {
+			owners = append(owners, mongoData)
+		}
+	}
+
+	return owners, nil
+}
+
+// otherDocumentOwner returns another MongoDBData which is already owning the given document
+func (r *MongoDBDataReconciler) otherDocumentOwner(
+	ctx context.Context,
+	mongoData *mongov1.MongoDBData,
+	collection *mongo.Collection,
+	objectID primitive.ObjectID,
+) (*mongov1.MongoDBData, error) {
+
+	owners, err := r.findDataForDocument(ctx, collection.Database().Name(), collection.Name(), objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, owner := range owners {
+		if owner.UID != mongoData.UID {
+			return owner, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,714 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+func TestAdoptFilter(t *testing.T) {
+	oid, _ := primitive.ObjectIDFromHex("5f1d7f2e8b0e4a3b2c1d0e0f")
+
+	tests := []struct {
+		name    string
+		adopt   *mongov1.MongoDBDataAdopt
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:  "object id",
+			adopt: &mongov1.MongoDBDataAdopt{ObjectID: oid.Hex()},
+			want:  bson.M{"_id": oid},
+		},
+		{
+			name:  "filter",
+			adopt: &mongov1.MongoDBDataAdopt{Filter: &runtime.RawExtension{Raw: []byte(`{"email":"a@example.com"}`)}},
+			want:  bson.D{{Key: "email", Value: "a@example.com"}},
+		},
+		{
+			name:    "invalid object id",
+			adopt:   &mongov1.MongoDBDataAdopt{ObjectID: "abc"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid filter",
+			adopt:   &mongov1.MongoDBDataAdopt{Filter: &runtime.RawExtension{Raw: []byte(`{"email":`)}},
+			wantErr: true,
+		},
+		{
+			name:    "empty adopt",
+			adopt:   &mongov1.MongoDBDataAdopt{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adoptFilter(tt.adopt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("adoptFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("adoptFilter() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResync(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval *metav1.Duration
+		want     ctrl.Result
+	}{
+		{
+			name: "resync disabled",
+			want: ctrl.Result{},
+		},
+		{
+			name:     "requeued after the resync interval",
+			interval: &metav1.Duration{Duration: time.Minute},
+			want:     ctrl.Result{RequeueAfter: time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MongoDBDataReconciler{}
+			mongoCfg := &mongov1.MongoDBConfig{Spec: mongov1.MongoDBConfigSpec{ResyncInterval: tt.interval}}
+
+			got, err := r.resync(mongoCfg, &mongov1.MongoDBData{})
+			if err != nil {
+				t.Fatalf("resync() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resync() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDataApplied(t *testing.T) {
+	var (
+		document = mustMarshal(t, bson.D{{Key: "name", Value: "x"}})
+		dataFrom = []mongov1.MongoDBDataFrom{{ConfigMapKeyRef: &mongov1.ConfigMapKeyReference{Name: "settings", Key: "a"}}}
+		inserted = []metav1.Condition{{Type: string(mongov1.MongoDBDataConditionInserted), ObservedGeneration: 2}}
+	)
+
+	tests := []struct {
+		name       string
+		generation int64
+		applied    int64
+		conditions []metav1.Condition
+		dataFrom   []mongov1.MongoDBDataFrom
+		hash       string
+		want       bool
+	}{
+		{
+			name:       "never inserted",
+			generation: 1,
+			want:       false,
+		},
+		{
+			name:       "current generation applied",
+			generation: 2,
+			applied:    2,
+			want:       true,
+		},
+		{
+			name:       "adopted but not applied",
+			generation: 2,
+			conditions: inserted,
+			want:       false,
+		},
+		{
+			name:       "spec changed after the write",
+			generation: 3,
+			applied:    2,
+			want:       false,
+		},
+		{
+			name:       "dataFrom sources unchanged",
+			generation: 2,
+			applied:    2,
+			dataFrom:   dataFrom,
+			hash:       dataFromHash(&mongov1.MongoDBData{Spec: mongov1.MongoDBDataSpec{DataFrom: dataFrom}}, document),
+			want:       true,
+		},
+		{
+			name:       "dataFrom sources changed",
+			generation: 2,
+			applied:    2,
+			dataFrom:   dataFrom,
+			hash:       "previous",
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &mongov1.MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+				Spec:       mongov1.MongoDBDataSpec{DataFrom: tt.dataFrom},
+				Status: mongov1.MongoDBDataStatus{
+					Conditions:        tt.conditions,
+					AppliedGeneration: tt.applied,
+					DataFromHash:      tt.hash,
+				},
+			}
+
+			r := &MongoDBDataReconciler{}
+			if got := r.isDataApplied(mongoData, document); got != tt.want {
+				t.Errorf("isDataApplied() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataFromHash(t *testing.T) {
+	mongoData := &mongov1.MongoDBData{Spec: mongov1.MongoDBDataSpec{
+		DataFrom:     []mongov1.MongoDBDataFrom{{ConfigMapKeyRef: &mongov1.ConfigMapKeyReference{Name: "settings", Key: "a"}}},
+		SecretFields: []mongov1.MongoDBSecretField{{Path: "auth.password"}},
+	}}
+
+	var (
+		document = mustMarshal(t, bson.D{
+			{Key: "name", Value: "x"},
+			{Key: "auth", Value: bson.D{{Key: "user", Value: "app"}, {Key: "password", Value: "hunter2"}}},
+		})
+		rotated = mustMarshal(t, bson.D{
+			{Key: "name", Value: "x"},
+			{Key: "auth", Value: bson.D{{Key: "user", Value: "app"}, {Key: "password", Value: "hunter3"}}},
+		})
+		changed = mustMarshal(t, bson.D{
+			{Key: "name", Value: "y"},
+			{Key: "auth", Value: bson.D{{Key: "user", Value: "app"}, {Key: "password", Value: "hunter2"}}},
+		})
+	)
+
+	hash := dataFromHash(mongoData, document)
+	if hash == "" {
+		t.Fatalf("dataFromHash() is empty with spec.dataFrom")
+	}
+	if got := dataFromHash(mongoData, rotated); got != hash {
+		t.Errorf("dataFromHash() changed with the value of a secret field")
+	}
+	if got := dataFromHash(mongoData, changed); got == hash {
+		t.Errorf("dataFromHash() didn't change with the document")
+	}
+	if got := dataFromHash(&mongov1.MongoDBData{}, document); got != "" {
+		t.Errorf("dataFromHash() = %s without spec.dataFrom, want it empty", got)
+	}
+}
+
+func TestSecretFieldsMerge(t *testing.T) {
+	document := mustMarshal(t, bson.D{{Key: "name", Value: "x"}})
+
+	tests := []struct {
+		name   string
+		values bson.D
+		want   bson.D
+	}{
+		{
+			name: "no secret fields",
+			want: bson.D{{Key: "name", Value: "x"}},
+		},
+		{
+			name:   "secret fields set at their paths",
+			values: bson.D{{Key: "auth.password", Value: "hunter2"}, {Key: "token", Value: "abc"}},
+			want: bson.D{
+				{Key: "name", Value: "x"},
+				{Key: "auth", Value: bson.D{{Key: "password", Value: "hunter2"}}},
+				{Key: "token", Value: "abc"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := secretFields{values: tt.values}.merge(document)
+			if err != nil {
+				t.Fatalf("merge() error = %v", err)
+			}
+
+			var got bson.D
+			if err := bson.Unmarshal(merged, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("merge() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, doc bson.D) []byte {
+	t.Helper()
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSecretFieldsStored(t *testing.T) {
+	document := mustMarshal(t, bson.D{
+		{Key: "name", Value: "x"},
+		{Key: "auth", Value: bson.D{{Key: "password", Value: "hunter2"}}},
+	})
+
+	tests := []struct {
+		name    string
+		values  bson.D
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "values are read from the document",
+			values: bson.D{{Key: "auth.password", Value: "plain"}},
+			want:   []string{"auth.password"},
+		},
+		{
+			name:    "missing field",
+			values:  bson.D{{Key: "token", Value: "abc"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := secretFields{values: tt.values}.stored(document)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("stored() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("stored() = %v, want the paths %v", got, tt.want)
+			}
+			for i, e := range got {
+				// the value as it is in the document, which may be encrypted
+				if e.Key != tt.want[i] || e.Value.(bson.RawValue).StringValue() != "hunter2" {
+					t.Errorf("stored()[%d] = %v, want the stored value of %s", i, e, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeFields(t *testing.T) {
+	oid := primitive.NewObjectID()
+	document := mustMarshal(t, bson.D{{Key: "name", Value: "x"}, {Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}}}})
+
+	tests := []struct {
+		name   string
+		values bson.D
+		want   bson.D
+	}{
+		{
+			name: "no values",
+			want: bson.D{{Key: "name", Value: "x"}, {Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}}}},
+		},
+		{
+			name:   "object ids set at their paths",
+			values: bson.D{{Key: "authorId", Value: oid}, {Key: "meta.publisherId", Value: oid}},
+			want: bson.D{
+				{Key: "name", Value: "x"},
+				{Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}, {Key: "publisherId", Value: oid}}},
+				{Key: "authorId", Value: oid},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := mergeFields(document, tt.values)
+			if err != nil {
+				t.Fatalf("mergeFields() error = %v", err)
+			}
+
+			var got bson.D
+			if err := bson.Unmarshal(merged, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeFields() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldsUpdate(t *testing.T) {
+	document := mustMarshal(t, bson.D{{Key: "name", Value: "x"}, {Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}}}})
+
+	tests := []struct {
+		name    string
+		set     []string
+		unset   []string
+		want    bson.D
+		wantErr bool
+	}{
+		{
+			name: "nothing to change",
+			want: bson.D{},
+		},
+		{
+			name:  "set and unset paths",
+			set:   []string{"name", "meta.tag"},
+			unset: []string{"old"},
+			want: bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "name", Value: bson.Raw(document).Lookup("name")},
+					{Key: "meta.tag", Value: bson.Raw(document).Lookup("meta", "tag")},
+				}},
+				{Key: "$unset", Value: bson.D{{Key: "old", Value: ""}}},
+			},
+		},
+		{
+			name:    "path not in document",
+			set:     []string{"other"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldsUpdate(document, tt.set, tt.unset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fieldsUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldsUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptedFieldsDiff(t *testing.T) {
+	var (
+		email = mongodb.EncryptedField{Path: "email", Algorithm: mongodb.AlgorithmDeterministic}
+		ssn   = mongodb.EncryptedField{Path: "ssn", Algorithm: mongodb.AlgorithmRandom}
+		paths = []string{"name", "email", "ssn"}
+	)
+
+	tests := []struct {
+		name   string
+		found  []mongodb.EncryptedField
+		fields []mongodb.EncryptedField
+		want   []string
+	}{
+		{
+			name: "no encrypted fields",
+			want: []string{},
+		},
+		{
+			name:   "same fields in another order",
+			found:  []mongodb.EncryptedField{ssn, email},
+			fields: []mongodb.EncryptedField{email, ssn},
+			want:   []string{},
+		},
+		{
+			name:   "field not encrypted yet",
+			found:  []mongodb.EncryptedField{email},
+			fields: []mongodb.EncryptedField{email, ssn},
+			want:   []string{"ssn"},
+		},
+		{
+			name:   "algorithm changed",
+			found:  []mongodb.EncryptedField{{Path: "email", Algorithm: mongodb.AlgorithmRandom}},
+			fields: []mongodb.EncryptedField{email},
+			want:   []string{"email"},
+		},
+		{
+			name:  "field not encrypted anymore",
+			found: []mongodb.EncryptedField{email},
+			want:  []string{"email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (encryptedFields{fields: tt.fields}).diff(tt.found, paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentVersion(t *testing.T) {
+	document := mustMarshal(t, bson.D{
+		{Key: "_version", Value: int32(3)},
+		{Key: "meta", Value: bson.D{{Key: "version", Value: int64(7)}}},
+	})
+
+	tests := []struct {
+		name       string
+		field      string
+		want       int64
+		wantFilter bson.E
+	}{
+		{
+			name:       "not enabled",
+			wantFilter: bson.E{},
+		},
+		{
+			name:       "int32 version",
+			field:      "_version",
+			want:       3,
+			wantFilter: bson.E{Key: "_version", Value: bson.Raw(document).Lookup("_version")},
+		},
+		{
+			name:       "nested version",
+			field:      "meta.version",
+			want:       7,
+			wantFilter: bson.E{Key: "meta.version", Value: bson.Raw(document).Lookup("meta", "version")},
+		},
+		{
+			name:       "missing version",
+			field:      "version",
+			want:       0,
+			wantFilter: bson.E{Key: "version", Value: bson.D{{Key: "$exists", Value: false}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, filter := documentVersion(document, tt.field)
+			if got != tt.want {
+				t.Errorf("documentVersion() = %d, want %d", got, tt.want)
+			}
+			if !reflect.DeepEqual(filter, tt.wantFilter) {
+				t.Errorf("documentVersion() filter = %v, want %v", filter, tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestSetVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		update bson.D
+		want   bson.D
+	}{
+		{
+			name:   "added to $set",
+			update: bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: "x"}}}},
+			want:   bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: "x"}, {Key: "_version", Value: int64(2)}}}},
+		},
+		{
+			name:   "only $unset",
+			update: bson.D{{Key: "$unset", Value: bson.D{{Key: "old", Value: ""}}}},
+			want: bson.D{
+				{Key: "$unset", Value: bson.D{{Key: "old", Value: ""}}},
+				{Key: "$set", Value: bson.D{{Key: "_version", Value: int64(2)}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setVersion(tt.update, "_version", 2); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithVersion(t *testing.T) {
+	document := mustMarshal(t, bson.D{{Key: "name", Value: "x"}})
+
+	tests := []struct {
+		name        string
+		concurrency *mongov1.ConcurrencySpec
+		want        bson.D
+	}{
+		{
+			name: "not enabled",
+			want: bson.D{{Key: "name", Value: "x"}},
+		},
+		{
+			name:        "version field set",
+			concurrency: &mongov1.ConcurrencySpec{VersionField: "meta.version"},
+			want: bson.D{
+				{Key: "name", Value: "x"},
+				{Key: "meta", Value: bson.D{{Key: "version", Value: int64(4)}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoData := &mongov1.MongoDBData{Spec: mongov1.MongoDBDataSpec{Concurrency: tt.concurrency}}
+
+			versioned, err := withVersion(mongoData, document, 4)
+			if err != nil {
+				t.Fatalf("withVersion() error = %v", err)
+			}
+
+			var got bson.D
+			if err := bson.Unmarshal(versioned, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withVersion() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuspendChangedPredicate(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldSuspend bool
+		newSuspend bool
+		want       bool
+	}{
+		{
+			name: "not changed",
+			want: false,
+		},
+		{
+			name:       "suspended",
+			newSuspend: true,
+			want:       true,
+		},
+		{
+			name:       "resumed",
+			oldSuspend: true,
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event.UpdateEvent{
+				ObjectOld: &mongov1.MongoDBConfig{Spec: mongov1.MongoDBConfigSpec{Suspend: tt.oldSuspend}},
+				ObjectNew: &mongov1.MongoDBConfig{Spec: mongov1.MongoDBConfigSpec{Suspend: tt.newSuspend}},
+			}
+			if got := suspendChangedPredicate().Update(e); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncNowChangedPredicate(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldSyncNow  string
+		newSyncNow  string
+		lastSyncNow string
+		want        bool
+	}{
+		{
+			name: "not changed",
+			want: false,
+		},
+		{
+			name:       "new value",
+			oldSyncNow: "2022-07-01T10:00:00Z",
+			newSyncNow: "2022-08-01T10:00:00Z",
+			want:       true,
+		},
+		{
+			name:        "handled value removed",
+			oldSyncNow:  "2022-08-01T10:00:00Z",
+			lastSyncNow: "2022-08-01T10:00:00Z",
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newCfg := func(value string) *mongov1.MongoDBConfig {
+				mongoCfg := &mongov1.MongoDBConfig{Status: mongov1.MongoDBConfigStatus{LastSyncNow: tt.lastSyncNow}}
+				if value != "" {
+					mongoCfg.Annotations = map[string]string{mongov1.SyncNowAnnotation: value}
+				}
+				return mongoCfg
+			}
+
+			e := event.UpdateEvent{ObjectOld: newCfg(tt.oldSyncNow), ObjectNew: newCfg(tt.newSyncNow)}
+			if got := syncNowChangedPredicate().Update(e); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppliedSpecMark(t *testing.T) {
+	document := mustMarshal(t, bson.D{{Key: "name", Value: "x"}})
+	dataFrom := []mongov1.MongoDBDataFrom{{ConfigMapKeyRef: &mongov1.ConfigMapKeyReference{Name: "settings", Key: "a"}}}
+
+	mongoData := &mongov1.MongoDBData{
+		ObjectMeta: metav1.ObjectMeta{
+			Generation: 3,
+			Annotations: map[string]string{
+				mongov1.RollbackAnnotation: "2",
+				mongov1.SyncNowAnnotation:  "2022-08-01T10:00:00Z",
+			},
+		},
+		Spec: mongov1.MongoDBDataSpec{DataFrom: dataFrom},
+	}
+
+	mongoCfg := &mongov1.MongoDBConfig{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{mongov1.SyncNowAnnotation: "2022-09-01T10:00:00Z"}},
+	}
+
+	applied := newAppliedSpec(mongoData, mongoCfg, document, secretFields{version: "v7"})
+	if !applied.syncRequested {
+		t.Errorf("newAppliedSpec() syncRequested = false, want true")
+	}
+
+	// nothing is recorded until the spec is written
+	if mongoData.Status.AppliedGeneration != 0 || mongoData.Status.DataFromHash != "" {
+		t.Fatalf("newAppliedSpec() changed the status to %+v", mongoData.Status)
+	}
+
+	applied.mark(mongoData)
+
+	want := mongov1.MongoDBDataStatus{
+		AppliedGeneration:   3,
+		DataFromHash:        dataFromHash(mongoData, document),
+		SecretFieldsVersion: "v7",
+		RolledBackTo:        2,
+		LastSyncNow:         "2022-08-01T10:00:00Z",
+		LastConfigSyncNow:   "2022-09-01T10:00:00Z",
+	}
+	if !reflect.DeepEqual(mongoData.Status, want) {
+		t.Errorf("mark() status = %+v, want %+v", mongoData.Status, want)
+	}
+
+	r := &MongoDBDataReconciler{}
+	if !r.isDataApplied(mongoData, document) {
+		t.Errorf("isDataApplied() = false after mark()")
+	}
+}
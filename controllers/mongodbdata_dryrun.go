@@ -0,0 +1,137 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+)
+
+// redactedValue replaces the values which come from Secrets in the planned writes
+const redactedValue = "<redacted>"
+
+// isDryRun reports whether the writes of the MongoDBData are only computed and reported,
+// either for the whole operator or by the dry-run annotation of the MongoDBData
+func (r *MongoDBDataReconciler) isDryRun(mongoData *mongov1.MongoDBData) bool {
+	return r.DryRun || mongoData.IsDryRun()
+}
+
+// dryRunHeldMessage explains what holds the deletion of the MongoDBData in dry-run mode
+func (r *MongoDBDataReconciler) dryRunHeldMessage(mongoData *mongov1.MongoDBData) string {
+	if mongoData.IsDryRun() {
+		return fmt.Sprintf("Deletion is held until the %s annotation is removed", mongov1.DryRunAnnotation)
+	}
+	return "Deletion is held until the operator runs without --data-dry-run"
+}
+
+// planWrite reports the write which would be made to the document in status.plannedWrite
+// and an event, the event is only recorded when the planned write changes
+func (r *MongoDBDataReconciler) planWrite(
+	ctx context.Context,
+	mongoData *mongov1.MongoDBData,
+	operation string,
+	filter interface{},
+	document interface{},
+) error {
+
+	write := &mongov1.MongoDBDataWrite{Operation: operation}
+
+	if filter != nil {
+		data, err := bson.MarshalExtJSON(filter, false, false)
+		if err != nil {
+			return err
+		}
+		write.Filter = string(data)
+	}
+
+	if document != nil {
+		data, err := bson.MarshalExtJSON(document, false, false)
+		if err != nil {
+			return err
+		}
+		write.Document = string(data)
+	}
+
+	if reflect.DeepEqual(mongoData.Status.PlannedWrite, write) {
+		return nil
+	}
+
+	return r.setEventPlannedWrite(ctx, mongoData, write)
+}
+
+// clearPlannedWrite removes the planned write from the status
+// once there is nothing left to write or dry-run is turned off
+func (r *MongoDBDataReconciler) clearPlannedWrite(ctx context.Context, mongoData *mongov1.MongoDBData) error {
+	if mongoData.Status.PlannedWrite == nil {
+		return nil
+	}
+
+	mongoData.Status.PlannedWrite = nil
+	return r.Client.Status().Update(ctx, mongoData)
+}
+
+// secretPaths returns the document field paths whose values come from Secrets,
+// either by spec.secretFields or by the secretKeyRef of spec.dataFrom
+func (r *MongoDBDataReconciler) secretPaths(ctx context.Context, mongoData *mongov1.MongoDBData) ([]string, error) {
+	paths, err := mongoData.SecretDataPaths(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+	return append(paths, mongoData.SecretFieldPaths()...), nil
+}
+
+// redactSecrets returns the bson document with the values which come from Secrets redacted,
+// so the planned writes can be published without them
+func (r *MongoDBDataReconciler) redactSecrets(ctx context.Context, mongoData *mongov1.MongoDBData, document []byte) ([]byte, error) {
+	paths, err := r.secretPaths(ctx, mongoData)
+	if err != nil {
+		return nil, err
+	}
+
+	values := bson.D{}
+	for _, path := range paths {
+		values = append(values, bson.E{Key: path, Value: redactedValue})
+	}
+	return mergeFields(document, values)
+}
+
+// redactFilter returns the filter with the values which come from Secrets redacted, a key
+// is redacted when it's a secret path, lies within one or contains one
+func (r *MongoDBDataReconciler) redactFilter(ctx context.Context, mongoData *mongov1.MongoDBData, filter bson.D) (bson.D, error) {
+	paths, err := r.secretPaths(ctx, mongoData)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := make(bson.D, 0, len(filter))
+	for _, e := range filter {
+		for _, path := range paths {
+			if e.Key == path || strings.HasPrefix(e.Key, path+".") || strings.HasPrefix(path, e.Key+".") {
+				e.Value = redactedValue
+				break
+			}
+		}
+		redacted = append(redacted, e)
+	}
+	return redacted, nil
+}
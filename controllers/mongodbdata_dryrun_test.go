@@ -0,0 +1,272 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+)
+
+// dryRunReconciler returns a reconciler with the given objects in its client
+func dryRunReconciler(t *testing.T, objs ...runtime.Object) *MongoDBDataReconciler {
+	t.Helper()
+
+	scheme := newTestScheme(t)
+	return &MongoDBDataReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build(),
+		Scheme: scheme,
+	}
+}
+
+func TestIsDryRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "disabled",
+			want: false,
+		},
+		{
+			name:   "operator flag",
+			dryRun: true,
+			want:   true,
+		},
+		{
+			name:        "annotation",
+			annotations: map[string]string{mongov1.DryRunAnnotation: "true"},
+			want:        true,
+		},
+		{
+			name:        "annotation set to false",
+			annotations: map[string]string{mongov1.DryRunAnnotation: "false"},
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MongoDBDataReconciler{DryRun: tt.dryRun}
+			mongoData := &mongov1.MongoDBData{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := r.isDryRun(mongoData); got != tt.want {
+				t.Errorf("isDryRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDryRunHeldMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name:   "operator flag",
+			dryRun: true,
+			want:   "Deletion is held until the operator runs without --data-dry-run",
+		},
+		{
+			name:        "annotation",
+			annotations: map[string]string{mongov1.DryRunAnnotation: "true"},
+			want:        "Deletion is held until the " + mongov1.DryRunAnnotation + " annotation is removed",
+		},
+		{
+			name:        "annotation and operator flag",
+			dryRun:      true,
+			annotations: map[string]string{mongov1.DryRunAnnotation: "true"},
+			want:        "Deletion is held until the " + mongov1.DryRunAnnotation + " annotation is removed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MongoDBDataReconciler{DryRun: tt.dryRun}
+			mongoData := &mongov1.MongoDBData{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := r.dryRunHeldMessage(mongoData); got != tt.want {
+				t.Errorf("dryRunHeldMessage() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "creds"},
+		Data: map[string][]byte{
+			"password": []byte("hunter2"),
+			"root":     []byte(`{"token":"abc","apiKey":"def"}`),
+		},
+	}
+
+	document := bson.D{
+		{Key: "name", Value: "example"},
+		{Key: "token", Value: "abc"},
+		{Key: "apiKey", Value: "def"},
+		{Key: "db", Value: bson.D{{Key: "user", Value: "app"}, {Key: "password", Value: "hunter2"}}},
+		{Key: "auth", Value: bson.D{{Key: "password", Value: "hunter2"}}},
+	}
+
+	tests := []struct {
+		name string
+		spec mongov1.MongoDBDataSpec
+		want bson.D
+	}{
+		{
+			name: "nothing from secrets",
+			want: document,
+		},
+		{
+			name: "secret fields",
+			spec: mongov1.MongoDBDataSpec{
+				SecretFields: []mongov1.MongoDBSecretField{{
+					Path:         "db.password",
+					SecretKeyRef: mongov1.LocalSecretKeyReference{Name: "creds", Key: "password"},
+				}},
+			},
+			want: bson.D{
+				{Key: "name", Value: "example"},
+				{Key: "token", Value: "abc"},
+				{Key: "apiKey", Value: "def"},
+				{Key: "db", Value: bson.D{{Key: "user", Value: "app"}, {Key: "password", Value: redactedValue}}},
+				{Key: "auth", Value: bson.D{{Key: "password", Value: "hunter2"}}},
+			},
+		},
+		{
+			name: "secret merged at a path",
+			spec: mongov1.MongoDBDataSpec{
+				DataFrom: []mongov1.MongoDBDataFrom{{
+					SecretKeyRef: &mongov1.LocalSecretKeyReference{Name: "creds", Key: "password"},
+					Path:         "auth.password",
+				}},
+			},
+			want: bson.D{
+				{Key: "name", Value: "example"},
+				{Key: "token", Value: "abc"},
+				{Key: "apiKey", Value: "def"},
+				{Key: "db", Value: bson.D{{Key: "user", Value: "app"}, {Key: "password", Value: "hunter2"}}},
+				{Key: "auth", Value: bson.D{{Key: "password", Value: redactedValue}}},
+			},
+		},
+		{
+			name: "secret merged into the root",
+			spec: mongov1.MongoDBDataSpec{
+				DataFrom: []mongov1.MongoDBDataFrom{{
+					SecretKeyRef: &mongov1.LocalSecretKeyReference{Name: "creds", Key: "root"},
+				}},
+			},
+			want: bson.D{
+				{Key: "name", Value: "example"},
+				{Key: "token", Value: redactedValue},
+				{Key: "apiKey", Value: redactedValue},
+				{Key: "db", Value: bson.D{{Key: "user", Value: "app"}, {Key: "password", Value: "hunter2"}}},
+				{Key: "auth", Value: bson.D{{Key: "password", Value: "hunter2"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := dryRunReconciler(t, secret)
+			mongoData := &mongov1.MongoDBData{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "example"},
+				Spec:       tt.spec,
+			}
+
+			data, err := bson.Marshal(document)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			redacted, err := r.redactSecrets(context.Background(), mongoData, data)
+			if err != nil {
+				t.Fatalf("redactSecrets() error = %v", err)
+			}
+
+			var got bson.D
+			if err := bson.Unmarshal(redacted, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactSecrets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactFilter(t *testing.T) {
+	mongoData := &mongov1.MongoDBData{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "example"},
+		Spec: mongov1.MongoDBDataSpec{
+			SecretFields: []mongov1.MongoDBSecretField{{
+				Path:         "auth.token",
+				SecretKeyRef: mongov1.LocalSecretKeyReference{Name: "creds", Key: "token"},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter bson.D
+		want   bson.D
+	}{
+		{
+			name:   "key without secrets",
+			filter: bson.D{{Key: "name", Value: "example"}},
+			want:   bson.D{{Key: "name", Value: "example"}},
+		},
+		{
+			name:   "secret key",
+			filter: bson.D{{Key: "name", Value: "example"}, {Key: "auth.token", Value: "abc"}},
+			want:   bson.D{{Key: "name", Value: "example"}, {Key: "auth.token", Value: redactedValue}},
+		},
+		{
+			name:   "key containing a secret",
+			filter: bson.D{{Key: "auth", Value: bson.D{{Key: "token", Value: "abc"}}}},
+			want:   bson.D{{Key: "auth", Value: redactedValue}},
+		},
+		{
+			name:   "common prefix is not a parent",
+			filter: bson.D{{Key: "auth.tokens", Value: "abc"}},
+			want:   bson.D{{Key: "auth.tokens", Value: "abc"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dryRunReconciler(t).redactFilter(context.Background(), mongoData, tt.filter)
+			if err != nil {
+				t.Fatalf("redactFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
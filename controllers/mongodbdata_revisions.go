@@ -0,0 +1,251 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+	"github.com/pingcap/errors"
+)
+
+// revisionKeyPrefix prefixes the secret keys of the document revisions
+const revisionKeyPrefix = "revision-"
+
+// documentRevision is an applied document in the revision history of a MongoDBData
+type documentRevision struct {
+	Revision   int64       `json:"revision"`
+	Generation int64       `json:"generation"`
+	Timestamp  metav1.Time `json:"timestamp"`
+	Hash       string      `json:"hash"`
+
+	// Document is the canonical extended json of the document without its secret fields
+	Document json.RawMessage `json:"document"`
+}
+
+// recordRevision adds the document to the revision history of the MongoDBData when it differs
+// from the latest revision, the oldest revisions are dropped beyond the history limit or when the
+// secret would exceed its maximum size. The secret fields are left out, they are resolved again
+// when the revision is rolled back to
+func (r *MongoDBDataReconciler) recordRevision(ctx context.Context, mongoData *mongov1.MongoDBData, document []byte) error {
+
+	limit := mongoData.RevisionHistoryLimit()
+	if limit == 0 {
+		return nil
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(document, &doc); err != nil {
+		return err
+	}
+
+	data, err := bson.Marshal(mongodb.RemoveFields(doc, mongoData.SecretFieldPaths()))
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	secret := &corev1.Secret{}
+	err = r.Client.Get(ctx, k8sTypes.NamespacedName{Namespace: mongoData.Namespace, Name: mongoData.RevisionsSecretName()}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	exists := err == nil
+	if exists {
+		if err := checkRevisionsOwner(secret, mongoData); err != nil {
+			return err
+		}
+	}
+
+	revisions, err := readRevisions(secret)
+	if err != nil {
+		return err
+	}
+
+	if len(revisions) > 0 && revisions[len(revisions)-1].Hash == hash {
+		return nil
+	}
+
+	extJSON, err := bson.MarshalExtJSON(bson.Raw(data), true, false)
+	if err != nil {
+		return err
+	}
+
+	next := mongoData.Status.Revision + 1
+	if len(revisions) > 0 && revisions[len(revisions)-1].Revision >= next {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+
+	revision := documentRevision{
+		Revision:   next,
+		Generation: mongoData.Generation,
+		Timestamp:  metav1.Now(),
+		Hash:       hash,
+		Document:   extJSON,
+	}
+
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: mongoData.Namespace,
+				Name:      mongoData.RevisionsSecretName(),
+			},
+		}
+
+		if err := controllerutil.SetControllerReference(mongoData, secret, r.Scheme); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(revision)
+	if err != nil {
+		return err
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[revisionKey(next)] = value
+
+	revisions = pruneRevisions(secret, append(revisions, revision), limit, corev1.MaxSecretSize)
+
+	// a document which doesn't fit into the secret on its own is not recorded
+	if size := secretDataSize(secret); size > corev1.MaxSecretSize {
+		msg := fmt.Sprintf("Revision %d is not recorded, it needs %d bytes and a secret can hold %d", next, size, corev1.MaxSecretSize)
+		r.Recorder.Event(mongoData, corev1.EventTypeWarning, "RevisionTooLarge", msg)
+		return nil
+	}
+
+	if exists {
+		err = r.Client.Update(ctx, secret)
+	} else {
+		err = r.Client.Create(ctx, secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	mongoData.Status.Revision = next
+	return r.Client.Status().Update(ctx, mongoData)
+}
+
+// revisionDocument returns the bson document of the given revision of the MongoDBData
+func (r *MongoDBDataReconciler) revisionDocument(ctx context.Context, mongoData *mongov1.MongoDBData, revision int64) ([]byte, error) {
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, k8sTypes.NamespacedName{Namespace: mongoData.Namespace, Name: mongoData.RevisionsSecretName()}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("revision %d is not found, there is no revision history", revision)
+		}
+		return nil, err
+	}
+
+	if err := checkRevisionsOwner(secret, mongoData); err != nil {
+		return nil, err
+	}
+
+	value, ok := secret.Data[revisionKey(revision)]
+	if !ok {
+		return nil, fmt.Errorf("revision %d is not found in the revision history", revision)
+	}
+
+	rev := documentRevision{}
+	if err := json.Unmarshal(value, &rev); err != nil {
+		return nil, fmt.Errorf("could not decode revision %d: %v", revision, err)
+	}
+
+	var doc bson.D
+	if err := bson.UnmarshalExtJSON(rev.Document, true, &doc); err != nil {
+		return nil, fmt.Errorf("could not decode the document of revision %d: %v", revision, err)
+	}
+
+	return bson.Marshal(doc)
+}
+
+// checkRevisionsOwner makes sure the revisions secret is controlled by the MongoDBData,
+// a secret of the same name which belongs to something else is never read or overwritten
+func checkRevisionsOwner(secret *corev1.Secret, mongoData *mongov1.MongoDBData) error {
+	if !metav1.IsControlledBy(secret, mongoData) {
+		return fmt.Errorf("secret %s is not controlled by MongoDBData %s", secret.Name, mongoData.Name)
+	}
+	return nil
+}
+
+// pruneRevisions drops the oldest revisions from the secret beyond the history limit and while the
+// data of the secret exceeds maxSize, the latest revision is always kept. It returns the kept revisions
+func pruneRevisions(secret *corev1.Secret, revisions []documentRevision, limit, maxSize int) []documentRevision {
+	for len(revisions) > limit || (len(revisions) > 1 && secretDataSize(secret) > maxSize) {
+		delete(secret.Data, revisionKey(revisions[0].Revision))
+		revisions = revisions[1:]
+	}
+	return revisions
+}
+
+// secretDataSize returns the size of the keys and values of the secret data
+func secretDataSize(secret *corev1.Secret) int {
+	size := 0
+	for key, value := range secret.Data {
+		size += len(key) + len(value)
+	}
+	return size
+}
+
+// readRevisions returns the revisions of the secret ordered by their number
+func readRevisions(secret *corev1.Secret) ([]documentRevision, error) {
+	revisions := []documentRevision{}
+	for key, value := range secret.Data {
+
+		if !strings.HasPrefix(key, revisionKeyPrefix) {
+			continue
+		}
+
+		rev := documentRevision{}
+		if err := json.Unmarshal(value, &rev); err != nil {
+			return nil, fmt.Errorf("could not decode %s of secret %s: %v", key, secret.Name, err)
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+func revisionKey(revision int64) string {
+	return revisionKeyPrefix + strconv.FormatInt(revision, 10)
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+)
+
+func TestReadRevisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string][]byte
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "empty secret",
+			want: []int64{},
+		},
+		{
+			name: "ordered by number",
+			data: map[string][]byte{
+				revisionKey(10): []byte(`{"revision":10,"document":{}}`),
+				revisionKey(2):  []byte(`{"revision":2,"document":{}}`),
+				revisionKey(9):  []byte(`{"revision":9,"document":{}}`),
+			},
+			want: []int64{2, 9, 10},
+		},
+		{
+			name: "other keys are ignored",
+			data: map[string][]byte{
+				revisionKey(1): []byte(`{"revision":1,"document":{}}`),
+				"notes":        []byte(`not a revision`),
+			},
+			want: []int64{1},
+		},
+		{
+			name: "invalid revision",
+			data: map[string][]byte{
+				revisionKey(1): []byte(`{`),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "revisions"}, Data: tt.data}
+
+			revisions, err := readRevisions(secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readRevisions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []int64{}
+			for _, rev := range revisions {
+				got = append(got, rev.Revision)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("readRevisions() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("readRevisions() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCheckRevisionsOwner(t *testing.T) {
+	mongoData := &mongov1.MongoDBData{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "example", UID: "data-uid"},
+	}
+
+	owner := func(controller bool) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: mongov1.GroupVersion.String(),
+			Kind:       "MongoDBData",
+			Name:       "example",
+			UID:        mongoData.UID,
+			Controller: &controller,
+		}}
+	}
+	controller := true
+
+	tests := []struct {
+		name    string
+		owners  []metav1.OwnerReference
+		wantErr bool
+	}{
+		{
+			name:   "controlled by the MongoDBData",
+			owners: owner(true),
+		},
+		{
+			name:    "no owner",
+			wantErr: true,
+		},
+		{
+			name:    "owned but not controlled",
+			owners:  owner(false),
+			wantErr: true,
+		},
+		{
+			name: "controlled by another object",
+			owners: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "example",
+				UID:        "other-uid",
+				Controller: &controller,
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "sth",
+				Name:            mongoData.RevisionsSecretName(),
+				OwnerReferences: tt.owners,
+			}}
+
+			if err := checkRevisionsOwner(secret, mongoData); (err != nil) != tt.wantErr {
+				t.Errorf("checkRevisionsOwner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPruneRevisions(t *testing.T) {
+	// every revision takes 10 bytes of its key and 90 bytes of its value
+	value := bytes.Repeat([]byte("x"), 90)
+
+	tests := []struct {
+		name    string
+		count   int
+		limit   int
+		maxSize int
+		want    []int64
+	}{
+		{
+			name:    "within limit and size",
+			count:   3,
+			limit:   5,
+			maxSize: 1000,
+			want:    []int64{1, 2, 3},
+		},
+		{
+			name:    "beyond limit",
+			count:   3,
+			limit:   2,
+			maxSize: 1000,
+			want:    []int64{2, 3},
+		},
+		{
+			name:    "beyond size",
+			count:   3,
+			limit:   5,
+			maxSize: 250,
+			want:    []int64{2, 3},
+		},
+		{
+			name:    "latest revision is kept beyond size",
+			count:   3,
+			limit:   5,
+			maxSize: 50,
+			want:    []int64{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{Data: map[string][]byte{}}
+			revisions := []documentRevision{}
+			for i := 1; i <= tt.count; i++ {
+				secret.Data[revisionKey(int64(i))] = value
+				revisions = append(revisions, documentRevision{Revision: int64(i)})
+			}
+
+			got := []int64{}
+			for _, rev := range pruneRevisions(secret, revisions, tt.limit, tt.maxSize) {
+				got = append(got, rev.Revision)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pruneRevisions() = %v, want %v", got, tt.want)
+			}
+			if len(secret.Data) != len(tt.want) {
+				t.Errorf("pruneRevisions() left %d secret keys, want %d", len(secret.Data), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRecordRevision(t *testing.T) {
+	limit := int32(2)
+	mongoData := &mongov1.MongoDBData{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "example", UID: "data-uid"},
+		Spec: mongov1.MongoDBDataSpec{
+			RevisionHistoryLimit: &limit,
+			SecretFields: []mongov1.MongoDBSecretField{
+				{Path: "password", SecretKeyRef: mongov1.LocalSecretKeyReference{Name: "creds", Key: "password"}},
+			},
+		},
+	}
+
+	scheme := newTestScheme(t)
+	r := &MongoDBDataReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(mongoData).Build(),
+		Scheme: scheme,
+	}
+	ctx := context.Background()
+
+	documents := []bson.D{
+		{{Key: "name", Value: "a"}, {Key: "password", Value: "hunter2"}},
+		{{Key: "name", Value: "a"}, {Key: "password", Value: "hunter3"}},
+		{{Key: "name", Value: "b"}},
+		{{Key: "name", Value: "c"}},
+	}
+	for _, doc := range documents {
+		if err := r.recordRevision(ctx, mongoData, mustMarshal(t, doc)); err != nil {
+			t.Fatalf("recordRevision() error = %v", err)
+		}
+	}
+
+	// the secret field is left out, so the second document is the same revision
+	if mongoData.Status.Revision != 3 {
+		t.Errorf("Status.Revision = %d, want 3", mongoData.Status.Revision)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: "sth", Name: mongoData.RevisionsSecretName()}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(secret, mongoData) {
+		t.Errorf("revisions secret is not controlled by the MongoDBData")
+	}
+
+	revisions, err := readRevisions(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 2 || revisions[1].Revision != 3 {
+		t.Fatalf("readRevisions() = %v, want revisions 2 and 3", revisions)
+	}
+
+	got, err := r.revisionDocument(ctx, mongoData, 2)
+	if err != nil {
+		t.Fatalf("revisionDocument() error = %v", err)
+	}
+	if want := mustMarshal(t, bson.D{{Key: "name", Value: "b"}}); !bytes.Equal(got, want) {
+		t.Errorf("revisionDocument() = %v, want %v", bson.Raw(got), bson.Raw(want))
+	}
+
+	if _, err := r.revisionDocument(ctx, mongoData, 1); err == nil {
+		t.Errorf("revisionDocument() of a dropped revision error = nil, want an error")
+	}
+}
+
+func TestRecordRevisionUncontrolledSecret(t *testing.T) {
+	mongoData := &mongov1.MongoDBData{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "example", UID: "data-uid"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: mongoData.RevisionsSecretName()},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+
+	scheme := newTestScheme(t)
+	r := &MongoDBDataReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(mongoData, secret).Build(),
+		Scheme: scheme,
+	}
+
+	if err := r.recordRevision(context.Background(), mongoData, mustMarshal(t, bson.D{{Key: "name", Value: "a"}})); err == nil {
+		t.Fatalf("recordRevision() error = nil, want an error")
+	}
+
+	got := &corev1.Secret{}
+	if err := r.Client.Get(context.Background(), client.ObjectKeyFromObject(secret), got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Data) != 1 || string(got.Data["password"]) != "hunter2" {
+		t.Errorf("recordRevision() changed the secret data to %v", got.Data)
+	}
+}
+
+func TestRecordRevisionTooLarge(t *testing.T) {
+	mongoData := &mongov1.MongoDBData{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "example", UID: "data-uid"},
+	}
+
+	scheme := newTestScheme(t)
+	recorder := record.NewFakeRecorder(10)
+	r := &MongoDBDataReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(mongoData).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+	ctx := context.Background()
+
+	if err := r.recordRevision(ctx, mongoData, mustMarshal(t, bson.D{{Key: "name", Value: "a"}})); err != nil {
+		t.Fatalf("recordRevision() error = %v", err)
+	}
+
+	large := bson.D{{Key: "name", Value: strings.Repeat("a", corev1.MaxSecretSize)}}
+	if err := r.recordRevision(ctx, mongoData, mustMarshal(t, large)); err != nil {
+		t.Fatalf("recordRevision() error = %v", err)
+	}
+
+	if mongoData.Status.Revision != 1 {
+		t.Errorf("Status.Revision = %d, want 1", mongoData.Status.Revision)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("recordRevision() recorded %d events, want 1", len(recorder.Events))
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: "sth", Name: mongoData.RevisionsSecretName()}, secret); err != nil {
+		t.Fatal(err)
+	}
+	revisions, err := readRevisions(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 1 {
+		t.Errorf("readRevisions() = %v, want revision 1", revisions)
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"k8s.io/apimachinery/pkg/runtime"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/go-logr/logr"
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+	"github.com/pingcap/errors"
+)
+
+var (
+	mongoDBIndexFinalizerName = "mongo.snappcloud.io/mongodb-index-finalizer"
+)
+
+// MongoDBIndexReconciler reconciles a MongoDBIndex object
+type MongoDBIndexReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MongoClients is the shared mongodb client pool
+	MongoClients *mongodb.ClientManager
+}
+
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbindexes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbindexes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbindexes/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates the index of a MongoDBIndex, rebuilds it when the live index
+// differs from the spec and drops it when the MongoDBIndex is deleted
+func (r *MongoDBIndexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	log := r.Log.WithValues("mongodb-index", req.NamespacedName)
+	log.Info("Reconciling MongoDBIndex")
+
+	mongoIndex := &mongov1.MongoDBIndex{}
+	if err := r.Client.Get(ctx, req.NamespacedName, mongoIndex); err != nil {
+		if errors.IsNotFound(err) {
+			// don't requeue on deletions, which yield a non-found object
+			log.Info("ignoring", "reason", "not found", "err", err)
+		}
+		return requeue(client.IgnoreNotFound(err))
+	}
+
+	// Check if the MongoDBConfig exists
+	mongoCfg := &mongov1.MongoDBConfig{}
+	if err := r.Client.Get(ctx, k8sTypes.NamespacedName{Name: mongoIndex.Spec.DB}, mongoCfg); err != nil {
+
+		if errors.IsNotFound(err) {
+
+			message := fmt.Sprintf("MongoDBConfig with name %s doesn't exists", mongoIndex.Spec.DB)
+			if err := r.setEventStatusPending(ctx, mongoIndex, message); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			log.Info("ignoring", "reason", message)
+			return requeueWithDelay(20 * time.Second)
+		}
+
+		log.Error(err, fmt.Sprintf("failed to get the mongo-config %s", mongoIndex.Spec.DB))
+		return requeue(err)
+	}
+
+	// get the shared mongodb client of the MongoDBConfig
+	mongoClient, err := getMongoClient(ctx, r.Client, r.MongoClients, mongoCfg)
+	if err != nil {
+
+		if err := r.setEventStatusPending(ctx, mongoIndex, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	collection := mongoClient.Database(mongoIndex.Spec.Database).Collection(mongoIndex.Spec.Collection)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if mongoIndex.ObjectMeta.DeletionTimestamp.IsZero() {
+
+		// register our finalizer, so the index gets dropped on deletion
+		if controllerutil.AddFinalizer(mongoIndex, mongoDBIndexFinalizerName) {
+			if err := r.Client.Update(ctx, mongoIndex); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+	} else {
+
+		if controllerutil.ContainsFinalizer(mongoIndex, mongoDBIndexFinalizerName) {
+
+			// there is nothing to drop if the index is never created by this MongoDBIndex
+			if mongoIndex.Status.Name != "" && mongoIndex.Status.Created {
+				if err := mongodb.DropIndex(ctx, collection, mongoIndex.Status.Name); err != nil {
+					log.Error(err, "unable to drop the index from mongodb")
+					return requeue(err)
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(mongoIndex, mongoDBIndexFinalizerName)
+			if err := r.Client.Update(ctx, mongoIndex); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return doNotRequeue()
+	}
+
+	if err := mongoCfg.CheckIndex(mongoIndex); err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoIndex, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	spec, err := mongoIndex.IndexSpec()
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoIndex, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	// the name of the index has changed with its keys or spec.name,
+	// so the index of the previous name is dropped when it has been created here
+	if mongoIndex.Status.Name != "" && mongoIndex.Status.Name != spec.Name {
+
+		if mongoIndex.Status.Created {
+			if err := mongodb.DropIndex(ctx, collection, mongoIndex.Status.Name); err != nil {
+				log.Error(err, "unable to drop the previous index from mongodb")
+				return requeue(err)
+			}
+		}
+
+		mongoIndex.Status.Name = ""
+		mongoIndex.Status.Created = false
+	}
+
+	live, err := mongodb.FindIndex(ctx, collection, spec.Name)
+	if err != nil {
+		log.Error(err, "unable to list the indexes of the collection")
+		return requeue(err)
+	}
+
+	if live == nil {
+		return r.createIndex(ctx, log, collection, mongoIndex, mongoCfg, spec, "Index has been created")
+	}
+
+	diff, err := mongodb.IndexDiff(live, spec)
+	if err != nil {
+		log.Error(err, "unable to compare the index with its spec")
+		return requeue(err)
+	}
+
+	if len(diff) > 0 {
+
+		msg := fmt.Sprintf("Index differs from spec in %s", strings.Join(diff, ", "))
+
+		// an index which has not been created by this MongoDBIndex is never taken over
+		if !mongoIndex.Status.Created {
+
+			msg := fmt.Sprintf("Index %s already exists with different %s", spec.Name, strings.Join(diff, ", "))
+			if err := r.setEventStatusFailed(ctx, mongoIndex, msg); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			return requeueWithDelay(30 * time.Second)
+		}
+
+		if mongoIndex.Spec.RebuildPolicy == mongov1.IndexRebuildNever {
+
+			if mongoIndex.Status.State != string(mongov1.MongoDBIndexConditionDrifted) {
+				if err := r.setEventStatusDrifted(ctx, mongoIndex, msg); err != nil {
+					log.Error(err, "unable to update target's status object")
+					return requeue(err)
+				}
+			}
+
+			return r.resync(mongoCfg)
+		}
+
+		log.Info("rebuilding index", "reason", msg)
+
+		if err := mongodb.DropIndex(ctx, collection, spec.Name); err != nil {
+			log.Error(err, "unable to drop the index from mongodb")
+			return requeue(err)
+		}
+
+		return r.createIndex(ctx, log, collection, mongoIndex, mongoCfg, spec, msg+", it has been rebuilt")
+	}
+
+	if mongoIndex.Status.State != string(mongov1.MongoDBIndexConditionReady) || mongoIndex.Status.Name != spec.Name {
+		mongoIndex.Status.Name = spec.Name
+		if err := r.setEventStatusReady(ctx, mongoIndex, "Index matches spec"); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	return r.resync(mongoCfg)
+}
+
+// createIndex creates the index in mongodb and records its name
+func (r *MongoDBIndexReconciler) createIndex(
+	ctx context.Context,
+	log logr.Logger,
+	collection *mongo.Collection,
+	mongoIndex *mongov1.MongoDBIndex,
+	mongoCfg *mongov1.MongoDBConfig,
+	spec mongodb.IndexSpec,
+	msg string,
+) (ctrl.Result, error) {
+
+	if err := mongodb.CreateIndex(ctx, collection, spec); err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoIndex, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	mongoIndex.Status.Name = spec.Name
+	mongoIndex.Status.Created = true
+	if err := r.setEventStatusReady(ctx, mongoIndex, msg); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	return r.resync(mongoCfg)
+}
+
+// resync requeues the MongoDBIndex after the resync interval of the MongoDBConfig,
+// so indexes which are changed directly in mongodb are detected
+func (r *MongoDBIndexReconciler) resync(mongoCfg *mongov1.MongoDBConfig) (ctrl.Result, error) {
+	if mongoCfg.Spec.ResyncInterval == nil || mongoCfg.Spec.ResyncInterval.Duration <= 0 {
+		return doNotRequeue()
+	}
+	return requeueWithDelay(mongoCfg.Spec.ResyncInterval.Duration)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MongoDBIndexReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mongov1.MongoDBIndex{}).
+		Complete(r)
+}
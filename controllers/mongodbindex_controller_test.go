@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+)
+
+func TestMongoDBIndexDriftedStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		set         func(r *MongoDBIndexReconciler, mongoIndex *mongov1.MongoDBIndex) error
+		wantState   mongov1.MongoDBIndexConditionType
+		wantDrifted bool
+		wantEvent   string
+	}{
+		{
+			name: "drifted",
+			set: func(r *MongoDBIndexReconciler, mongoIndex *mongov1.MongoDBIndex) error {
+				return r.setEventStatusDrifted(context.Background(), mongoIndex, "drifted")
+			},
+			wantState:   mongov1.MongoDBIndexConditionDrifted,
+			wantDrifted: true,
+			wantEvent:   corev1.EventTypeWarning,
+		},
+		{
+			name: "ready after the drift",
+			set: func(r *MongoDBIndexReconciler, mongoIndex *mongov1.MongoDBIndex) error {
+				if err := r.setEventStatusDrifted(context.Background(), mongoIndex, "drifted"); err != nil {
+					return err
+				}
+				<-r.Recorder.(*record.FakeRecorder).Events
+				return r.setEventStatusReady(context.Background(), mongoIndex, "ready")
+			},
+			wantState: mongov1.MongoDBIndexConditionReady,
+			wantEvent: corev1.EventTypeNormal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoIndex := &mongov1.MongoDBIndex{ObjectMeta: metav1.ObjectMeta{Namespace: "sth", Name: "idx"}}
+
+			scheme := newTestScheme(t)
+			recorder := record.NewFakeRecorder(10)
+			r := &MongoDBIndexReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(mongoIndex).Build(),
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+
+			if err := tt.set(r, mongoIndex); err != nil {
+				t.Fatalf("set status error = %v", err)
+			}
+
+			if mongoIndex.Status.State != string(tt.wantState) {
+				t.Errorf("State = %s, want %s", mongoIndex.Status.State, tt.wantState)
+			}
+			if got := apimeta.IsStatusConditionTrue(mongoIndex.Status.Conditions, string(mongov1.MongoDBIndexConditionDrifted)); got != tt.wantDrifted {
+				t.Errorf("Drifted = %v, want %v", got, tt.wantDrifted)
+			}
+			if event := <-recorder.Events; !strings.HasPrefix(event, tt.wantEvent+" ") {
+				t.Errorf("event = %s, want a %s event", event, tt.wantEvent)
+			}
+		})
+	}
+}
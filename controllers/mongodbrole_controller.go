@@ -0,0 +1,209 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/go-logr/logr"
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+	"github.com/pingcap/errors"
+)
+
+var (
+	mongoDBRoleFinalizerName = "mongo.snappcloud.io/mongodb-role-finalizer"
+)
+
+// MongoDBRoleReconciler reconciles a MongoDBRole object
+type MongoDBRoleReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MongoClients is the shared mongodb client pool
+	MongoClients *mongodb.ClientManager
+}
+
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates the role of a MongoDBRole with createRole, keeps its privileges
+// and inherited roles up to date with updateRole and drops it on deletion
+func (r *MongoDBRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	log := r.Log.WithValues("mongodb-role", req.NamespacedName)
+	log.Info("Reconciling MongoDBRole")
+
+	mongoRole := &mongov1.MongoDBRole{}
+	if err := r.Client.Get(ctx, req.NamespacedName, mongoRole); err != nil {
+		if errors.IsNotFound(err) {
+			// don't requeue on deletions, which yield a non-found object
+			log.Info("ignoring", "reason", "not found", "err", err)
+		}
+		return requeue(client.IgnoreNotFound(err))
+	}
+
+	// Check if the MongoDBConfig exists
+	mongoCfg := &mongov1.MongoDBConfig{}
+	if err := r.Client.Get(ctx, k8sTypes.NamespacedName{Name: mongoRole.Spec.DB}, mongoCfg); err != nil {
+
+		if errors.IsNotFound(err) {
+
+			message := fmt.Sprintf("MongoDBConfig with name %s doesn't exists", mongoRole.Spec.DB)
+			if err := r.setEventStatusPending(ctx, mongoRole, message); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			log.Info("ignoring", "reason", message)
+			return requeueWithDelay(20 * time.Second)
+		}
+
+		log.Error(err, fmt.Sprintf("failed to get the mongo-config %s", mongoRole.Spec.DB))
+		return requeue(err)
+	}
+
+	// get the shared mongodb client of the MongoDBConfig
+	mongoClient, err := getMongoClient(ctx, r.Client, r.MongoClients, mongoCfg)
+	if err != nil {
+
+		if err := r.setEventStatusPending(ctx, mongoRole, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	database := mongoClient.Database(mongoRole.Spec.Database)
+	roleName := mongoRole.MongoRoleName()
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if mongoRole.ObjectMeta.DeletionTimestamp.IsZero() {
+
+		// register our finalizer, so the role gets dropped on deletion
+		if controllerutil.AddFinalizer(mongoRole, mongoDBRoleFinalizerName) {
+			if err := r.Client.Update(ctx, mongoRole); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+	} else {
+
+		if controllerutil.ContainsFinalizer(mongoRole, mongoDBRoleFinalizerName) {
+
+			// only the role created by this MongoDBRole is dropped
+			if mongoRole.Status.Created {
+				if err := mongodb.DropRole(ctx, database, roleName); err != nil {
+					log.Error(err, "unable to drop the role from mongodb")
+					return requeue(err)
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(mongoRole, mongoDBRoleFinalizerName)
+			if err := r.Client.Update(ctx, mongoRole); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return doNotRequeue()
+	}
+
+	if err := mongoCfg.CheckRole(mongoRole); err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoRole, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	exists, err := mongodb.RoleExists(ctx, database, roleName)
+	if err != nil {
+		log.Error(err, "unable to get the role from mongodb")
+		return requeue(err)
+	}
+
+	// an existing role which has not been created by this MongoDBRole is never taken over
+	if exists && !mongoRole.Status.Created {
+
+		msg := fmt.Sprintf("Role %s already exists in database %s and is not managed by this MongoDBRole", roleName, mongoRole.Spec.Database)
+		if err := r.setEventStatusFailed(ctx, mongoRole, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	msg := "Role has been updated"
+	if exists {
+		err = mongodb.UpdateRole(ctx, database, roleName, mongoRole.MongoPrivileges(), mongoRole.MongoRoles())
+	} else {
+		err = mongodb.CreateRole(ctx, database, roleName, mongoRole.MongoPrivileges(), mongoRole.MongoRoles())
+		msg = "Role has been created"
+	}
+
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoRole, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	ready := mongoRole.Status.State == string(mongov1.MongoDBRoleConditionReady) &&
+		isConditionObserved(mongoRole.Status.Conditions, string(mongov1.MongoDBRoleConditionReady), mongoRole.Generation)
+
+	if !exists || !ready {
+		mongoRole.Status.Created = true
+		if err := r.setEventStatusReady(ctx, mongoRole, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+	}
+
+	return doNotRequeue()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MongoDBRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mongov1.MongoDBRole{}).
+		Complete(r)
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	// nolint
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/go-logr/logr"
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+	"github.com/pingcap/errors"
+)
+
+const (
+	// mongoDBUserPasswordKey is the key of the generated password in the user secret
+	mongoDBUserPasswordKey = corev1.BasicAuthPasswordKey
+)
+
+var (
+	mongoDBUserFinalizerName = "mongo.snappcloud.io/mongodb-user-finalizer"
+
+	// errSecretNotControlled is returned when the credentials secret belongs to something else
+	errSecretNotControlled = fmt.Errorf("secret is not controlled by the MongoDBUser")
+)
+
+// MongoDBUserReconciler reconciles a MongoDBUser object
+type MongoDBUserReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MongoClients is the shared mongodb client pool
+	MongoClients *mongodb.ClientManager
+}
+
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbusers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbusers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mongo.snappcloud.io,resources=mongodbusers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+
+// Reconcile creates the user of a MongoDBUser with a generated password, keeps its
+// roles and password up to date with updateUser and drops it on deletion
+func (r *MongoDBUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	log := r.Log.WithValues("mongodb-user", req.NamespacedName)
+	log.Info("Reconciling MongoDBUser")
+
+	mongoUser := &mongov1.MongoDBUser{}
+	if err := r.Client.Get(ctx, req.NamespacedName, mongoUser); err != nil {
+		if errors.IsNotFound(err) {
+			// don't requeue on deletions, which yield a non-found object
+			log.Info("ignoring", "reason", "not found", "err", err)
+		}
+		return requeue(client.IgnoreNotFound(err))
+	}
+
+	// Check if the MongoDBConfig exists
+	mongoCfg := &mongov1.MongoDBConfig{}
+	if err := r.Client.Get(ctx, k8sTypes.NamespacedName{Name: mongoUser.Spec.DB}, mongoCfg); err != nil {
+
+		if errors.IsNotFound(err) {
+
+			message := fmt.Sprintf("MongoDBConfig with name %s doesn't exists", mongoUser.Spec.DB)
+			if err := r.setEventStatusPending(ctx, mongoUser, message); err != nil {
+				log.Error(err, "unable to update target's status object")
+				return requeue(err)
+			}
+
+			log.Info("ignoring", "reason", message)
+			return requeueWithDelay(20 * time.Second)
+		}
+
+		log.Error(err, fmt.Sprintf("failed to get the mongo-config %s", mongoUser.Spec.DB))
+		return requeue(err)
+	}
+
+	// get the shared mongodb client of the MongoDBConfig
+	mongoClient, err := getMongoClient(ctx, r.Client, r.MongoClients, mongoCfg)
+	if err != nil {
+
+		if err := r.setEventStatusPending(ctx, mongoUser, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	database := mongoClient.Database(mongoUser.Spec.Database)
+	username := mongoUser.MongoUsername()
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if mongoUser.ObjectMeta.DeletionTimestamp.IsZero() {
+
+		// register our finalizer, so the user gets dropped on deletion
+		if controllerutil.AddFinalizer(mongoUser, mongoDBUserFinalizerName) {
+			if err := r.Client.Update(ctx, mongoUser); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+	} else {
+
+		if controllerutil.ContainsFinalizer(mongoUser, mongoDBUserFinalizerName) {
+
+			info, err := mongodb.GetUser(ctx, database, username)
+			if err != nil {
+				log.Error(err, "unable to get the user from mongodb")
+				return requeue(err)
+			}
+
+			// only the user created by this MongoDBUser is dropped,
+			// the secret is garbage collected with its owner
+			if info != nil && info.Owner() == string(mongoUser.UID) {
+				if err := mongodb.DropUser(ctx, database, username); err != nil {
+					log.Error(err, "unable to drop the user from mongodb")
+					return requeue(err)
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(mongoUser, mongoDBUserFinalizerName)
+			if err := r.Client.Update(ctx, mongoUser); err != nil {
+				log.Error(err, "unable to update target")
+				return requeue(err)
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return doNotRequeue()
+	}
+
+	if err := mongoCfg.CheckUser(mongoUser); err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoUser, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return doNotRequeue()
+	}
+
+	info, err := mongodb.GetUser(ctx, database, username)
+	if err != nil {
+		log.Error(err, "unable to get the user from mongodb")
+		return requeue(err)
+	}
+
+	// an existing user which has not been created by this MongoDBUser is never taken over
+	if info != nil && info.Owner() != string(mongoUser.UID) {
+
+		msg := fmt.Sprintf("User %s already exists in database %s and is not managed by this MongoDBUser", username, mongoUser.Spec.Database)
+		if err := r.setEventStatusFailed(ctx, mongoUser, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	secret, err := r.ensureSecret(ctx, mongoUser)
+	if err == errSecretNotControlled {
+
+		msg := fmt.Sprintf("Secret %s already exists and is not controlled by this MongoDBUser", mongoUser.CredentialsSecretName())
+		if err := r.setEventStatusFailed(ctx, mongoUser, msg); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+	if err != nil {
+		log.Error(err, "unable to get or create the user secret")
+		return requeue(err)
+	}
+
+	var (
+		password = string(secret.Data[mongoDBUserPasswordKey])
+		roles    = mongoUser.MongoRoles()
+		msg      string
+	)
+
+	switch {
+	case info == nil:
+		err = mongodb.CreateUser(ctx, database, username, password, roles, string(mongoUser.UID))
+		msg = "User has been created"
+	case mongoUser.Status.PasswordVersion != secret.ResourceVersion:
+		// the password in the secret has been changed
+		err = mongodb.UpdateUser(ctx, database, username, password, roles)
+		msg = "User has been updated with the password of the secret"
+	case !sameRoles(info.Roles, roles):
+		err = mongodb.UpdateUser(ctx, database, username, "", roles)
+		msg = "User roles have been updated"
+	}
+
+	if err != nil {
+
+		if err := r.setEventStatusFailed(ctx, mongoUser, err.Error()); err != nil {
+			log.Error(err, "unable to update target's status object")
+			return requeue(err)
+		}
+
+		return requeueWithDelay(30 * time.Second)
+	}
+
+	if msg == "" && mongoUser.Status.State == string(mongov1.MongoDBUserConditionReady) {
+		return doNotRequeue()
+	}
+
+	// read back the roles which mongodb has actually granted
+	if info, err = mongodb.GetUser(ctx, database, username); err != nil {
+		log.Error(err, "unable to get the user from mongodb")
+		return requeue(err)
+	}
+
+	mongoUser.Status.SecretName = secret.Name
+	mongoUser.Status.PasswordVersion = secret.ResourceVersion
+	mongoUser.Status.Roles = []mongov1.RoleReference{}
+	if info != nil {
+		for _, role := range info.Roles {
+			mongoUser.Status.Roles = append(mongoUser.Status.Roles, mongov1.RoleReference{Role: role.Role, Database: role.DB})
+		}
+	}
+
+	if msg == "" {
+		msg = "User matches spec"
+	}
+
+	if err := r.setEventStatusReady(ctx, mongoUser, msg); err != nil {
+		log.Error(err, "unable to update target's status object")
+		return requeue(err)
+	}
+
+	return doNotRequeue()
+}
+
+// ensureSecret returns the secret holding the credentials of the user, the secret
+// is created with a generated password and owned by the MongoDBUser if it doesn't exist,
+// an existing secret which is not controlled by the MongoDBUser is never used or overwritten
+func (r *MongoDBUserReconciler) ensureSecret(ctx context.Context, mongoUser *mongov1.MongoDBUser) (*corev1.Secret, error) {
+
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, k8sTypes.NamespacedName{Namespace: mongoUser.Namespace, Name: mongoUser.CredentialsSecretName()}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(secret, mongoUser) {
+		return nil, errSecretNotControlled
+	}
+	if exists && len(secret.Data[mongoDBUserPasswordKey]) > 0 {
+		return secret, nil
+	}
+
+	length := mongoUser.Spec.PasswordLength
+	if length == 0 {
+		length = 32
+	}
+
+	password, err := mongodb.GeneratePassword(length)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: mongoUser.Namespace,
+				Name:      mongoUser.CredentialsSecretName(),
+			},
+			Type: corev1.SecretTypeBasicAuth,
+		}
+
+		if err := controllerutil.SetControllerReference(mongoUser, secret, r.Scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[corev1.BasicAuthUsernameKey] = []byte(mongoUser.MongoUsername())
+	secret.Data[mongoDBUserPasswordKey] = []byte(password)
+	secret.Data["database"] = []byte(mongoUser.Spec.Database)
+
+	if exists {
+		err = r.Client.Update(ctx, secret)
+	} else {
+		err = r.Client.Create(ctx, secret)
+	}
+
+	return secret, err
+}
+
+// sameRoles reports whether both lists have the same roles in any order
+func sameRoles(a, b []mongodb.RoleRef) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	roles := map[mongodb.RoleRef]int{}
+	for _, role := range a {
+		roles[role]++
+	}
+
+	for _, role := range b {
+		if roles[role] == 0 {
+			return false
+		}
+		roles[role]--
+	}
+
+	return true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MongoDBUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mongov1.MongoDBUser{}).
+		// reconcile the user when its password is changed in the secret
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
+)
+
+// newTestScheme returns a scheme with the kubernetes and the operator types
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := mongov1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	return scheme
+}
+
+func TestSameRoles(t *testing.T) {
+	var (
+		read  = mongodb.RoleRef{Role: "read", DB: "app"}
+		write = mongodb.RoleRef{Role: "readWrite", DB: "app"}
+	)
+
+	tests := []struct {
+		name string
+		a, b []mongodb.RoleRef
+		want bool
+	}{
+		{
+			name: "no roles",
+			want: true,
+		},
+		{
+			name: "same roles in another order",
+			a:    []mongodb.RoleRef{read, write},
+			b:    []mongodb.RoleRef{write, read},
+			want: true,
+		},
+		{
+			name: "role removed",
+			a:    []mongodb.RoleRef{read, write},
+			b:    []mongodb.RoleRef{read},
+			want: false,
+		},
+		{
+			name: "duplicate roles",
+			a:    []mongodb.RoleRef{read, read},
+			b:    []mongodb.RoleRef{read, write},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameRoles(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureSecret(t *testing.T) {
+	mongoUser := &mongov1.MongoDBUser{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "app", UID: "uid"},
+		Spec:       mongov1.MongoDBUserSpec{Database: "team-a", PasswordLength: 20},
+	}
+
+	controller := true
+	controlled := []metav1.OwnerReference{{
+		APIVersion: mongov1.GroupVersion.String(),
+		Kind:       "MongoDBUser",
+		Name:       "app",
+		UID:        "uid",
+		Controller: &controller,
+	}}
+
+	tests := []struct {
+		name         string
+		objs         []runtime.Object
+		wantPassword string
+		wantErr      error
+	}{
+		{
+			name: "secret is created",
+		},
+		{
+			name: "password of the controlled secret is kept",
+			objs: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "app-mongodb-user", OwnerReferences: controlled},
+				Data:       map[string][]byte{mongoDBUserPasswordKey: []byte("hunter2")},
+			}},
+			wantPassword: "hunter2",
+		},
+		{
+			name: "secret which is not controlled is not used",
+			objs: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "app-mongodb-user"},
+				Data:       map[string][]byte{mongoDBUserPasswordKey: []byte("hunter2")},
+			}},
+			wantPassword: "hunter2",
+			wantErr:      errSecretNotControlled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newTestScheme(t)
+			r := &MongoDBUserReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objs...).Build(),
+				Scheme: scheme,
+			}
+
+			secret, err := r.ensureSecret(context.Background(), mongoUser)
+			if err != tt.wantErr {
+				t.Fatalf("ensureSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			key := client.ObjectKey{Namespace: "team-a", Name: mongoUser.CredentialsSecretName()}
+			stored := &corev1.Secret{}
+			if err := r.Client.Get(context.Background(), key, stored); err != nil {
+				t.Fatalf("secret is not stored: %v", err)
+			}
+
+			if tt.wantErr != nil {
+				if string(stored.Data[mongoDBUserPasswordKey]) != tt.wantPassword {
+					t.Errorf("stored password = %s, want %s", stored.Data[mongoDBUserPasswordKey], tt.wantPassword)
+				}
+				return
+			}
+
+			password := string(secret.Data[mongoDBUserPasswordKey])
+			if tt.wantPassword != "" && password != tt.wantPassword {
+				t.Errorf("ensureSecret() password = %s, want %s", password, tt.wantPassword)
+			}
+			if tt.wantPassword == "" && len(password) != 20 {
+				t.Errorf("ensureSecret() password length = %d, want 20", len(password))
+			}
+			if !metav1.IsControlledBy(stored, mongoUser) {
+				t.Errorf("stored secret is not controlled by the MongoDBUser")
+			}
+			if string(stored.Data[mongoDBUserPasswordKey]) != password {
+				t.Errorf("stored password = %s, want %s", stored.Data[mongoDBUserPasswordKey], password)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 
 	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -61,6 +62,104 @@ func (r *MongoDBConfigReconciler) setEventStatusError(ctx context.Context, adapt
 	)
 }
 
+func (r *MongoDBConfigReconciler) setEventStatusSecretError(ctx context.Context, adapter *mongov1.MongoDBConfig, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.SecretError,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBConfigReconciler) setEventStatusAuthError(ctx context.Context, adapter *mongov1.MongoDBConfig, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.AuthError,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBConfigReconciler) setEventStatusHandshakeError(ctx context.Context, adapter *mongov1.MongoDBConfig, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.HandshakeError,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+// setEventCondition records an event and sets the given condition
+// without changing the readiness of the MongoDBConfig
+func (r *MongoDBConfigReconciler) setEventCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBConfig,
+	reason mongov1.MongoDBConfigConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	eventType := corev1.EventTypeNormal
+	if status == metav1.ConditionTrue {
+		eventType = corev1.EventTypeWarning
+	}
+
+	r.Recorder.Event(adapter, eventType, string(reason), message)
+
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBConfigReconciler) setEventSuspended(ctx context.Context, adapter *mongov1.MongoDBConfig, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.Suspended,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBConfigReconciler) setEventResumed(ctx context.Context, adapter *mongov1.MongoDBConfig, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.Suspended,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBConfigReconciler) setEventEncryptionUnsupported(ctx context.Context, adapter *mongov1.MongoDBConfig, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.EncryptionUnsupported,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBConfigReconciler) setEventEncryptionSupported(ctx context.Context, adapter *mongov1.MongoDBConfig, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.EncryptionUnsupported,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
 func (r *MongoDBDataReconciler) setEventStatusPending(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
 	return r.setEventStatusCondition(
 		ctx,
@@ -91,6 +190,46 @@ func (r *MongoDBDataReconciler) setEventStatusFailed(ctx context.Context, adapte
 	)
 }
 
+// setEventStatusConflict reports a key conflict, the Conflict condition is true
+// like for a version conflict but the state changes as the document isn't written
+func (r *MongoDBDataReconciler) setEventStatusConflict(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	adapter.Status.State = string(mongov1.MongoDBDataConditionConflict)
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionConflict,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+// setEventDeletionHeld reports why the finalizer of a deleted MongoDBData is held,
+// the event is only recorded once for the same message
+func (r *MongoDBDataReconciler) setEventDeletionHeld(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	cond := apimeta.FindStatusCondition(adapter.Status.Conditions, string(mongov1.MongoDBDataConditionDeleting))
+	if cond != nil && cond.Message == msg {
+		return nil
+	}
+
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionDeleting,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBDataReconciler) setEventStatusAdoptionFailed(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionAdoptionFailed,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
 func (r *MongoDBDataReconciler) setEventStatusCondition(
 	ctx context.Context,
 	adapter *mongov1.MongoDBData,
@@ -117,3 +256,474 @@ func (r *MongoDBDataReconciler) setEventStatusCondition(
 
 	return r.Client.Status().Update(ctx, adapter)
 }
+
+// setEventCondition records an event and sets the given condition
+// without changing the state of the MongoDBData
+func (r *MongoDBDataReconciler) setEventCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBData,
+	reason mongov1.MongoDBDataConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	eventType := corev1.EventTypeNormal
+	if status == metav1.ConditionTrue {
+		eventType = corev1.EventTypeWarning
+	}
+
+	r.Recorder.Event(adapter, eventType, string(reason), message)
+
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBDataReconciler) setEventDrifted(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionDrifted,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBDataReconciler) setEventInSync(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionDrifted,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBDataReconciler) setEventVersionConflict(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionConflict,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBDataReconciler) setEventVersionResolved(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionConflict,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+// setEventPlannedWrite records an event and the status of a write which is not executed in dry-run mode
+func (r *MongoDBDataReconciler) setEventPlannedWrite(
+	ctx context.Context,
+	adapter *mongov1.MongoDBData,
+	write *mongov1.MongoDBDataWrite,
+) error {
+
+	message := fmt.Sprintf("Dry run: %s", write.Operation)
+	if write.Filter != "" {
+		message += fmt.Sprintf(" filter %s", write.Filter)
+	}
+	if write.Document != "" {
+		message += fmt.Sprintf(" document %s", write.Document)
+	}
+
+	r.Recorder.Event(adapter, corev1.EventTypeNormal, "DryRun", message)
+
+	adapter.Status.PlannedWrite = write
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBDataReconciler) setEventSuspended(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionSuspended,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBDataReconciler) setEventResumed(ctx context.Context, adapter *mongov1.MongoDBData, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBDataConditionSuspended,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBIndexReconciler) setEventStatusCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBIndex,
+	reason mongov1.MongoDBIndexConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	// a drift is reported by a true condition but it is still a warning
+	eventType := corev1.EventTypeWarning
+	if status == metav1.ConditionTrue && reason != mongov1.MongoDBIndexConditionDrifted {
+		eventType = corev1.EventTypeNormal
+	}
+
+	r.Recorder.Event(adapter, eventType, string(status), message)
+
+	adapter.Status.State = string(reason)
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBIndexReconciler) setEventStatusPending(ctx context.Context, adapter *mongov1.MongoDBIndex, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBIndexConditionPending,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBIndexReconciler) setEventStatusReady(ctx context.Context, adapter *mongov1.MongoDBIndex, msg string) error {
+	apimeta.RemoveStatusCondition(&adapter.Status.Conditions, string(mongov1.MongoDBIndexConditionDrifted))
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBIndexConditionReady,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBIndexReconciler) setEventStatusFailed(ctx context.Context, adapter *mongov1.MongoDBIndex, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBIndexConditionFailed,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBIndexReconciler) setEventStatusDrifted(ctx context.Context, adapter *mongov1.MongoDBIndex, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBIndexConditionDrifted,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBCollectionReconciler) setEventStatusCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBCollection,
+	reason mongov1.MongoDBCollectionConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	eventType := corev1.EventTypeWarning
+	if status == metav1.ConditionTrue {
+		eventType = corev1.EventTypeNormal
+	}
+
+	r.Recorder.Event(adapter, eventType, string(status), message)
+
+	adapter.Status.State = string(reason)
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBCollectionReconciler) setEventStatusPending(ctx context.Context, adapter *mongov1.MongoDBCollection, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBCollectionConditionPending,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBCollectionReconciler) setEventStatusReady(ctx context.Context, adapter *mongov1.MongoDBCollection, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBCollectionConditionReady,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBCollectionReconciler) setEventStatusFailed(ctx context.Context, adapter *mongov1.MongoDBCollection, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBCollectionConditionFailed,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+// setEventCondition records an event and sets the given condition
+// without changing the state of the MongoDBCollection
+func (r *MongoDBCollectionReconciler) setEventCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBCollection,
+	reason mongov1.MongoDBCollectionConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	eventType := corev1.EventTypeNormal
+	if status == metav1.ConditionTrue {
+		eventType = corev1.EventTypeWarning
+	}
+
+	r.Recorder.Event(adapter, eventType, string(reason), message)
+
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBCollectionReconciler) setEventImmutableDrift(ctx context.Context, adapter *mongov1.MongoDBCollection, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBCollectionConditionImmutableDrift,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBCollectionReconciler) setEventImmutableInSync(ctx context.Context, adapter *mongov1.MongoDBCollection, msg string) error {
+	return r.setEventCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBCollectionConditionImmutableDrift,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+// isConditionObserved reports whether the condition is true for the given generation
+func isConditionObserved(conditions []metav1.Condition, conditionType string, generation int64) bool {
+	cond := apimeta.FindStatusCondition(conditions, conditionType)
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == generation
+}
+
+func (r *MongoDBRoleReconciler) setEventStatusCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBRole,
+	reason mongov1.MongoDBRoleConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	eventType := corev1.EventTypeWarning
+	if status == metav1.ConditionTrue {
+		eventType = corev1.EventTypeNormal
+	}
+
+	r.Recorder.Event(adapter, eventType, string(status), message)
+
+	adapter.Status.State = string(reason)
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBRoleReconciler) setEventStatusPending(ctx context.Context, adapter *mongov1.MongoDBRole, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBRoleConditionPending,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBRoleReconciler) setEventStatusReady(ctx context.Context, adapter *mongov1.MongoDBRole, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBRoleConditionReady,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBRoleReconciler) setEventStatusFailed(ctx context.Context, adapter *mongov1.MongoDBRole, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBRoleConditionFailed,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBUserReconciler) setEventStatusCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBUser,
+	reason mongov1.MongoDBUserConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	eventType := corev1.EventTypeWarning
+	if status == metav1.ConditionTrue {
+		eventType = corev1.EventTypeNormal
+	}
+
+	r.Recorder.Event(adapter, eventType, string(status), message)
+
+	adapter.Status.State = string(reason)
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBUserReconciler) setEventStatusPending(ctx context.Context, adapter *mongov1.MongoDBUser, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBUserConditionPending,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBUserReconciler) setEventStatusReady(ctx context.Context, adapter *mongov1.MongoDBUser, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBUserConditionReady,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBUserReconciler) setEventStatusFailed(ctx context.Context, adapter *mongov1.MongoDBUser, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBUserConditionFailed,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBBulkDataReconciler) setEventStatusCondition(
+	ctx context.Context,
+	adapter *mongov1.MongoDBBulkData,
+	reason mongov1.MongoDBBulkDataConditionType,
+	status metav1.ConditionStatus,
+	message string,
+) error {
+
+	eventType := corev1.EventTypeWarning
+	if status == metav1.ConditionTrue {
+		eventType = corev1.EventTypeNormal
+	}
+
+	r.Recorder.Event(adapter, eventType, string(status), message)
+
+	adapter.Status.State = string(reason)
+	apimeta.SetStatusCondition(&adapter.Status.Conditions, metav1.Condition{
+		Type:               string(reason),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: adapter.Generation,
+	})
+
+	return r.Client.Status().Update(ctx, adapter)
+}
+
+func (r *MongoDBBulkDataReconciler) setEventStatusPending(ctx context.Context, adapter *mongov1.MongoDBBulkData, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBBulkDataConditionPending,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBBulkDataReconciler) setEventStatusApplied(ctx context.Context, adapter *mongov1.MongoDBBulkData, msg string) error {
+	apimeta.RemoveStatusCondition(&adapter.Status.Conditions, string(mongov1.MongoDBBulkDataConditionPartiallyApplied))
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBBulkDataConditionApplied,
+		metav1.ConditionTrue,
+		msg,
+	)
+}
+
+func (r *MongoDBBulkDataReconciler) setEventStatusPartiallyApplied(ctx context.Context, adapter *mongov1.MongoDBBulkData, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBBulkDataConditionPartiallyApplied,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
+
+func (r *MongoDBBulkDataReconciler) setEventStatusFailed(ctx context.Context, adapter *mongov1.MongoDBBulkData, msg string) error {
+	return r.setEventStatusCondition(
+		ctx,
+		adapter,
+		mongov1.MongoDBBulkDataConditionFailed,
+		metav1.ConditionFalse,
+		msg,
+	)
+}
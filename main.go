@@ -34,6 +34,7 @@ import (
 
 	mongov1 "github.com/mrjosh/mongodb-data-operator/api/v1"
 	"github.com/mrjosh/mongodb-data-operator/controllers"
+	"github.com/mrjosh/mongodb-data-operator/pkg/mongodb"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -54,6 +55,7 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var dataDryRun bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -66,6 +68,14 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.",
 	)
 
+	flag.BoolVar(
+		&dataDryRun,
+		"data-dry-run",
+		false,
+		"Compute the writes of the MongoDBData documents and report them in their status and events "+
+			"without executing them. Indexes, collections, users, roles and bulk data are still written.",
+	)
+
 	opts := zap.Options{
 		Development: true,
 	}
@@ -99,24 +109,93 @@ func main() {
 		os.Exit(1)
 	}
 
+	// shared mongodb clients, disconnected when the manager stops
+	mongoClients := mongodb.NewClientManager()
+	if err := mgr.Add(mongoClients); err != nil {
+		setupLog.Error(err, "unable to add mongodb client manager")
+		os.Exit(1)
+	}
+
+	// change streams of the MongoDBConfigs, stopped when the manager stops
+	changeStreams := mongodb.NewChangeStreamManager(ctrl.Log.WithName("changestreams"))
+	if err := mgr.Add(changeStreams); err != nil {
+		setupLog.Error(err, "unable to add change stream manager")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.MongoDBConfigReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("MongoDBConfig"),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("mongodb-config-controller"),
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("MongoDBConfig"),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("mongodb-config-controller"),
+		MongoClients:  mongoClients,
+		ChangeStreams: changeStreams,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MongoDBConfig")
 		os.Exit(1)
 	}
 	if err = (&controllers.MongoDBDataReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("MongoDBData"),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("mongodb-config-controller"),
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("MongoDBData"),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("mongodb-config-controller"),
+		MongoClients:  mongoClients,
+		ChangeStreams: changeStreams,
+		DryRun:        dataDryRun,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MongoDBData")
 		os.Exit(1)
 	}
+	if err = (&controllers.MongoDBIndexReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("MongoDBIndex"),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("mongodb-index-controller"),
+		MongoClients: mongoClients,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MongoDBIndex")
+		os.Exit(1)
+	}
+	if err = (&controllers.MongoDBCollectionReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("MongoDBCollection"),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("mongodb-collection-controller"),
+		MongoClients: mongoClients,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MongoDBCollection")
+		os.Exit(1)
+	}
+	if err = (&controllers.MongoDBRoleReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("MongoDBRole"),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("mongodb-role-controller"),
+		MongoClients: mongoClients,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MongoDBRole")
+		os.Exit(1)
+	}
+	if err = (&controllers.MongoDBUserReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("MongoDBUser"),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("mongodb-user-controller"),
+		MongoClients: mongoClients,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MongoDBUser")
+		os.Exit(1)
+	}
+	if err = (&controllers.MongoDBBulkDataReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("MongoDBBulkData"),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("mongodb-bulkdata-controller"),
+		MongoClients: mongoClients,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MongoDBBulkData")
+		os.Exit(1)
+	}
 	if err = (&mongov1.MongoDBData{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "MongoDBData")
 		os.Exit(1)
@@ -125,6 +204,26 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "MongoDBConfig")
 		os.Exit(1)
 	}
+	if err = (&mongov1.MongoDBIndex{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MongoDBIndex")
+		os.Exit(1)
+	}
+	if err = (&mongov1.MongoDBCollection{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MongoDBCollection")
+		os.Exit(1)
+	}
+	if err = (&mongov1.MongoDBRole{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MongoDBRole")
+		os.Exit(1)
+	}
+	if err = (&mongov1.MongoDBUser{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MongoDBUser")
+		os.Exit(1)
+	}
+	if err = (&mongov1.MongoDBBulkData{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MongoDBBulkData")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	// metrics ports
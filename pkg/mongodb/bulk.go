@@ -0,0 +1,208 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkDocument is a document which is upserted by its key filter
+type BulkDocument struct {
+	// Index is the 1-based position of the document in its source
+	Index int
+
+	Filter   bson.D
+	Document bson.D
+}
+
+// Key returns the key filter of the document as a comparable string,
+// integers of any bson type have the same key as they match the same documents
+func (d BulkDocument) Key() (string, error) {
+	key, err := bson.MarshalExtJSON(d.Filter, false, false)
+	return string(key), err
+}
+
+// BulkResult counts the documents of a bulk upsert
+type BulkResult struct {
+	Inserted int64
+	Updated  int64
+	Failed   int64
+
+	// Errors are the messages of the documents which could not be written
+	Errors []string
+}
+
+// ParseDocuments splits a json array of objects or newline delimited
+// json objects into the raw json of each document
+func ParseDocuments(data []byte) ([][]byte, error) {
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var docs []json.RawMessage
+		if err := json.Unmarshal(data, &docs); err != nil {
+			return nil, fmt.Errorf("could not decode documents: %v", err)
+		}
+
+		raws := make([][]byte, 0, len(docs))
+		for _, doc := range docs {
+			raws = append(raws, doc)
+		}
+		return raws, nil
+	}
+
+	raws := [][]byte{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc json.RawMessage
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return raws, nil
+			}
+			return nil, fmt.Errorf("could not decode document %d: %v", len(raws)+1, err)
+		}
+		raws = append(raws, doc)
+	}
+}
+
+// BulkUpsert sets the fields of the documents which match the filters and inserts the missing ones,
+// the other fields of the documents are left alone and the writes are unordered, so a failed
+// document doesn't stop the others. Only the documents without an owner or of the given owner are
+// written, a document whose key matches the document of another owner fails
+func BulkUpsert(ctx context.Context, collection *mongo.Collection, docs []BulkDocument, ownerField, owner string) (BulkResult, error) {
+
+	result := BulkResult{}
+	if len(docs) == 0 {
+		return result, nil
+	}
+
+	owners, err := foreignOwners(ctx, collection, docs, ownerField, owner)
+	if err != nil {
+		return result, err
+	}
+
+	errs := map[int]string{}
+	writes := make([]BulkDocument, 0, len(docs))
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+
+		key, err := doc.Key()
+		if err != nil {
+			return result, err
+		}
+
+		if other, ok := owners[key]; ok {
+			errs[doc.Index] = fmt.Sprintf("document %d: key matches the document of %s", doc.Index, other)
+			continue
+		}
+
+		writes = append(writes, doc)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(ownedFilter(doc.Filter, ownerField, owner)).
+			SetUpdate(bson.D{{Key: "$set", Value: doc.Document}}).
+			SetUpsert(true))
+	}
+
+	var res *mongo.BulkWriteResult
+	if len(models) > 0 {
+		res, err = collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	}
+	if err != nil {
+
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+			return result, err
+		}
+
+		// the key of a document of another owner is matched by a unique index
+		for _, writeErr := range bulkErr.WriteErrors {
+			doc := writes[writeErr.Index]
+			msg := writeErr.Message
+			if writeErr.Code == duplicateKey {
+				msg = "key matches a document of another owner: " + msg
+			}
+			errs[doc.Index] = fmt.Sprintf("document %d: %s", doc.Index, msg)
+		}
+	}
+
+	if res != nil {
+		result.Inserted = res.UpsertedCount
+		result.Updated = res.ModifiedCount
+	}
+
+	for _, doc := range docs {
+		if msg, ok := errs[doc.Index]; ok {
+			result.Errors = append(result.Errors, msg)
+		}
+	}
+	result.Failed = int64(len(result.Errors))
+
+	return result, nil
+}
+
+// foreignOwners returns the owners of the documents which match the keys of the given documents
+// and belong to another owner by the keys, these documents are never written
+func foreignOwners(ctx context.Context, collection *mongo.Collection, docs []BulkDocument, ownerField, owner string) (map[string]string, error) {
+
+	projection := bson.D{{Key: ownerField, Value: 1}}
+	paths := make([]string, 0, len(docs[0].Filter))
+	for _, e := range docs[0].Filter {
+		projection = append(projection, bson.E{Key: e.Key, Value: 1})
+		paths = append(paths, e.Key)
+	}
+
+	cursor, err := collection.Find(ctx, foreignOwnerFilter(docs, ownerField, owner), options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	owners := map[string]string{}
+	for cursor.Next(ctx) {
+
+		filter, err := KeyFilter(cursor.Current, paths)
+		if err != nil {
+			continue
+		}
+
+		key, err := BulkDocument{Filter: filter}.Key()
+		if err != nil {
+			return nil, err
+		}
+
+		other, _ := cursor.Current.Lookup(ownerField).StringValueOK()
+		owners[key] = other
+	}
+
+	return owners, cursor.Err()
+}
+
+// ownedFilter restricts the key filter of a document to the documents
+// which have no owner yet or belong to the given owner
+func ownedFilter(filter bson.D, ownerField, owner string) bson.D {
+	owned := append(bson.D{}, filter...)
+	return append(owned, bson.E{Key: ownerField, Value: bson.D{{Key: "$in", Value: bson.A{nil, owner}}}})
+}
+
+// foreignOwnerFilter matches the documents of the keys of the given documents which belong to another owner
+func foreignOwnerFilter(docs []BulkDocument, ownerField, owner string) bson.D {
+	keys := bson.A{}
+	for _, doc := range docs {
+		keys = append(keys, doc.Filter)
+	}
+
+	return bson.D{
+		{Key: "$or", Value: keys},
+		{Key: ownerField, Value: bson.D{{Key: "$nin", Value: bson.A{nil, owner}}}},
+	}
+}
@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			data: "  \n",
+			want: nil,
+		},
+		{
+			name: "json array",
+			data: `[{"a":1}, {"b":{"c":2}}]`,
+			want: []string{`{"a":1}`, `{"b":{"c":2}}`},
+		},
+		{
+			name: "newline delimited json",
+			data: "{\"a\":1}\n{\"b\":2}\n",
+			want: []string{`{"a":1}`, `{"b":2}`},
+		},
+		{
+			name:    "invalid json array",
+			data:    `[{"a":1}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid second document",
+			data:    "{\"a\":1}\n{\"b\":",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raws, err := ParseDocuments([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDocuments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, raw := range raws {
+				got = append(got, string(raw))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDocuments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkUpsertWithoutDocuments(t *testing.T) {
+	// nothing is written, so the collection is never used
+	result, err := BulkUpsert(context.Background(), nil, nil, "_owner", "team-a/seed")
+	if err != nil {
+		t.Fatalf("BulkUpsert() error = %v", err)
+	}
+	if !reflect.DeepEqual(result, BulkResult{}) {
+		t.Errorf("BulkUpsert() = %+v, want an empty result", result)
+	}
+}
+
+func TestBulkDocumentKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b bson.D
+		want bool
+	}{
+		{
+			name: "same key",
+			a:    bson.D{{Key: "code", Value: "a"}},
+			b:    bson.D{{Key: "code", Value: "a"}},
+			want: true,
+		},
+		{
+			name: "integers of another bson type",
+			a:    bson.D{{Key: "code", Value: int32(1)}},
+			b:    bson.D{{Key: "code", Value: int64(1)}},
+			want: true,
+		},
+		{
+			name: "other value",
+			a:    bson.D{{Key: "code", Value: "a"}},
+			b:    bson.D{{Key: "code", Value: "b"}},
+			want: false,
+		},
+		{
+			name: "number and string",
+			a:    bson.D{{Key: "code", Value: int32(1)}},
+			b:    bson.D{{Key: "code", Value: "1"}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := BulkDocument{Filter: tt.a}.Key()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := BulkDocument{Filter: tt.b}.Key()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a == b; got != tt.want {
+				t.Errorf("Key() %s == %s is %v, want %v", a, b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnedFilter(t *testing.T) {
+	filter := bson.D{{Key: "code", Value: "a"}}
+
+	want := bson.D{
+		{Key: "code", Value: "a"},
+		{Key: "_owner", Value: bson.D{{Key: "$in", Value: bson.A{nil, "team-a/seed"}}}},
+	}
+	if got := ownedFilter(filter, "_owner", "team-a/seed"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ownedFilter() = %v, want %v", got, want)
+	}
+
+	// the key filter of the document is left as it is
+	if len(filter) != 1 {
+		t.Errorf("ownedFilter() changed the key filter to %v", filter)
+	}
+}
+
+func TestForeignOwnerFilter(t *testing.T) {
+	docs := []BulkDocument{
+		{Index: 1, Filter: bson.D{{Key: "code", Value: "a"}}},
+		{Index: 2, Filter: bson.D{{Key: "code", Value: "b"}}},
+	}
+
+	want := bson.D{
+		{Key: "$or", Value: bson.A{bson.D{{Key: "code", Value: "a"}}, bson.D{{Key: "code", Value: "b"}}}},
+		{Key: "_owner", Value: bson.D{{Key: "$nin", Value: bson.A{nil, "team-a/seed"}}}},
+	}
+	if got := foreignOwnerFilter(docs, "_owner", "team-a/seed"); !reflect.DeepEqual(got, want) {
+		t.Errorf("foreignOwnerFilter() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,388 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// changeStreamHistoryLost is returned when the resume token is no longer in the oplog
+	changeStreamHistoryLost = 286
+
+	// resumeTokenFlushInterval is how often the resume tokens are persisted
+	resumeTokenFlushInterval = 10 * time.Second
+
+	// changeStreamRetryDelay is the delay between change stream restarts after a failure
+	changeStreamRetryDelay = 5 * time.Second
+)
+
+var _ manager.Runnable = &ChangeStreamManager{}
+var _ manager.LeaderElectionRunnable = &ChangeStreamManager{}
+
+// ChangeEvent is a change of a document which has been made in mongodb
+type ChangeEvent struct {
+	// ConfigUID is the uid of the MongoDBConfig which is watching the document
+	ConfigUID types.UID
+
+	Database      string
+	Collection    string
+	DocumentID    primitive.ObjectID
+	OperationType string
+}
+
+// ChangeStreamOptions configures the change stream of a MongoDBConfig
+type ChangeStreamOptions struct {
+	// Collections are the names or glob patterns of the collections whose events are delivered,
+	// every collection is watched when it's empty
+	Collections []string
+
+	// ExcludedDatabases are the databases whose events are never delivered
+	ExcludedDatabases []string
+
+	// ResumeTokenDatabase and ResumeTokenCollection is where the resume token is persisted,
+	// so the change stream continues after the last seen event when it's restarted
+	ResumeTokenDatabase   string
+	ResumeTokenCollection string
+}
+
+// ChangeStreamManager runs one change stream per MongoDBConfig and delivers
+// the update, replace and delete events of documents to the handler
+type ChangeStreamManager struct {
+	log     logr.Logger
+	mu      sync.Mutex
+	streams map[types.UID]*changeStream
+
+	// handlerMu is separated from mu, so a stream which is being stopped
+	// can still deliver its last event without a deadlock
+	handlerMu sync.RWMutex
+	handler   func(context.Context, ChangeEvent)
+}
+
+type changeStream struct {
+	version string
+	client  *mongo.Client
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewChangeStreamManager returns a ChangeStreamManager without any change stream
+func NewChangeStreamManager(log logr.Logger) *ChangeStreamManager {
+	return &ChangeStreamManager{
+		log:     log,
+		streams: make(map[types.UID]*changeStream),
+	}
+}
+
+// SetHandler sets the function which receives the change events,
+// the given context is done when the change stream is stopped
+func (m *ChangeStreamManager) SetHandler(handler func(context.Context, ChangeEvent)) {
+	m.handlerMu.Lock()
+	defer m.handlerMu.Unlock()
+	m.handler = handler
+}
+
+// Ensure starts the change stream of the given MongoDBConfig uid, a running
+// change stream is restarted when the version or the client has changed
+func (m *ChangeStreamManager) Ensure(uid types.UID, version string, client *mongo.Client, opts ChangeStreamOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cs, ok := m.streams[uid]; ok {
+		if cs.version == version && cs.client == client {
+			return
+		}
+		m.stopLocked(uid)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cs := &changeStream{
+		version: version,
+		client:  client,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	m.streams[uid] = cs
+
+	go func() {
+		defer close(cs.done)
+		m.run(ctx, uid, client, opts)
+	}()
+}
+
+// Stop stops the change stream of the given MongoDBConfig uid
+func (m *ChangeStreamManager) Stop(uid types.UID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopLocked(uid)
+}
+
+func (m *ChangeStreamManager) stopLocked(uid types.UID) {
+	cs, ok := m.streams[uid]
+	if !ok {
+		return
+	}
+
+	delete(m.streams, uid)
+	cs.cancel()
+	<-cs.done
+}
+
+// Start implements manager.Runnable, it blocks until the manager
+// is stopped and then stops all of the change streams
+func (m *ChangeStreamManager) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for uid := range m.streams {
+		m.stopLocked(uid)
+	}
+
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable,
+// only the leader reconciles the events
+func (m *ChangeStreamManager) NeedLeaderElection() bool {
+	return true
+}
+
+// run watches the change stream until the context is done,
+// the change stream is restarted after failures
+func (m *ChangeStreamManager) run(ctx context.Context, uid types.UID, client *mongo.Client, opts ChangeStreamOptions) {
+	log := m.log.WithValues("mongodb-config-uid", uid)
+	tokens := client.Database(opts.ResumeTokenDatabase).Collection(opts.ResumeTokenCollection)
+
+	for {
+		err := m.watch(ctx, uid, client, tokens, opts)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Error(err, "change stream failed, restarting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(changeStreamRetryDelay):
+		}
+	}
+}
+
+func (m *ChangeStreamManager) watch(
+	ctx context.Context,
+	uid types.UID,
+	client *mongo.Client,
+	tokens *mongo.Collection,
+	opts ChangeStreamOptions,
+) error {
+
+	pipeline := changeStreamPipeline(opts)
+
+	csOpts := options.ChangeStream()
+	if token, err := loadResumeToken(ctx, tokens, uid); err != nil {
+		return err
+	} else if token != nil {
+		csOpts.SetResumeAfter(token)
+	}
+
+	stream, err := client.Watch(ctx, pipeline, csOpts)
+	if err != nil {
+
+		// the resume token is too old, start from now on
+		if isCommandError(err, changeStreamHistoryLost) {
+			m.log.Info("resume token is lost, watching from now on", "mongodb-config-uid", uid)
+			if err := deleteResumeToken(ctx, tokens, uid); err != nil {
+				return err
+			}
+		}
+
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	var (
+		lastFlush = time.Now()
+		saved     bson.Raw
+	)
+
+	// persist the last resume token, even when the context is done
+	defer func() {
+		if token := stream.ResumeToken(); !bytes.Equal(token, saved) {
+			fCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = saveResumeToken(fCtx, tokens, uid, token)
+		}
+	}()
+
+	for {
+		if stream.TryNext(ctx) {
+
+			var ev struct {
+				OperationType string `bson:"operationType"`
+				NS            struct {
+					DB   string `bson:"db"`
+					Coll string `bson:"coll"`
+				} `bson:"ns"`
+				DocumentKey struct {
+					ID interface{} `bson:"_id"`
+				} `bson:"documentKey"`
+			}
+
+			if err := stream.Decode(&ev); err != nil {
+				return err
+			}
+
+			if oid, ok := ev.DocumentKey.ID.(primitive.ObjectID); ok {
+				m.deliver(ctx, ChangeEvent{
+					ConfigUID:     uid,
+					Database:      ev.NS.DB,
+					Collection:    ev.NS.Coll,
+					DocumentID:    oid,
+					OperationType: ev.OperationType,
+				})
+			}
+		}
+
+		if err := stream.Err(); err != nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if time.Since(lastFlush) < resumeTokenFlushInterval {
+			continue
+		}
+
+		if token := stream.ResumeToken(); !bytes.Equal(token, saved) {
+			if err := saveResumeToken(ctx, tokens, uid, token); err != nil {
+				return err
+			}
+			saved = token
+		}
+		lastFlush = time.Now()
+	}
+}
+
+// changeStreamPipeline filters the events by their operation type and namespace on the server,
+// so the events of the other databases and collections are never sent to the operator
+func changeStreamPipeline(opts ChangeStreamOptions) mongo.Pipeline {
+	match := bson.D{
+		{Key: "operationType", Value: bson.M{"$in": bson.A{"update", "replace", "delete"}}},
+	}
+
+	if len(opts.ExcludedDatabases) > 0 {
+		excluded := bson.A{}
+		for _, database := range opts.ExcludedDatabases {
+			excluded = append(excluded, database)
+		}
+		match = append(match, bson.E{Key: "ns.db", Value: bson.M{"$nin": excluded}})
+	}
+
+	if len(opts.Collections) > 0 {
+		collections := bson.A{}
+		for _, pattern := range opts.Collections {
+			if strings.ContainsAny(pattern, `*?[\`) {
+				collections = append(collections, bson.M{"ns.coll": bson.M{"$regex": globRegex(pattern)}})
+			} else {
+				collections = append(collections, bson.M{"ns.coll": pattern})
+			}
+		}
+		match = append(match, bson.E{Key: "$or", Value: collections})
+	}
+
+	return mongo.Pipeline{{{Key: "$match", Value: match}}}
+}
+
+// globRegex converts a glob pattern of path.Match into an anchored regular expression
+func globRegex(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(pattern[i:]))
+				i = len(pattern)
+				continue
+			}
+			b.WriteString(pattern[i : i+end+2])
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return b.String()
+}
+
+func (m *ChangeStreamManager) deliver(ctx context.Context, ev ChangeEvent) {
+	m.handlerMu.RLock()
+	handler := m.handler
+	m.handlerMu.RUnlock()
+
+	if handler != nil {
+		handler(ctx, ev)
+	}
+}
+
+func loadResumeToken(ctx context.Context, tokens *mongo.Collection, uid types.UID) (bson.Raw, error) {
+	var doc struct {
+		Token bson.Raw `bson:"token"`
+	}
+
+	if err := tokens.FindOne(ctx, bson.M{"_id": string(uid)}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return doc.Token, nil
+}
+
+func saveResumeToken(ctx context.Context, tokens *mongo.Collection, uid types.UID, token bson.Raw) error {
+	if token == nil {
+		return nil
+	}
+
+	_, err := tokens.UpdateByID(
+		ctx,
+		string(uid),
+		bson.M{"$set": bson.M{"token": token, "updatedAt": primitive.NewDateTimeFromTime(time.Now())}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func deleteResumeToken(ctx context.Context, tokens *mongo.Collection, uid types.UID) error {
+	_, err := tokens.DeleteOne(ctx, bson.M{"_id": string(uid)})
+	return err
+}
@@ -0,0 +1,195 @@
+package mongodb
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestClient returns a client which is never connected,
+// the change streams using it fail and wait for their restart
+func newTestClient(t *testing.T) *mongo.Client {
+	t.Helper()
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestChangeStreamManagerEnsure(t *testing.T) {
+	var (
+		client = newTestClient(t)
+		other  = newTestClient(t)
+	)
+
+	tests := []struct {
+		name        string
+		version     string
+		client      *mongo.Client
+		wantRestart bool
+	}{
+		{
+			name:    "same version and client",
+			version: "1",
+			client:  client,
+		},
+		{
+			name:        "version changed",
+			version:     "2",
+			client:      client,
+			wantRestart: true,
+		},
+		{
+			name:        "client changed",
+			version:     "1",
+			client:      other,
+			wantRestart: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewChangeStreamManager(logr.Discard())
+			defer m.Stop("uid")
+
+			m.Ensure("uid", "1", client, ChangeStreamOptions{})
+			first := m.streams["uid"]
+
+			m.Ensure("uid", tt.version, tt.client, ChangeStreamOptions{})
+			second := m.streams["uid"]
+
+			if restarted := first != second; restarted != tt.wantRestart {
+				t.Errorf("Ensure() restarted = %v, want %v", restarted, tt.wantRestart)
+			}
+			if tt.wantRestart {
+				select {
+				case <-first.done:
+				default:
+					t.Errorf("Ensure() did not stop the previous change stream")
+				}
+			}
+		})
+	}
+}
+
+func TestChangeStreamManagerStop(t *testing.T) {
+	m := NewChangeStreamManager(logr.Discard())
+	m.Ensure("a", "1", newTestClient(t), ChangeStreamOptions{})
+	m.Ensure("b", "1", newTestClient(t), ChangeStreamOptions{})
+
+	cs := m.streams["a"]
+	m.Stop("a")
+
+	select {
+	case <-cs.done:
+	default:
+		t.Fatalf("Stop() returned before the change stream has stopped")
+	}
+	if _, ok := m.streams["a"]; ok {
+		t.Errorf("Stop() kept the stopped change stream")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if len(m.streams) != 0 {
+		t.Errorf("Start() kept %d change streams after the manager was stopped", len(m.streams))
+	}
+}
+
+func TestChangeStreamManagerDeliver(t *testing.T) {
+	m := NewChangeStreamManager(logr.Discard())
+
+	// events without a handler are dropped
+	m.deliver(context.Background(), ChangeEvent{ConfigUID: "uid"})
+
+	var got []types.UID
+	m.SetHandler(func(_ context.Context, ev ChangeEvent) {
+		got = append(got, ev.ConfigUID)
+	})
+	m.deliver(context.Background(), ChangeEvent{ConfigUID: "uid"})
+
+	if len(got) != 1 || got[0] != "uid" {
+		t.Errorf("deliver() delivered %v, want [uid]", got)
+	}
+}
+
+func TestChangeStreamPipeline(t *testing.T) {
+	operations := bson.E{Key: "operationType", Value: bson.M{"$in": bson.A{"update", "replace", "delete"}}}
+
+	tests := []struct {
+		name string
+		opts ChangeStreamOptions
+		want bson.D
+	}{
+		{
+			name: "every namespace",
+			want: bson.D{operations},
+		},
+		{
+			name: "excluded databases and collections",
+			opts: ChangeStreamOptions{
+				Collections:       []string{"users", "logs-*"},
+				ExcludedDatabases: []string{"tokens", "admin"},
+			},
+			want: bson.D{
+				operations,
+				{Key: "ns.db", Value: bson.M{"$nin": bson.A{"tokens", "admin"}}},
+				{Key: "$or", Value: bson.A{
+					bson.M{"ns.coll": "users"},
+					bson.M{"ns.coll": bson.M{"$regex": "^logs-[^/]*$"}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := mongo.Pipeline{{{Key: "$match", Value: tt.want}}}
+			if got := changeStreamPipeline(tt.opts); !reflect.DeepEqual(got, want) {
+				t.Errorf("changeStreamPipeline() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestGlobRegex(t *testing.T) {
+	tests := []struct {
+		pattern string
+		names   []string
+	}{
+		{pattern: "users", names: []string{"users", "users2", "xusers"}},
+		{pattern: "logs-*", names: []string{"logs-", "logs-2022", "logs", "app-logs-1"}},
+		{pattern: "user?", names: []string{"users", "user", "userss"}},
+		{pattern: "[ab]*", names: []string{"apps", "bills", "costs"}},
+		{pattern: "[^ab]*", names: []string{"apps", "costs"}},
+		{pattern: "a.b", names: []string{"a.b", "axb"}},
+		{pattern: `\*`, names: []string{"*", "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := regexp.MustCompile(globRegex(tt.pattern))
+			for _, name := range tt.names {
+				want, err := path.Match(tt.pattern, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := re.MatchString(name); got != want {
+					t.Errorf("globRegex(%q) matches %q = %v, want %v", tt.pattern, name, got, want)
+				}
+			}
+		})
+	}
+}
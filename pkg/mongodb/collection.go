@@ -0,0 +1,243 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionSpec is the desired state of a mongodb collection
+type CollectionSpec struct {
+	Name string
+
+	// Validator is the $jsonSchema document of the collection validator
+	Validator        bson.D
+	ValidationLevel  string
+	ValidationAction string
+
+	Capped bool
+	Size   int64
+	Max    int64
+
+	TimeSeries         *TimeSeriesSpec
+	ExpireAfterSeconds *int64
+
+	// ClusteredIndexName creates a clustered collection on _id when it's set
+	ClusteredIndexName string
+
+	Collation *options.Collation
+}
+
+// TimeSeriesSpec defines a time series collection
+type TimeSeriesSpec struct {
+	TimeField   string
+	MetaField   string
+	Granularity string
+}
+
+// FindCollection returns the options of the collection with the given name,
+// the second return value is false if there is no such collection
+func FindCollection(ctx context.Context, db *mongo.Database, name string) (bson.Raw, bool, error) {
+
+	cursor, err := db.ListCollections(ctx, bson.M{"name": name})
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return nil, false, cursor.Err()
+	}
+
+	opts, _ := cursor.Current.Lookup("options").DocumentOK()
+	return opts, true, nil
+}
+
+// CreateCollection creates the collection with all of the options of the spec
+func CreateCollection(ctx context.Context, db *mongo.Database, spec CollectionSpec) error {
+
+	cmd := bson.D{{Key: "create", Value: spec.Name}}
+
+	if spec.Capped {
+		cmd = append(cmd, bson.E{Key: "capped", Value: true}, bson.E{Key: "size", Value: spec.Size})
+		if spec.Max > 0 {
+			cmd = append(cmd, bson.E{Key: "max", Value: spec.Max})
+		}
+	}
+
+	if ts := spec.TimeSeries; ts != nil {
+		tsDoc := bson.D{{Key: "timeField", Value: ts.TimeField}}
+		if ts.MetaField != "" {
+			tsDoc = append(tsDoc, bson.E{Key: "metaField", Value: ts.MetaField})
+		}
+		if ts.Granularity != "" {
+			tsDoc = append(tsDoc, bson.E{Key: "granularity", Value: ts.Granularity})
+		}
+		cmd = append(cmd, bson.E{Key: "timeseries", Value: tsDoc})
+	}
+
+	if spec.ExpireAfterSeconds != nil {
+		cmd = append(cmd, bson.E{Key: "expireAfterSeconds", Value: *spec.ExpireAfterSeconds})
+	}
+
+	if spec.ClusteredIndexName != "" {
+		cmd = append(cmd, bson.E{Key: "clusteredIndex", Value: bson.D{
+			{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}},
+			{Key: "unique", Value: true},
+			{Key: "name", Value: spec.ClusteredIndexName},
+		}})
+	}
+
+	if spec.Collation != nil {
+		cmd = append(cmd, bson.E{Key: "collation", Value: spec.Collation.ToDocument()})
+	}
+
+	if spec.Validator != nil {
+		cmd = append(cmd, validatorOptions(spec)...)
+	}
+
+	return db.RunCommand(ctx, cmd).Err()
+}
+
+// ModifyCollection applies the options of the spec which can be changed
+// on an existing collection with collMod
+func ModifyCollection(ctx context.Context, db *mongo.Database, spec CollectionSpec) error {
+
+	cmd := bson.D{{Key: "collMod", Value: spec.Name}}
+
+	// time series collections don't support validators
+	if spec.TimeSeries == nil {
+		cmd = append(cmd, validatorOptions(spec)...)
+	}
+
+	if spec.ExpireAfterSeconds != nil {
+		cmd = append(cmd, bson.E{Key: "expireAfterSeconds", Value: *spec.ExpireAfterSeconds})
+	}
+
+	return db.RunCommand(ctx, cmd).Err()
+}
+
+// DropCollection drops the collection with the given name,
+// it's not an error if the collection doesn't exist
+func DropCollection(ctx context.Context, db *mongo.Database, name string) error {
+	return db.Collection(name).Drop(ctx)
+}
+
+// CollectionDiff returns the options of the live collection which differ from
+// the spec and can be changed with collMod
+func CollectionDiff(live bson.Raw, spec CollectionSpec) ([]string, error) {
+	diff := []string{}
+
+	// an empty validator is left behind when the validator is removed with collMod
+	liveValidator, hasValidator := live.Lookup("validator").DocumentOK()
+	if elems, err := liveValidator.Elements(); err != nil || len(elems) == 0 {
+		hasValidator = false
+	}
+
+	if hasValidator != (spec.Validator != nil) {
+		diff = append(diff, "validator")
+	} else if hasValidator {
+		validator, err := bson.Marshal(bson.D{{Key: "$jsonSchema", Value: spec.Validator}})
+		if err != nil {
+			return nil, err
+		}
+		if !sameDocument(liveValidator, validator) {
+			diff = append(diff, "validator")
+		}
+	}
+
+	// mongodb omits the validation level and action when they are the defaults
+	if lookupString(live, "validationLevel", "strict") != defaultString(spec.ValidationLevel, "strict") {
+		diff = append(diff, "validationLevel")
+	}
+
+	if lookupString(live, "validationAction", "error") != defaultString(spec.ValidationAction, "error") {
+		diff = append(diff, "validationAction")
+	}
+
+	if spec.ExpireAfterSeconds != nil {
+		liveTTL, ok := live.Lookup("expireAfterSeconds").AsInt64OK()
+		if !ok || liveTTL != *spec.ExpireAfterSeconds {
+			diff = append(diff, "expireAfterSeconds")
+		}
+	}
+
+	return diff, nil
+}
+
+// ImmutableCollectionDiff returns the options of the live collection which differ from
+// the spec and can only be set when the collection is created
+func ImmutableCollectionDiff(live bson.Raw, spec CollectionSpec) ([]string, error) {
+	diff := []string{}
+
+	if lookupBool(live, "capped") != spec.Capped {
+		diff = append(diff, "capped")
+	} else if spec.Capped {
+		if size, _ := live.Lookup("size").AsInt64OK(); size != spec.Size {
+			diff = append(diff, "size")
+		}
+		if max, _ := live.Lookup("max").AsInt64OK(); max != spec.Max {
+			diff = append(diff, "max")
+		}
+	}
+
+	// mongodb fills in the default granularity of a time series collection
+	liveTS, hasTS := live.Lookup("timeseries").DocumentOK()
+	if hasTS != (spec.TimeSeries != nil) {
+		diff = append(diff, "timeseries")
+	} else if hasTS {
+		ts := spec.TimeSeries
+		if lookupString(liveTS, "timeField", "") != ts.TimeField ||
+			lookupString(liveTS, "metaField", "") != ts.MetaField ||
+			(ts.Granularity != "" && lookupString(liveTS, "granularity", "seconds") != ts.Granularity) {
+			diff = append(diff, "timeseries")
+		}
+	}
+
+	liveClustered, hasClustered := live.Lookup("clusteredIndex").DocumentOK()
+	if hasClustered != (spec.ClusteredIndexName != "") ||
+		(hasClustered && lookupString(liveClustered, "name", "") != spec.ClusteredIndexName) {
+		diff = append(diff, "clusteredIndex")
+	}
+
+	if same, err := sameCollation(live, spec.Collation); err != nil {
+		return nil, err
+	} else if !same {
+		diff = append(diff, "collation")
+	}
+
+	return diff, nil
+}
+
+func validatorOptions(spec CollectionSpec) bson.D {
+	opts := bson.D{}
+
+	validator := bson.D{}
+	if spec.Validator != nil {
+		validator = bson.D{{Key: "$jsonSchema", Value: spec.Validator}}
+	}
+	opts = append(opts, bson.E{Key: "validator", Value: validator})
+
+	opts = append(opts,
+		bson.E{Key: "validationLevel", Value: defaultString(spec.ValidationLevel, "strict")},
+		bson.E{Key: "validationAction", Value: defaultString(spec.ValidationAction, "error")},
+	)
+
+	return opts
+}
+
+func lookupString(doc bson.Raw, key, def string) string {
+	if value, ok := doc.Lookup(key).StringValueOK(); ok {
+		return value
+	}
+	return def
+}
+
+func defaultString(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
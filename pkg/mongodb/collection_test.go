@@ -0,0 +1,186 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCollectionDiff(t *testing.T) {
+	ttl := int64(3600)
+	schema := bson.D{{Key: "required", Value: bson.A{"email"}}}
+
+	tests := []struct {
+		name string
+		live bson.D
+		spec CollectionSpec
+		want []string
+	}{
+		{
+			name: "defaults",
+			live: bson.D{},
+			spec: CollectionSpec{},
+			want: []string{},
+		},
+		{
+			name: "same validator",
+			live: bson.D{
+				{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: schema}}},
+				{Key: "validationLevel", Value: "moderate"},
+			},
+			spec: CollectionSpec{Validator: schema, ValidationLevel: "moderate"},
+			want: []string{},
+		},
+		{
+			name: "validator added",
+			live: bson.D{},
+			spec: CollectionSpec{Validator: schema},
+			want: []string{"validator"},
+		},
+		{
+			name: "empty validator left behind by collMod",
+			live: bson.D{{Key: "validator", Value: bson.D{}}},
+			spec: CollectionSpec{},
+			want: []string{},
+		},
+		{
+			name: "validator changed",
+			live: bson.D{{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: bson.D{{Key: "required", Value: bson.A{"name"}}}}}}},
+			spec: CollectionSpec{Validator: schema},
+			want: []string{"validator"},
+		},
+		{
+			name: "validation level and action",
+			live: bson.D{{Key: "validationAction", Value: "warn"}},
+			spec: CollectionSpec{ValidationLevel: "off"},
+			want: []string{"validationLevel", "validationAction"},
+		},
+		{
+			name: "ttl changed",
+			live: bson.D{{Key: "expireAfterSeconds", Value: int64(60)}},
+			spec: CollectionSpec{ExpireAfterSeconds: &ttl},
+			want: []string{"expireAfterSeconds"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CollectionDiff(mustMarshal(t, tt.live), tt.spec)
+			if err != nil {
+				t.Fatalf("CollectionDiff() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectionDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImmutableCollectionDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		live bson.D
+		spec CollectionSpec
+		want []string
+	}{
+		{
+			name: "defaults",
+			live: bson.D{},
+			spec: CollectionSpec{},
+			want: []string{},
+		},
+		{
+			name: "same capped collection",
+			live: bson.D{{Key: "capped", Value: true}, {Key: "size", Value: int32(4096)}, {Key: "max", Value: int64(10)}},
+			spec: CollectionSpec{Capped: true, Size: 4096, Max: 10},
+			want: []string{},
+		},
+		{
+			name: "capped collection resized",
+			live: bson.D{{Key: "capped", Value: true}, {Key: "size", Value: int32(4096)}},
+			spec: CollectionSpec{Capped: true, Size: 8192},
+			want: []string{"size"},
+		},
+		{
+			name: "not capped",
+			live: bson.D{},
+			spec: CollectionSpec{Capped: true, Size: 4096},
+			want: []string{"capped"},
+		},
+		{
+			name: "time series with default granularity",
+			live: bson.D{{Key: "timeseries", Value: bson.D{{Key: "timeField", Value: "at"}, {Key: "granularity", Value: "seconds"}}}},
+			spec: CollectionSpec{TimeSeries: &TimeSeriesSpec{TimeField: "at"}},
+			want: []string{},
+		},
+		{
+			name: "time series changed",
+			live: bson.D{{Key: "timeseries", Value: bson.D{{Key: "timeField", Value: "at"}, {Key: "granularity", Value: "seconds"}}}},
+			spec: CollectionSpec{TimeSeries: &TimeSeriesSpec{TimeField: "at", Granularity: "hours"}},
+			want: []string{"timeseries"},
+		},
+		{
+			name: "clustered index and collation added",
+			live: bson.D{},
+			spec: CollectionSpec{ClusteredIndexName: "by_id", Collation: &options.Collation{Locale: "en"}},
+			want: []string{"clusteredIndex", "collation"},
+		},
+		{
+			name: "collation with defaults filled in",
+			live: bson.D{{Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: int32(3)}}}},
+			spec: CollectionSpec{Collation: &options.Collation{Locale: "en"}},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ImmutableCollectionDiff(mustMarshal(t, tt.live), tt.spec)
+			if err != nil {
+				t.Fatalf("ImmutableCollectionDiff() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImmutableCollectionDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorOptions(t *testing.T) {
+	schema := bson.D{{Key: "required", Value: bson.A{"email"}}}
+
+	tests := []struct {
+		name string
+		spec CollectionSpec
+		want bson.D
+	}{
+		{
+			name: "validator removed",
+			spec: CollectionSpec{},
+			want: bson.D{
+				{Key: "validator", Value: bson.D{}},
+				{Key: "validationLevel", Value: "strict"},
+				{Key: "validationAction", Value: "error"},
+			},
+		},
+		{
+			name: "validator",
+			spec: CollectionSpec{Validator: schema, ValidationAction: "warn"},
+			want: bson.D{
+				{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: schema}}},
+				{Key: "validationLevel", Value: "strict"},
+				{Key: "validationAction", Value: "warn"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validatorOptions(tt.spec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validatorOptions() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,495 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NewDocument converts a raw json object into a bson document,
+// the order of the fields, nested objects and arrays are preserved
+func NewDocument(data []byte) (bson.D, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("could not decode document: %v", err)
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("document must be a json object")
+	}
+
+	obj, err := decodeObject(dec, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("document must be a single json object")
+	}
+
+	// the root is never decoded as an extended json value
+	return obj.(bson.D), nil
+}
+
+// MarshalDocument converts a raw json object into bson bytes
+func MarshalDocument(data []byte) ([]byte, error) {
+	doc, err := NewDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return bson.Marshal(doc)
+}
+
+// NewValue converts raw json into a bson value, the order of the fields
+// of objects is preserved like in NewDocument
+func NewValue(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	value, err := decodeValue(dec, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("data must be a single json value")
+	}
+
+	return value, nil
+}
+
+// MergeDocument merges the value into the document at the dot separated path, objects
+// are merged field by field and any other value replaces the current one. The value
+// must be an object when the path is empty, it's merged into the root of the document
+func MergeDocument(doc bson.D, path string, value interface{}) (bson.D, error) {
+	if path != "" {
+		return mergeAt(doc, strings.Split(path, "."), value), nil
+	}
+
+	obj, ok := value.(bson.D)
+	if !ok {
+		return nil, fmt.Errorf("data must be a json object to be merged into the document")
+	}
+
+	for _, e := range obj {
+		doc = mergeAt(doc, []string{e.Key}, e.Value)
+	}
+	return doc, nil
+}
+
+// RemoveFields returns the document without the fields of the dot separated paths
+func RemoveFields(doc bson.D, paths []string) bson.D {
+	for _, path := range paths {
+		doc = removeAt(doc, strings.Split(path, "."))
+	}
+	return doc
+}
+
+// FieldPaths returns the dot separated paths of the leaf fields of the bson document in order,
+// arrays, empty documents and any other values are leaves
+func FieldPaths(document []byte) ([]string, error) {
+	paths := []string{}
+	if err := appendFieldPaths(&paths, bson.Raw(document), ""); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// FieldDiff returns the paths whose values in the live document differ from the desired document,
+// a path whose parent in the live document is not a document is replaced by that parent,
+// since mongodb can't set a field inside of it
+func FieldDiff(live, desired bson.Raw, paths []string) []string {
+	diff := []string{}
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+
+		if parent := nonDocumentParent(live, keys); parent != "" {
+			diff = AppendPath(diff, parent)
+			continue
+		}
+
+		liveValue, err := live.LookupErr(keys...)
+		if err != nil {
+			diff = AppendPath(diff, path)
+			continue
+		}
+
+		desiredValue, err := desired.LookupErr(keys...)
+		if err != nil || !equalValues(liveValue, desiredValue) {
+			diff = AppendPath(diff, path)
+		}
+	}
+	return diff
+}
+
+// RemovedPaths returns the previous paths which are not managed anymore, the paths which are
+// inside or above a current path are left out since setting the current path replaces them
+func RemovedPaths(previous, current []string) []string {
+	removed := []string{}
+	for _, path := range previous {
+		if !overlapsPath(current, path) {
+			removed = append(removed, path)
+		}
+	}
+	return removed
+}
+
+// AppendPath appends the path unless it's already one of the paths or inside of one
+func AppendPath(paths []string, path string) []string {
+	for _, p := range paths {
+		if p == path || strings.HasPrefix(path, p+".") {
+			return paths
+		}
+	}
+	return append(paths, path)
+}
+
+// EqualDocuments reports whether the bson documents have the same fields with values of
+// the same bson types, the order of the fields is ignored and the order of array items is kept
+func EqualDocuments(a, b bson.Raw) bool {
+	aElems, err := a.Elements()
+	if err != nil {
+		return false
+	}
+
+	bElems, err := b.Elements()
+	if err != nil || len(aElems) != len(bElems) {
+		return false
+	}
+
+	values := make(map[string]bson.RawValue, len(bElems))
+	for _, elem := range bElems {
+		values[elem.Key()] = elem.Value()
+	}
+
+	for _, elem := range aElems {
+		value, ok := values[elem.Key()]
+		if !ok || !equalValues(elem.Value(), value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalValues(a, b bson.RawValue) bool {
+	if a.Type != b.Type {
+		return false
+	}
+
+	switch a.Type {
+	case bson.TypeEmbeddedDocument:
+		return EqualDocuments(a.Document(), b.Document())
+	case bson.TypeArray:
+		aValues, aErr := a.Array().Values()
+		bValues, bErr := b.Array().Values()
+		if aErr != nil || bErr != nil || len(aValues) != len(bValues) {
+			return false
+		}
+		for i := range aValues {
+			if !equalValues(aValues[i], bValues[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// doubles are compared by their bytes, so NaN is equal to itself
+	return bytes.Equal(a.Value, b.Value)
+}
+
+// NewFilter converts a raw extended json object into a mongodb query filter
+func NewFilter(data []byte) (bson.D, error) {
+	var filter bson.D
+	if err := bson.UnmarshalExtJSON(data, false, &filter); err != nil {
+		return nil, fmt.Errorf("could not decode filter: %v", err)
+	}
+	return filter, nil
+}
+
+// KeyFilter builds a filter which matches the values of the given
+// dot separated field paths of the bson document
+func KeyFilter(document []byte, paths []string) (bson.D, error) {
+	filter := bson.D{}
+	for _, path := range paths {
+
+		value, err := bson.Raw(document).LookupErr(strings.Split(path, ".")...)
+		if err != nil {
+			return nil, fmt.Errorf("key %s is not found in document", path)
+		}
+
+		filter = append(filter, bson.E{Key: path, Value: value})
+	}
+	return filter, nil
+}
+
+func appendFieldPaths(paths *[]string, doc bson.Raw, path string) error {
+	elems, err := doc.Elements()
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range elems {
+		fieldPath := joinPath(path, elem.Key())
+
+		// empty documents are leaves, so they are set as they are
+		if sub, ok := elem.Value().DocumentOK(); ok {
+			if subElems, err := sub.Elements(); err == nil && len(subElems) > 0 {
+				if err := appendFieldPaths(paths, sub, fieldPath); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		*paths = append(*paths, fieldPath)
+	}
+	return nil
+}
+
+// nonDocumentParent returns the first parent of the path which exists
+// in the document and is not a document, missing parents are created by mongodb
+func nonDocumentParent(doc bson.Raw, keys []string) string {
+	for i := 1; i < len(keys); i++ {
+		value, err := doc.LookupErr(keys[:i]...)
+		if err != nil {
+			return ""
+		}
+		if value.Type != bson.TypeEmbeddedDocument {
+			return strings.Join(keys[:i], ".")
+		}
+	}
+	return ""
+}
+
+func overlapsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path || strings.HasPrefix(path, p+".") || strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeAt(doc bson.D, keys []string, value interface{}) bson.D {
+	merged := append(bson.D{}, doc...)
+
+	for i, e := range merged {
+		if e.Key != keys[0] {
+			continue
+		}
+
+		if len(keys) > 1 {
+			child, _ := e.Value.(bson.D)
+			merged[i].Value = mergeAt(child, keys[1:], value)
+			return merged
+		}
+
+		current, isObj := e.Value.(bson.D)
+		obj, ok := value.(bson.D)
+		if !isObj || !ok {
+			merged[i].Value = value
+			return merged
+		}
+
+		for _, field := range obj {
+			current = mergeAt(current, []string{field.Key}, field.Value)
+		}
+		merged[i].Value = current
+		return merged
+	}
+
+	if len(keys) > 1 {
+		return append(merged, bson.E{Key: keys[0], Value: mergeAt(bson.D{}, keys[1:], value)})
+	}
+	return append(merged, bson.E{Key: keys[0], Value: value})
+}
+
+func removeAt(doc bson.D, keys []string) bson.D {
+	removed := bson.D{}
+	for _, e := range doc {
+
+		if e.Key != keys[0] {
+			removed = append(removed, e)
+			continue
+		}
+
+		if len(keys) == 1 {
+			continue
+		}
+
+		if child, ok := e.Value.(bson.D); ok {
+			e.Value = removeAt(child, keys[1:])
+		}
+		removed = append(removed, e)
+	}
+	return removed
+}
+
+// decodeObject decodes a json object into a bson document, a nested object of
+// extended json like {"$date": ...} or {"$oid": ...} is decoded into its bson value
+func decodeObject(dec *json.Decoder, path string) (interface{}, error) {
+	doc := bson.D{}
+	for dec.More() {
+
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("could not decode document: %v", err)
+		}
+
+		key := tok.(string)
+		if len(doc) == 0 && path != "" && strings.HasPrefix(key, "$") {
+			return decodeExtJSON(dec, path, key)
+		}
+
+		fieldPath := joinPath(path, key)
+		if err := validateKey(key, fieldPath); err != nil {
+			return nil, err
+		}
+
+		value, err := decodeValue(dec, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+
+		doc = append(doc, bson.E{Key: key, Value: value})
+	}
+
+	// consume the closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("could not decode document: %v", err)
+	}
+
+	return doc, nil
+}
+
+// decodeExtJSON decodes the rest of an object whose first key is given as an extended json
+// value, both canonical and relaxed extended json v2 are accepted
+func decodeExtJSON(dec *json.Decoder, path, key string) (interface{}, error) {
+	buf := bytes.NewBufferString(`{"v":{`)
+	for {
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode document: %v", err)
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("could not decode document: %v", err)
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+
+		if !dec.More() {
+			break
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("could not decode document: %v", err)
+		}
+
+		key = tok.(string)
+		buf.WriteByte(',')
+	}
+	buf.WriteString(`}}`)
+
+	// consume the closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("could not decode document: %v", err)
+	}
+
+	var doc bson.D
+	if err := bson.UnmarshalExtJSON(buf.Bytes(), false, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	// unknown $ keys are decoded as a plain document, they are not valid field names
+	if sub, ok := doc[0].Value.(bson.D); ok {
+		return nil, fmt.Errorf("%s: field name cannot start with '$'", joinPath(path, sub[0].Key))
+	}
+
+	return doc[0].Value, nil
+}
+
+func decodeArray(dec *json.Decoder, path string) (bson.A, error) {
+	arr := bson.A{}
+	for i := 0; dec.More(); i++ {
+
+		value, err := decodeValue(dec, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, value)
+	}
+
+	// consume the closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("could not decode document: %v", err)
+	}
+
+	return arr, nil
+}
+
+func decodeValue(dec *json.Decoder, path string) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("could not decode document: %v", err)
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			return decodeObject(dec, path)
+		}
+		return decodeArray(dec, path)
+	case json.Number:
+		// integers are stored as int64 to keep them exact,
+		// everything else falls back to a double
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q is not a valid number", path, v.String())
+		}
+		return f, nil
+	default:
+		// string, bool and null
+		return v, nil
+	}
+}
+
+func validateKey(key, path string) error {
+	if key == "" {
+		return fmt.Errorf("%s: field name cannot be empty", path)
+	}
+	if strings.HasPrefix(key, "$") {
+		return fmt.Errorf("%s: field name cannot start with '$'", path)
+	}
+	if strings.Contains(key, ".") {
+		return fmt.Errorf("%s: field name cannot contain '.'", path)
+	}
+	if strings.ContainsRune(key, 0) {
+		return fmt.Errorf("%s: field name cannot contain null character", path)
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
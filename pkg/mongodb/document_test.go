@@ -0,0 +1,529 @@
+package mongodb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewDocument(t *testing.T) {
+	oid, _ := primitive.ObjectIDFromHex("5f1d7f2e8b0e4a3b2c1d0e0f")
+
+	tests := []struct {
+		name    string
+		data    string
+		want    bson.D
+		wantErr bool
+	}{
+		{
+			name: "integers are int64",
+			data: `{"a":1,"b":-2,"c":9007199254740993}`,
+			want: bson.D{{Key: "a", Value: int64(1)}, {Key: "b", Value: int64(-2)}, {Key: "c", Value: int64(9007199254740993)}},
+		},
+		{
+			name: "other numbers are doubles",
+			data: `{"a":1.5,"b":1e3}`,
+			want: bson.D{{Key: "a", Value: 1.5}, {Key: "b", Value: float64(1000)}},
+		},
+		{
+			name: "nested objects and arrays keep their order",
+			data: `{"z":{"b":[1,"x",true,null],"a":{}}}`,
+			want: bson.D{{Key: "z", Value: bson.D{
+				{Key: "b", Value: bson.A{int64(1), "x", true, nil}},
+				{Key: "a", Value: bson.D{}},
+			}}},
+		},
+		{
+			name: "relaxed extended json",
+			data: `{"d":{"$date":"2020-01-01T00:00:00Z"},"id":{"$oid":"5f1d7f2e8b0e4a3b2c1d0e0f"}}`,
+			want: bson.D{
+				{Key: "d", Value: primitive.NewDateTimeFromTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+				{Key: "id", Value: oid},
+			},
+		},
+		{
+			name: "canonical extended json",
+			data: `{"i":{"$numberInt":"5"},"l":{"$numberLong":"6"},"dec":{"$numberDecimal":"1.10"}}`,
+			want: bson.D{
+				{Key: "i", Value: int32(5)},
+				{Key: "l", Value: int64(6)},
+				{Key: "dec", Value: mustDecimal(t, "1.10")},
+			},
+		},
+		{
+			name: "extended json in arrays",
+			data: `{"a":[{"$numberInt":"1"}]}`,
+			want: bson.D{{Key: "a", Value: bson.A{int32(1)}}},
+		},
+		{
+			name:    "not an object",
+			data:    `[1,2]`,
+			wantErr: true,
+		},
+		{
+			name:    "several objects",
+			data:    `{"a":1} {"b":2}`,
+			wantErr: true,
+		},
+		{
+			name:    "root $ key",
+			data:    `{"$set":1}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown $ key",
+			data:    `{"a":{"$foo":1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "dotted key",
+			data:    `{"a.b":1}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			data:    `{"":1}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid extended json",
+			data:    `{"d":{"$date":"yesterday"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDocument([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewDocument() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualDocuments(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b bson.D
+		want bool
+	}{
+		{
+			name: "field order is ignored",
+			a:    bson.D{{Key: "a", Value: int64(1)}, {Key: "b", Value: bson.D{{Key: "c", Value: "x"}, {Key: "d", Value: true}}}},
+			b:    bson.D{{Key: "b", Value: bson.D{{Key: "d", Value: true}, {Key: "c", Value: "x"}}}, {Key: "a", Value: int64(1)}},
+			want: true,
+		},
+		{
+			name: "array order is kept",
+			a:    bson.D{{Key: "a", Value: bson.A{int64(1), int64(2)}}},
+			b:    bson.D{{Key: "a", Value: bson.A{int64(2), int64(1)}}},
+			want: false,
+		},
+		{
+			name: "same value of another bson type",
+			a:    bson.D{{Key: "a", Value: int64(1)}},
+			b:    bson.D{{Key: "a", Value: int32(1)}},
+			want: false,
+		},
+		{
+			name: "missing field",
+			a:    bson.D{{Key: "a", Value: int64(1)}},
+			b:    bson.D{{Key: "a", Value: int64(1)}, {Key: "b", Value: int64(2)}},
+			want: false,
+		},
+		{
+			name: "NaN",
+			a:    bson.D{{Key: "a", Value: math.NaN()}},
+			b:    bson.D{{Key: "a", Value: math.NaN()}},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualDocuments(mustMarshal(t, tt.a), mustMarshal(t, tt.b)); got != tt.want {
+				t.Errorf("EqualDocuments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    bson.D
+		wantErr bool
+	}{
+		{
+			name: "query operators",
+			data: `{"age":{"$gt":18},"name":"x"}`,
+			want: bson.D{{Key: "age", Value: bson.D{{Key: "$gt", Value: int32(18)}}}, {Key: "name", Value: "x"}},
+		},
+		{
+			name: "extended json",
+			data: `{"n":{"$numberLong":"5"}}`,
+			want: bson.D{{Key: "n", Value: int64(5)}},
+		},
+		{
+			name:    "not an object",
+			data:    `[1]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFilter([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFilter() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyFilter(t *testing.T) {
+	document := bson.D{
+		{Key: "name", Value: "x"},
+		{Key: "meta", Value: bson.D{{Key: "id", Value: int64(5)}}},
+	}
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "top level key",
+			paths: []string{"name"},
+			want:  `{"name":"x"}`,
+		},
+		{
+			name:  "nested key keeps its type",
+			paths: []string{"name", "meta.id"},
+			want:  `{"name":"x","meta.id":{"$numberLong":"5"}}`,
+		},
+		{
+			name:  "document key",
+			paths: []string{"meta"},
+			want:  `{"meta":{"id":{"$numberLong":"5"}}}`,
+		},
+		{
+			name:    "missing key",
+			paths:   []string{"email"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := KeyFilter(mustMarshal(t, document), tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("KeyFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := bson.MarshalExtJSON(filter, true, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("KeyFilter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeDocument(t *testing.T) {
+	doc := bson.D{
+		{Key: "a", Value: int64(1)},
+		{Key: "b", Value: bson.D{{Key: "c", Value: int64(1)}}},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		value   interface{}
+		want    bson.D
+		wantErr bool
+	}{
+		{
+			name:  "object merged into the root",
+			value: bson.D{{Key: "b", Value: bson.D{{Key: "d", Value: int64(2)}}}, {Key: "e", Value: int64(3)}},
+			want: bson.D{
+				{Key: "a", Value: int64(1)},
+				{Key: "b", Value: bson.D{{Key: "c", Value: int64(1)}, {Key: "d", Value: int64(2)}}},
+				{Key: "e", Value: int64(3)},
+			},
+		},
+		{
+			name:    "root value must be an object",
+			value:   "x",
+			wantErr: true,
+		},
+		{
+			name:  "value replaced at a path",
+			path:  "b.c",
+			value: "x",
+			want: bson.D{
+				{Key: "a", Value: int64(1)},
+				{Key: "b", Value: bson.D{{Key: "c", Value: "x"}}},
+			},
+		},
+		{
+			name:  "object replaces a value",
+			path:  "a",
+			value: bson.D{{Key: "f", Value: true}},
+			want: bson.D{
+				{Key: "a", Value: bson.D{{Key: "f", Value: true}}},
+				{Key: "b", Value: bson.D{{Key: "c", Value: int64(1)}}},
+			},
+		},
+		{
+			name:  "missing parents are created",
+			path:  "x.y",
+			value: int64(1),
+			want: bson.D{
+				{Key: "a", Value: int64(1)},
+				{Key: "b", Value: bson.D{{Key: "c", Value: int64(1)}}},
+				{Key: "x", Value: bson.D{{Key: "y", Value: int64(1)}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeDocument(doc, tt.path, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergeDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeDocument() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	// the merged document is a copy
+	if !reflect.DeepEqual(doc[1].Value, bson.D{{Key: "c", Value: int64(1)}}) {
+		t.Errorf("MergeDocument() changed the document to %#v", doc)
+	}
+}
+
+func TestRemoveFields(t *testing.T) {
+	doc := bson.D{
+		{Key: "a", Value: int64(1)},
+		{Key: "b", Value: bson.D{{Key: "c", Value: int64(1)}, {Key: "d", Value: int64(2)}}},
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  bson.D
+	}{
+		{
+			name: "no paths",
+			want: doc,
+		},
+		{
+			name:  "nested field",
+			paths: []string{"b.c"},
+			want: bson.D{
+				{Key: "a", Value: int64(1)},
+				{Key: "b", Value: bson.D{{Key: "d", Value: int64(2)}}},
+			},
+		},
+		{
+			name:  "top level field and missing path",
+			paths: []string{"a", "x.y"},
+			want: bson.D{
+				{Key: "b", Value: bson.D{{Key: "c", Value: int64(1)}, {Key: "d", Value: int64(2)}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemoveFields(doc, tt.paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemoveFields() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldPaths(t *testing.T) {
+	document := mustMarshal(t, bson.D{
+		{Key: "name", Value: "x"},
+		{Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}, {Key: "limits", Value: bson.D{{Key: "max", Value: int64(5)}}}}},
+		{Key: "empty", Value: bson.D{}},
+		{Key: "items", Value: bson.A{bson.D{{Key: "a", Value: int64(1)}}}},
+	})
+
+	want := []string{"name", "meta.tag", "meta.limits.max", "empty", "items"}
+
+	got, err := FieldPaths(document)
+	if err != nil {
+		t.Fatalf("FieldPaths() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestFieldDiff(t *testing.T) {
+	desired := mustMarshal(t, bson.D{
+		{Key: "name", Value: "x"},
+		{Key: "count", Value: int32(1)},
+		{Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}}},
+	})
+	paths := []string{"name", "count", "meta.tag"}
+
+	tests := []struct {
+		name string
+		live bson.D
+		want []string
+	}{
+		{
+			name: "same fields with unmanaged fields",
+			live: bson.D{
+				{Key: "_id", Value: int64(7)},
+				{Key: "name", Value: "x"},
+				{Key: "count", Value: int32(1)},
+				{Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}, {Key: "owner", Value: "app"}}},
+				{Key: "other", Value: true},
+			},
+			want: []string{},
+		},
+		{
+			name: "changed and missing fields",
+			live: bson.D{{Key: "name", Value: "y"}, {Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}}}},
+			want: []string{"name", "count"},
+		},
+		{
+			name: "value of another type",
+			live: bson.D{{Key: "name", Value: "x"}, {Key: "count", Value: int64(1)}, {Key: "meta", Value: bson.D{{Key: "tag", Value: "a"}}}},
+			want: []string{"count"},
+		},
+		{
+			name: "parent is not a document",
+			live: bson.D{{Key: "name", Value: "x"}, {Key: "count", Value: int32(1)}, {Key: "meta", Value: "a"}},
+			want: []string{"meta"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FieldDiff(mustMarshal(t, tt.live), desired, paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemovedPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []string
+		current  []string
+		want     []string
+	}{
+		{
+			name:    "no previous paths",
+			current: []string{"a"},
+			want:    []string{},
+		},
+		{
+			name:     "removed field",
+			previous: []string{"a", "b"},
+			current:  []string{"a"},
+			want:     []string{"b"},
+		},
+		{
+			name:     "field replaced by a document or a value",
+			previous: []string{"a", "b.c"},
+			current:  []string{"a.x", "b"},
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemovedPaths(tt.previous, tt.current); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemovedPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		path  string
+		want  []string
+	}{
+		{
+			name:  "new path",
+			paths: []string{"a"},
+			path:  "b",
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "existing path",
+			paths: []string{"a"},
+			path:  "a",
+			want:  []string{"a"},
+		},
+		{
+			name:  "path inside of a path",
+			paths: []string{"a"},
+			path:  "a.b",
+			want:  []string{"a"},
+		},
+		{
+			name:  "path with a common prefix",
+			paths: []string{"a"},
+			path:  "ab",
+			want:  []string{"a", "ab"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AppendPath(tt.paths, tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AppendPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, doc bson.D) bson.Raw {
+	t.Helper()
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func mustDecimal(t *testing.T, value string) primitive.Decimal128 {
+	t.Helper()
+	dec, err := primitive.ParseDecimal128(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dec
+}
@@ -0,0 +1,251 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// AlgorithmDeterministic always encrypts a value into the same ciphertext,
+	// so the encrypted field can still be matched by equality
+	AlgorithmDeterministic = "AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic"
+
+	// AlgorithmRandom encrypts a value into a different ciphertext every time
+	AlgorithmRandom = "AEAD_AES_256_CBC_HMAC_SHA_512-Random"
+
+	// MasterKeySize is the size of a local master key in bytes
+	MasterKeySize = 96
+
+	// localKMSProvider keeps the master key in the memory of the operator
+	localKMSProvider = "local"
+
+	// encryptedSubtype is the bson binary subtype of encrypted values
+	encryptedSubtype = 6
+
+	// the first byte of an encrypted value is the type of its blob
+	deterministicBlob = 1
+	randomBlob        = 2
+)
+
+// ErrEncryptionNotSupported is returned when the operator is built without the cse build tag
+var ErrEncryptionNotSupported = errors.New("client-side field level encryption requires the operator to be built with the cse build tag and libmongocrypt")
+
+// EncryptionSupported reports whether the operator is built with client-side field level encryption
+func EncryptionSupported() bool {
+	return encryptionSupported
+}
+
+// EncryptionOptions are the settings of explicit client-side field level encryption
+type EncryptionOptions struct {
+	// MasterKey is the local master key which encrypts the data key
+	MasterKey []byte
+
+	// KeyVaultNamespace is the database.collection of the data keys
+	KeyVaultNamespace string
+
+	// KeyAltName is the alternate name of the data key, it's created when it doesn't exist
+	KeyAltName string
+}
+
+// EncryptedField is a dot separated field path which is encrypted with the given algorithm
+type EncryptedField struct {
+	Path      string
+	Algorithm string
+}
+
+// Encrypter encrypts and decrypts the fields of documents with a single data key
+type Encrypter struct {
+	ce         *mongo.ClientEncryption
+	keyAltName string
+}
+
+// NewEncrypter returns an Encrypter which keeps its data key in the key vault of
+// the given client, the data key is created when there is none with the alternate name
+func NewEncrypter(ctx context.Context, keyVaultClient *mongo.Client, opts EncryptionOptions) (*Encrypter, error) {
+	if !encryptionSupported {
+		return nil, ErrEncryptionNotSupported
+	}
+
+	if len(opts.MasterKey) != MasterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", MasterKeySize, len(opts.MasterKey))
+	}
+
+	dbName, collName, ok := strings.Cut(opts.KeyVaultNamespace, ".")
+	if !ok || dbName == "" || collName == "" {
+		return nil, fmt.Errorf("key vault namespace %q must be in database.collection form", opts.KeyVaultNamespace)
+	}
+
+	// the unique index keeps concurrent replicas from creating several data keys with the same name
+	keyVault := keyVaultClient.Database(dbName).Collection(collName)
+	if err := CreateIndex(ctx, keyVault, IndexSpec{
+		Name:                    "keyAltNames_1",
+		Keys:                    bson.D{{Key: "keyAltNames", Value: 1}},
+		Unique:                  true,
+		PartialFilterExpression: bson.D{{Key: "keyAltNames", Value: bson.D{{Key: "$exists", Value: true}}}},
+	}); err != nil {
+		return nil, fmt.Errorf("could not create the key vault index: %v", err)
+	}
+
+	ce, err := mongo.NewClientEncryption(keyVaultClient, options.ClientEncryption().
+		SetKeyVaultNamespace(opts.KeyVaultNamespace).
+		SetKmsProviders(map[string]map[string]interface{}{
+			localKMSProvider: {"key": opts.MasterKey},
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Encrypter{ce: ce, keyAltName: opts.KeyAltName}
+	if err := e.ensureDataKey(ctx); err != nil {
+		_ = ce.Close(ctx)
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// ensureDataKey creates the data key of the Encrypter if it doesn't exist yet
+func (e *Encrypter) ensureDataKey(ctx context.Context) error {
+	err := e.ce.GetKeyByAltName(ctx, e.keyAltName).Err()
+	if err == nil {
+		return nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return fmt.Errorf("could not find data key %s: %v", e.keyAltName, err)
+	}
+
+	_, err = e.ce.CreateDataKey(ctx, localKMSProvider, options.DataKey().SetKeyAltNames([]string{e.keyAltName}))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("could not create data key %s: %v", e.keyAltName, err)
+	}
+
+	return nil
+}
+
+// Encrypt returns the bson document with the values of the given fields encrypted,
+// fields which are missing from the document are skipped
+func (e *Encrypter) Encrypt(ctx context.Context, document []byte, fields []EncryptedField) ([]byte, error) {
+	if len(fields) == 0 {
+		return document, nil
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+
+		value, err := bson.Raw(document).LookupErr(strings.Split(field.Path, ".")...)
+		if err != nil {
+			continue
+		}
+
+		encrypted, err := e.ce.Encrypt(ctx, value, options.Encrypt().
+			SetAlgorithm(field.Algorithm).
+			SetKeyAltName(e.keyAltName))
+		if err != nil {
+			return nil, fmt.Errorf("could not encrypt %s: %v", field.Path, err)
+		}
+
+		if doc, err = MergeDocument(doc, field.Path, encrypted); err != nil {
+			return nil, err
+		}
+	}
+
+	return bson.Marshal(doc)
+}
+
+// Decrypt returns the bson document with all of its encrypted values decrypted,
+// and the fields which were encrypted with the algorithms they were encrypted with
+func (e *Encrypter) Decrypt(ctx context.Context, document []byte) ([]byte, []EncryptedField, error) {
+	var doc bson.D
+	if err := bson.Unmarshal(document, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	fields := []EncryptedField{}
+	decrypted, err := e.decryptValue(ctx, doc, "", &fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := bson.Marshal(decrypted)
+	return data, fields, err
+}
+
+// ParseMasterKey returns the local master key of the raw or base64 encoded secret value
+func ParseMasterKey(data []byte) ([]byte, error) {
+	if len(data) == MasterKeySize {
+		return data, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != MasterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, raw or base64 encoded", MasterKeySize)
+	}
+
+	return key, nil
+}
+
+// Close releases the resources of the Encrypter, the key vault client stays connected
+func (e *Encrypter) Close(ctx context.Context) error {
+	return e.ce.Close(ctx)
+}
+
+func (e *Encrypter) decryptValue(ctx context.Context, value interface{}, path string, fields *[]EncryptedField) (interface{}, error) {
+	switch v := value.(type) {
+	case bson.D:
+		decrypted := make(bson.D, 0, len(v))
+		for _, elem := range v {
+			elemValue, err := e.decryptValue(ctx, elem.Value, joinPath(path, elem.Key), fields)
+			if err != nil {
+				return nil, err
+			}
+			decrypted = append(decrypted, bson.E{Key: elem.Key, Value: elemValue})
+		}
+		return decrypted, nil
+	case bson.A:
+		decrypted := make(bson.A, 0, len(v))
+		for i, item := range v {
+			itemValue, err := e.decryptValue(ctx, item, joinPath(path, strconv.Itoa(i)), fields)
+			if err != nil {
+				return nil, err
+			}
+			decrypted = append(decrypted, itemValue)
+		}
+		return decrypted, nil
+	case primitive.Binary:
+		if v.Subtype != encryptedSubtype || len(v.Data) == 0 {
+			return v, nil
+		}
+
+		plain, err := e.ce.Decrypt(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt %s: %v", path, err)
+		}
+
+		*fields = append(*fields, EncryptedField{Path: path, Algorithm: blobAlgorithm(v.Data[0])})
+		return plain, nil
+	}
+	return value, nil
+}
+
+func blobAlgorithm(blob byte) string {
+	switch blob {
+	case deterministicBlob:
+		return AlgorithmDeterministic
+	case randomBlob:
+		return AlgorithmRandom
+	}
+	return ""
+}
@@ -0,0 +1,6 @@
+//go:build cse
+
+package mongodb
+
+// encryptionSupported is true when the operator is built with libmongocrypt
+const encryptionSupported = true
@@ -0,0 +1,7 @@
+//go:build !cse
+
+package mongodb
+
+// encryptionSupported is false without the cse build tag, the driver
+// panics when client-side encryption is used without libmongocrypt
+const encryptionSupported = false
@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestParseMasterKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, MasterKeySize)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name: "raw key",
+			data: key,
+		},
+		{
+			name: "base64 key with a trailing newline",
+			data: []byte(base64.StdEncoding.EncodeToString(key) + "\n"),
+		},
+		{
+			name:    "short key",
+			data:    []byte(base64.StdEncoding.EncodeToString(key[:32])),
+			wantErr: true,
+		},
+		{
+			name:    "invalid base64",
+			data:    []byte("not a key"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMasterKey(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMasterKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, key) {
+				t.Errorf("ParseMasterKey() = %x, want %x", got, key)
+			}
+		})
+	}
+}
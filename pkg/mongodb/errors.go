@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+const (
+	// authenticationFailed is returned when the server rejects the credentials
+	authenticationFailed = 18
+
+	// duplicateKey is returned when a write violates a unique index
+	duplicateKey = 11000
+)
+
+// IsHandshakeError reports whether the mongodb server was reachable but the tls
+// handshake has failed, other errors mean that the server could not be reached at all
+func IsHandshakeError(err error) bool {
+	return isServerError(err, isHandshakeError)
+}
+
+// IsAuthError reports whether the mongodb server was reachable but it has rejected the credentials
+func IsAuthError(err error) bool {
+	return isServerError(err, isAuthError)
+}
+
+// isServerError reports whether the error or the last heartbeat error of one of the servers matches
+func isServerError(err error, match func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if match(err) {
+		return true
+	}
+
+	// the server selection error keeps the last heartbeat error of each server
+	var sse topology.ServerSelectionError
+	if errors.As(err, &sse) {
+		for _, server := range sse.Desc.Servers {
+			if server.LastError != nil && match(server.LastError) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isHandshakeError(err error) bool {
+	var (
+		recordHeaderErr tls.RecordHeaderError
+		unknownAuthErr  x509.UnknownAuthorityError
+		hostnameErr     x509.HostnameError
+		certInvalidErr  x509.CertificateInvalidError
+		opErr           *net.OpError
+	)
+
+	switch {
+	case errors.As(err, &recordHeaderErr),
+		errors.As(err, &unknownAuthErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &certInvalidErr):
+		return true
+	}
+
+	// tls alerts are not exported, they are returned as remote and local errors of the connection
+	return errors.As(err, &opErr) && (opErr.Op == "remote error" || opErr.Op == "local error")
+}
+
+func isAuthError(err error) bool {
+	var (
+		authErr   *auth.Error
+		driverErr driver.Error
+		cmdErr    mongo.CommandError
+	)
+
+	return errors.As(err, &authErr) ||
+		(errors.As(err, &driverErr) && driverErr.Code == authenticationFailed) ||
+		(errors.As(err, &cmdErr) && cmdErr.HasErrorCode(authenticationFailed))
+}
@@ -0,0 +1,149 @@
+package mongodb
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/address"
+	"go.mongodb.org/mongo-driver/mongo/description"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+func TestIsHandshakeError(t *testing.T) {
+	var (
+		refused  = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		tlsAlert = &net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")}
+		unknown  = x509.UnknownAuthorityError{}
+	)
+
+	serverSelection := func(lastErr error) error {
+		return topology.ServerSelectionError{
+			Wrapped: errors.New("context deadline exceeded"),
+			Desc: description.Topology{Servers: []description.Server{
+				{Addr: address.Address("host:27017"), LastError: lastErr},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "connection refused",
+			err:  refused,
+			want: false,
+		},
+		{
+			name: "unknown certificate authority",
+			err:  fmt.Errorf("could not connect to mongodb: %w", unknown),
+			want: true,
+		},
+		{
+			name: "tls alert",
+			err:  tlsAlert,
+			want: true,
+		},
+		{
+			name: "authentication failed",
+			err:  fmt.Errorf("could not connect to mongodb: %w", &auth.Error{}),
+			want: false,
+		},
+		{
+			name: "heartbeat tls error",
+			err:  fmt.Errorf("could not ping mongodb: %w", serverSelection(unknown)),
+			want: true,
+		},
+		{
+			name: "heartbeat connection refused",
+			err:  serverSelection(refused),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsHandshakeError(tt.err); got != tt.want {
+				t.Errorf("IsHandshakeError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	serverSelection := func(lastErr error) error {
+		return topology.ServerSelectionError{
+			Wrapped: errors.New("context deadline exceeded"),
+			Desc: description.Topology{Servers: []description.Server{
+				{Addr: address.Address("host:27017"), LastError: lastErr},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "message mentioning auth",
+			err:  errors.New("auth error: unable to authenticate"),
+			want: false,
+		},
+		{
+			name: "driver auth error",
+			err:  fmt.Errorf("could not connect to mongodb: %w", &auth.Error{}),
+			want: true,
+		},
+		{
+			name: "authentication failed code",
+			err:  driver.Error{Code: authenticationFailed, Message: "Authentication failed."},
+			want: true,
+		},
+		{
+			name: "command error with another code",
+			err:  mongo.CommandError{Code: 13, Message: "not authorized"},
+			want: false,
+		},
+		{
+			name: "command error with authentication failed code",
+			err:  mongo.CommandError{Code: authenticationFailed, Message: "Authentication failed."},
+			want: true,
+		},
+		{
+			name: "heartbeat auth error",
+			err:  serverSelection(&auth.Error{}),
+			want: true,
+		},
+		{
+			name: "tls error",
+			err:  x509.UnknownAuthorityError{},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.want {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
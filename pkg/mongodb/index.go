@@ -0,0 +1,251 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// namespaceNotFound is returned when the collection of an index doesn't exist
+	namespaceNotFound = 26
+
+	// indexNotFound is returned when an index doesn't exist
+	indexNotFound = 27
+)
+
+// IndexSpec is the desired state of a mongodb index
+type IndexSpec struct {
+	Name                    string
+	Keys                    bson.D
+	Unique                  bool
+	Sparse                  bool
+	PartialFilterExpression bson.D
+	ExpireAfterSeconds      *int32
+	Collation               *options.Collation
+}
+
+// IndexName returns the default name mongodb gives to an index of the given keys
+func IndexName(keys bson.D) string {
+	parts := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// FindIndex returns the live index of the collection with the given name,
+// or nil if there is no such index
+func FindIndex(ctx context.Context, collection *mongo.Collection, name string) (bson.Raw, error) {
+
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		if isCommandError(err, namespaceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		if indexName, _ := cursor.Current.Lookup("name").StringValueOK(); indexName == name {
+			return cursor.Current, nil
+		}
+	}
+
+	return nil, cursor.Err()
+}
+
+// CreateIndex creates the given index on the collection
+func CreateIndex(ctx context.Context, collection *mongo.Collection, spec IndexSpec) error {
+
+	opts := options.Index().
+		SetName(spec.Name).
+		SetUnique(spec.Unique).
+		SetSparse(spec.Sparse)
+
+	if spec.PartialFilterExpression != nil {
+		opts.SetPartialFilterExpression(spec.PartialFilterExpression)
+	}
+
+	if spec.ExpireAfterSeconds != nil {
+		opts.SetExpireAfterSeconds(*spec.ExpireAfterSeconds)
+	}
+
+	if spec.Collation != nil {
+		opts.SetCollation(spec.Collation)
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: spec.Keys, Options: opts})
+	return err
+}
+
+// DropIndex drops the index of the collection with the given name,
+// it's not an error if the index doesn't exist
+func DropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	if err != nil && (isCommandError(err, indexNotFound) || isCommandError(err, namespaceNotFound)) {
+		return nil
+	}
+	return err
+}
+
+// IndexDiff returns the options of the live index which differ from the spec
+func IndexDiff(live bson.Raw, spec IndexSpec) ([]string, error) {
+	diff := []string{}
+
+	keys, err := bson.Marshal(spec.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// text indexes are stored with the internal _fts and _ftsx keys
+	liveKeys, _ := live.Lookup("key").DocumentOK()
+	if !isTextIndex(spec.Keys) && !sameDocument(liveKeys, keys) {
+		diff = append(diff, "keys")
+	}
+
+	if lookupBool(live, "unique") != spec.Unique {
+		diff = append(diff, "unique")
+	}
+
+	if lookupBool(live, "sparse") != spec.Sparse {
+		diff = append(diff, "sparse")
+	}
+
+	liveTTL, hasTTL := live.Lookup("expireAfterSeconds").AsInt64OK()
+	if hasTTL != (spec.ExpireAfterSeconds != nil) ||
+		(hasTTL && liveTTL != int64(*spec.ExpireAfterSeconds)) {
+		diff = append(diff, "expireAfterSeconds")
+	}
+
+	livePFE, hasPFE := live.Lookup("partialFilterExpression").DocumentOK()
+	if hasPFE != (spec.PartialFilterExpression != nil) {
+		diff = append(diff, "partialFilterExpression")
+	} else if hasPFE {
+		pfe, err := bson.Marshal(spec.PartialFilterExpression)
+		if err != nil {
+			return nil, err
+		}
+		if !sameDocument(livePFE, pfe) {
+			diff = append(diff, "partialFilterExpression")
+		}
+	}
+
+	if same, err := sameCollation(live, spec.Collation); err != nil {
+		return nil, err
+	} else if !same {
+		diff = append(diff, "collation")
+	}
+
+	return diff, nil
+}
+
+// sameCollation compares the collation of the live options with the spec, mongodb fills
+// in the defaults of a collation, so only the fields which are set in the spec are compared
+func sameCollation(live bson.Raw, collation *options.Collation) (bool, error) {
+	liveCollation, hasCollation := live.Lookup("collation").DocumentOK()
+	if hasCollation != (collation != nil) {
+		return false, nil
+	}
+
+	if !hasCollation {
+		return true, nil
+	}
+
+	elems, err := collation.ToDocument().Elements()
+	if err != nil {
+		return false, err
+	}
+
+	for _, elem := range elems {
+		if !sameValue(liveCollation.Lookup(elem.Key()), elem.Value()) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func isTextIndex(keys bson.D) bool {
+	for _, key := range keys {
+		if key.Value == "text" {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupBool(doc bson.Raw, key string) bool {
+	value, _ := doc.Lookup(key).BooleanOK()
+	return value
+}
+
+// sameDocument compares two documents field by field,
+// numbers of different bson types are equal if their values are equal
+func sameDocument(a, b bson.Raw) bool {
+	aElems, err := a.Elements()
+	if err != nil {
+		return false
+	}
+
+	bElems, err := b.Elements()
+	if err != nil {
+		return false
+	}
+
+	if len(aElems) != len(bElems) {
+		return false
+	}
+
+	for i := range aElems {
+		if aElems[i].Key() != bElems[i].Key() || !sameValue(aElems[i].Value(), bElems[i].Value()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameValue(a, b bson.RawValue) bool {
+	if af, ok := numberValue(a); ok {
+		bf, ok := numberValue(b)
+		return ok && af == bf
+	}
+
+	if a.Type != b.Type {
+		return false
+	}
+
+	switch a.Type {
+	case bson.TypeEmbeddedDocument:
+		return sameDocument(a.Document(), b.Document())
+	case bson.TypeArray:
+		return sameDocument(a.Array(), b.Array())
+	}
+
+	return bytes.Equal(a.Value, b.Value)
+}
+
+func numberValue(rv bson.RawValue) (float64, bool) {
+	switch rv.Type {
+	case bson.TypeDouble:
+		return rv.Double(), true
+	case bson.TypeInt32:
+		return float64(rv.Int32()), true
+	case bson.TypeInt64:
+		return float64(rv.Int64()), true
+	}
+	return 0, false
+}
+
+func isCommandError(err error, code int) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.HasErrorCode(code)
+}
@@ -0,0 +1,135 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestIndexName(t *testing.T) {
+	tests := []struct {
+		name string
+		keys bson.D
+		want string
+	}{
+		{
+			name: "single key",
+			keys: bson.D{{Key: "email", Value: int32(1)}},
+			want: "email_1",
+		},
+		{
+			name: "compound key",
+			keys: bson.D{{Key: "a.b", Value: int32(1)}, {Key: "c", Value: int32(-1)}},
+			want: "a.b_1_c_-1",
+		},
+		{
+			name: "text key",
+			keys: bson.D{{Key: "title", Value: "text"}},
+			want: "title_text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IndexName(tt.keys); got != tt.want {
+				t.Errorf("IndexName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexDiff(t *testing.T) {
+	ttl := int32(3600)
+	keys := bson.D{{Key: "email", Value: int32(1)}}
+
+	tests := []struct {
+		name string
+		live bson.D
+		spec IndexSpec
+		want []string
+	}{
+		{
+			name: "same index",
+			live: bson.D{{Key: "key", Value: bson.D{{Key: "email", Value: 1.0}}}, {Key: "unique", Value: true}},
+			spec: IndexSpec{Keys: keys, Unique: true},
+			want: []string{},
+		},
+		{
+			name: "different keys",
+			live: bson.D{{Key: "key", Value: bson.D{{Key: "email", Value: int32(-1)}}}},
+			spec: IndexSpec{Keys: keys},
+			want: []string{"keys"},
+		},
+		{
+			name: "text index keys are not compared",
+			live: bson.D{{Key: "key", Value: bson.D{{Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}}}},
+			spec: IndexSpec{Keys: bson.D{{Key: "title", Value: "text"}}},
+			want: []string{},
+		},
+		{
+			name: "unique and sparse",
+			live: bson.D{{Key: "key", Value: keys}, {Key: "sparse", Value: true}},
+			spec: IndexSpec{Keys: keys, Unique: true},
+			want: []string{"unique", "sparse"},
+		},
+		{
+			name: "ttl added",
+			live: bson.D{{Key: "key", Value: keys}},
+			spec: IndexSpec{Keys: keys, ExpireAfterSeconds: &ttl},
+			want: []string{"expireAfterSeconds"},
+		},
+		{
+			name: "ttl changed",
+			live: bson.D{{Key: "key", Value: keys}, {Key: "expireAfterSeconds", Value: int32(60)}},
+			spec: IndexSpec{Keys: keys, ExpireAfterSeconds: &ttl},
+			want: []string{"expireAfterSeconds"},
+		},
+		{
+			name: "partial filter expression changed",
+			live: bson.D{
+				{Key: "key", Value: keys},
+				{Key: "partialFilterExpression", Value: bson.D{{Key: "active", Value: true}}},
+			},
+			spec: IndexSpec{Keys: keys, PartialFilterExpression: bson.D{{Key: "active", Value: false}}},
+			want: []string{"partialFilterExpression"},
+		},
+		{
+			name: "collation defaults filled in by mongodb",
+			live: bson.D{
+				{Key: "key", Value: keys},
+				{Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: int32(2)}, {Key: "caseLevel", Value: false}}},
+			},
+			spec: IndexSpec{Keys: keys, Collation: &options.Collation{Locale: "en", Strength: 2}},
+			want: []string{},
+		},
+		{
+			name: "collation changed",
+			live: bson.D{
+				{Key: "key", Value: keys},
+				{Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: int32(3)}}},
+			},
+			spec: IndexSpec{Keys: keys, Collation: &options.Collation{Locale: "en", Strength: 2}},
+			want: []string{"collation"},
+		},
+		{
+			name: "collation removed",
+			live: bson.D{{Key: "key", Value: keys}, {Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}}}},
+			spec: IndexSpec{Keys: keys},
+			want: []string{"collation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IndexDiff(mustMarshal(t, tt.live), tt.spec)
+			if err != nil {
+				t.Fatalf("IndexDiff() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IndexDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
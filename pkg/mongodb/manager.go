@@ -0,0 +1,311 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// replacedClientGracePeriod is how long a replaced client stays connected,
+// so the reconcilers and change streams which are still using it can finish
+const replacedClientGracePeriod = 2 * time.Minute
+
+// errClientManagerClosed is returned by Get once the manager has been stopped
+var errClientManagerClosed = errors.New("mongodb client manager is stopped")
+
+var _ manager.Runnable = &ClientManager{}
+var _ manager.LeaderElectionRunnable = &ClientManager{}
+
+// ClientManager keeps one shared mongodb client per MongoDBConfig,
+// clients are keyed by the MongoDBConfig UID and get reconnected
+// whenever the given version of the MongoDBConfig changes
+type ClientManager struct {
+	mu      sync.Mutex
+	clients map[types.UID]*managedClient
+
+	// connecting are the connections in progress by uid and version, concurrent
+	// callers wait for the same connection instead of holding mu while connecting
+	connecting map[connectKey]*connectCall
+
+	// replaced are the clients of older versions which are closed after the grace period
+	replaced map[*managedClient]*time.Timer
+
+	// seq orders the connections, so a slow connection of an older
+	// version doesn't replace a client which has been connected after it
+	seq uint64
+
+	// closed is set once Start has disconnected the clients, no client is connected afterwards
+	closed bool
+
+	gracePeriod time.Duration
+
+	// connect connects a new client, it is replaced in tests
+	connect func(ctx context.Context, opts ConnectionOptions) (*mongo.Client, error)
+}
+
+type managedClient struct {
+	version string
+	client  *mongo.Client
+	seq     uint64
+
+	// encrypter is created on first use and shares the version of the client
+	encrypter *Encrypter
+}
+
+type connectKey struct {
+	uid     types.UID
+	version string
+}
+
+type connectCall struct {
+	done   chan struct{}
+	client *mongo.Client
+	err    error
+
+	// cancel aborts the connection and removed is set once the client has been
+	// removed or the manager stopped meanwhile, the client is not cached then
+	cancel  context.CancelFunc
+	removed bool
+}
+
+// NewClientManager returns an empty ClientManager
+func NewClientManager() *ClientManager {
+	return &ClientManager{
+		clients:     make(map[types.UID]*managedClient),
+		connecting:  make(map[connectKey]*connectCall),
+		replaced:    make(map[*managedClient]*time.Timer),
+		gracePeriod: replacedClientGracePeriod,
+		connect:     NewClientWithOptions,
+	}
+}
+
+// Get returns the cached client of the given MongoDBConfig uid, a new client
+// is connected if there is no cached one or the cached one has another version.
+// The replaced client is disconnected after a grace period, since it can still be in use
+func (m *ClientManager) Get(ctx context.Context, uid types.UID, version string, opts ConnectionOptions) (*mongo.Client, error) {
+	m.mu.Lock()
+
+	if m.closed {
+		m.mu.Unlock()
+		return nil, errClientManagerClosed
+	}
+
+	if mc, ok := m.clients[uid]; ok && mc.version == version {
+		m.mu.Unlock()
+		return mc.client, nil
+	}
+
+	key := connectKey{uid: uid, version: version}
+	if call, ok := m.connecting[key]; ok {
+		m.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.client, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	m.seq++
+	seq := m.seq
+	cCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	call := &connectCall{done: make(chan struct{}), cancel: cancel}
+	m.connecting[key] = call
+	m.mu.Unlock()
+
+	// connecting can take up to the server selection timeout,
+	// the other MongoDBConfigs are not blocked meanwhile
+	client, err := m.connect(cCtx, opts)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.connecting, key)
+
+	if err == nil && call.removed {
+		// the client has been removed while connecting, it must not be cached again
+		dCtx, dCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer dCancel()
+		_ = client.Disconnect(dCtx)
+		client, err = nil, fmt.Errorf("mongodb client of version %s has been removed while connecting", version)
+	}
+
+	call.client, call.err = client, err
+	close(call.done)
+
+	if call.err != nil {
+		return nil, call.err
+	}
+
+	mc := &managedClient{version: version, client: call.client, seq: seq}
+	if current, ok := m.clients[uid]; ok && current.seq > seq {
+		// a newer client has been connected meanwhile, this one is only used by its callers
+		m.replaceLocked(mc)
+		return call.client, nil
+	}
+
+	if current, ok := m.clients[uid]; ok {
+		m.replaceLocked(current)
+	}
+	m.clients[uid] = mc
+
+	return call.client, nil
+}
+
+// Remove disconnects and forgets the cached client of the given MongoDBConfig uid,
+// the connections in progress are aborted so they don't cache their client again
+func (m *ClientManager) Remove(ctx context.Context, uid types.UID) error {
+	m.mu.Lock()
+	for key, call := range m.connecting {
+		if key.uid == uid {
+			call.removed = true
+			call.cancel()
+		}
+	}
+
+	mc, ok := m.clients[uid]
+	delete(m.clients, uid)
+	var encrypter *Encrypter
+	if ok {
+		encrypter = mc.encrypter
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return mc.close(ctx, encrypter)
+}
+
+// Encrypter returns the cached field encrypter of the given MongoDBConfig uid, it uses the
+// cached client of the same version as its key vault client, so Get must be called first
+func (m *ClientManager) Encrypter(ctx context.Context, uid types.UID, version string, opts EncryptionOptions) (*Encrypter, error) {
+	m.mu.Lock()
+	mc, ok := m.clients[uid]
+	if !ok || mc.version != version {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("no mongodb client of version %s", version)
+	}
+	encrypter := mc.encrypter
+	m.mu.Unlock()
+
+	if encrypter != nil {
+		return encrypter, nil
+	}
+
+	// the data key is created outside of the lock, it needs a round trip to the key vault
+	encrypter, err := NewEncrypter(ctx, mc.client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the client has been removed or replaced meanwhile, the encrypter would never be closed
+	if current, ok := m.clients[uid]; !ok || current != mc {
+		_ = encrypter.Close(ctx)
+		return nil, fmt.Errorf("no mongodb client of version %s", version)
+	}
+
+	// another caller has created the encrypter meanwhile
+	if mc.encrypter != nil {
+		_ = encrypter.Close(ctx)
+		return mc.encrypter, nil
+	}
+
+	mc.encrypter = encrypter
+	return encrypter, nil
+}
+
+// Start implements manager.Runnable, it blocks until the manager is stopped and then
+// disconnects all of the cached clients, Get fails from then on
+func (m *ClientManager) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	m.mu.Lock()
+	m.closed = true
+
+	for _, call := range m.connecting {
+		call.removed = true
+		call.cancel()
+	}
+
+	closing := make(map[*managedClient]*Encrypter, len(m.clients)+len(m.replaced))
+	for uid, mc := range m.clients {
+		delete(m.clients, uid)
+		closing[mc] = mc.encrypter
+	}
+
+	for mc, timer := range m.replaced {
+		timer.Stop()
+		delete(m.replaced, mc)
+		closing[mc] = mc.encrypter
+	}
+	m.mu.Unlock()
+
+	dCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var lastErr error
+	for mc, encrypter := range closing {
+		if err := mc.close(dCtx, encrypter); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable,
+// the clients are needed by the webhooks and controllers on every replica
+func (m *ClientManager) NeedLeaderElection() bool {
+	return false
+}
+
+// replaceLocked disconnects the client after the grace period, m.mu must be held
+func (m *ClientManager) replaceLocked(mc *managedClient) {
+	m.replaced[mc] = time.AfterFunc(m.gracePeriod, func() {
+		m.mu.Lock()
+		_, ok := m.replaced[mc]
+		delete(m.replaced, mc)
+		encrypter := mc.encrypter
+		m.mu.Unlock()
+
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = mc.close(ctx, encrypter)
+	})
+}
+
+// close releases the encrypter and disconnects the client, the encrypter
+// is read by the caller while holding m.mu since Encrypter sets it
+func (mc *managedClient) close(ctx context.Context, encrypter *Encrypter) error {
+	var encErr error
+	if encrypter != nil {
+		encErr = encrypter.Close(ctx)
+	}
+
+	if err := mc.client.Disconnect(ctx); err != nil {
+		return fmt.Errorf("could not disconnect from mongodb: %v", err)
+	}
+
+	if encErr != nil {
+		return fmt.Errorf("could not close the encrypter: %v", encErr)
+	}
+
+	return nil
+}
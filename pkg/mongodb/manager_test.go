@@ -0,0 +1,219 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestClientManager returns a ClientManager which connects unconnected test clients
+// and counts the connections, release blocks the connections until it is closed
+func newTestClientManager(t *testing.T, release <-chan struct{}) (*ClientManager, *int32) {
+	t.Helper()
+
+	var connects int32
+	m := NewClientManager()
+	m.connect = func(ctx context.Context, opts ConnectionOptions) (*mongo.Client, error) {
+		atomic.AddInt32(&connects, 1)
+		if release != nil {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return newTestClient(t), nil
+	}
+	return m, &connects
+}
+
+func TestClientManagerGet(t *testing.T) {
+	const uid = types.UID("uid")
+
+	tests := []struct {
+		name         string
+		versions     []string
+		wantConnects int32
+		wantReplaced int
+	}{
+		{
+			name:         "client is cached",
+			versions:     []string{"1", "1"},
+			wantConnects: 1,
+		},
+		{
+			name:         "new version replaces the client",
+			versions:     []string{"1", "2"},
+			wantConnects: 2,
+			wantReplaced: 1,
+		},
+		{
+			name:         "every version replaces the client",
+			versions:     []string{"1", "2", "3", "3"},
+			wantConnects: 3,
+			wantReplaced: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, connects := newTestClientManager(t, nil)
+
+			var last *mongo.Client
+			for i, version := range tt.versions {
+				client, err := m.Get(context.Background(), uid, version, ConnectionOptions{})
+				if err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				if i > 0 && tt.versions[i-1] == version && client != last {
+					t.Errorf("Get() of version %s = another client, want the cached one", version)
+				}
+				if i > 0 && tt.versions[i-1] != version && client == last {
+					t.Errorf("Get() of version %s = the cached client, want a new one", version)
+				}
+				last = client
+			}
+
+			if got := atomic.LoadInt32(connects); got != tt.wantConnects {
+				t.Errorf("Get() connects = %d, want %d", got, tt.wantConnects)
+			}
+
+			m.mu.Lock()
+			replaced := len(m.replaced)
+			current := m.clients[uid]
+			m.mu.Unlock()
+
+			if replaced != tt.wantReplaced {
+				t.Errorf("Get() replaced clients = %d, want %d", replaced, tt.wantReplaced)
+			}
+			if current == nil || current.client != last {
+				t.Errorf("Get() cached client is not the last connected one")
+			}
+		})
+	}
+}
+
+func TestClientManagerGracePeriod(t *testing.T) {
+	const uid = types.UID("uid")
+
+	m, _ := newTestClientManager(t, nil)
+	m.gracePeriod = 10 * time.Millisecond
+
+	if _, err := m.Get(context.Background(), uid, "1", ConnectionOptions{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := m.Get(context.Background(), uid, "2", ConnectionOptions{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	replaced := func() int {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.replaced)
+	}
+
+	if got := replaced(); got != 1 {
+		t.Fatalf("replaced clients = %d, want 1", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for replaced() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("replaced client is not closed after the grace period")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestClientManagerConcurrentGet(t *testing.T) {
+	const (
+		uid     = types.UID("uid")
+		callers = 10
+	)
+
+	release := make(chan struct{})
+	m, connects := newTestClientManager(t, release)
+
+	var (
+		wg      sync.WaitGroup
+		clients = make([]*mongo.Client, callers)
+		errs    = make([]error, callers)
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i], errs[i] = m.Get(context.Background(), uid, "1", ConnectionOptions{})
+		}(i)
+	}
+
+	// let every caller wait for the connection before releasing it
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("Get() error = %v", errs[i])
+		}
+		if clients[i] != clients[0] {
+			t.Errorf("Get() of caller %d = another client, want the shared one", i)
+		}
+	}
+	if got := atomic.LoadInt32(connects); got != 1 {
+		t.Errorf("Get() connects = %d, want 1", got)
+	}
+}
+
+func TestClientManagerRemoveWhileConnecting(t *testing.T) {
+	const uid = types.UID("uid")
+
+	release := make(chan struct{})
+	defer close(release)
+	m, connects := newTestClientManager(t, release)
+
+	errc := make(chan error)
+	go func() {
+		_, err := m.Get(context.Background(), uid, "1", ConnectionOptions{})
+		errc <- err
+	}()
+
+	for atomic.LoadInt32(connects) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := m.Remove(context.Background(), uid); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if err := <-errc; err == nil {
+		t.Errorf("Get() error = nil, want the aborted connection")
+	}
+
+	m.mu.Lock()
+	_, ok := m.clients[uid]
+	m.mu.Unlock()
+	if ok {
+		t.Errorf("removed client is cached again")
+	}
+}
+
+func TestClientManagerGetAfterStart(t *testing.T) {
+	m, connects := newTestClientManager(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = m.Start(ctx)
+
+	if _, err := m.Get(context.Background(), "uid", "1", ConnectionOptions{}); err != errClientManagerClosed {
+		t.Errorf("Get() error = %v, want %v", err, errClientManagerClosed)
+	}
+	if got := atomic.LoadInt32(connects); got != 0 {
+		t.Errorf("Get() connects = %d, want 0", got)
+	}
+}
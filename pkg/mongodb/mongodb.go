@@ -10,13 +10,41 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
+// ConnectionOptions holds the settings of a mongodb connection
+type ConnectionOptions struct {
+	// URL is the mongodb connection url
+	URL string
+
+	// TLS enables tls for the connection when it's not nil
+	TLS *TLSOptions
+}
+
 // New mongodb connection with context given
 func NewClientWithContext(ctx context.Context, url string) (*mongo.Client, error) {
+	return NewClientWithOptions(ctx, ConnectionOptions{URL: url})
+}
+
+// New mongodb connection with context and connection options given
+func NewClientWithOptions(ctx context.Context, connOpts ConnectionOptions) (*mongo.Client, error) {
 	mCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	opts := options.Client()
-	opts.ApplyURI(url)
+	opts.ApplyURI(connOpts.URL)
+
+	if connOpts.TLS != nil {
+
+		tlsConfig, err := connOpts.TLS.Config()
+		if err != nil {
+			return nil, err
+		}
+		opts.SetTLSConfig(tlsConfig)
+
+		// authenticate with the client certificate when there are no other credentials
+		if opts.Auth == nil && connOpts.TLS.HasClientCertificate() {
+			opts.SetAuth(options.Credential{AuthMechanism: "MONGODB-X509"})
+		}
+	}
 
 	client, err := mongo.NewClient(opts)
 	if err != nil {
@@ -27,13 +55,26 @@ func NewClientWithContext(ctx context.Context, url string) (*mongo.Client, error
 		return nil, fmt.Errorf("could not connect to mongodb: %v", err)
 	}
 
-	if err := client.Ping(mCtx, readpref.Primary()); err != nil {
-		return nil, fmt.Errorf("could not ping mongodb: %v", err)
+	if err := Ping(mCtx, client); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, err
 	}
 
 	return client, nil
 }
 
+// Ping checks if the mongodb primary is reachable with the given client
+func Ping(ctx context.Context, client *mongo.Client) error {
+	pCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := client.Ping(pCtx, readpref.Primary()); err != nil {
+		return fmt.Errorf("could not ping mongodb: %w", err)
+	}
+
+	return nil
+}
+
 // New mongodb connection
 func NewClient(url string) (*mongo.Client, error) {
 	return NewClientWithContext(context.Background(), url)
@@ -0,0 +1,53 @@
+package mongodb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// TLSOptions holds the tls settings of a mongodb connection
+type TLSOptions struct {
+	// CA is the pem encoded certificate authority used to verify the server
+	CA []byte
+
+	// ClientCert and ClientKey are the pem encoded client certificate and key,
+	// they are used for x509 authentication
+	ClientCert []byte
+	ClientKey  []byte
+
+	InsecureSkipVerify bool
+	ServerName         string
+}
+
+// HasClientCertificate reports whether a client certificate is configured
+func (o *TLSOptions) HasClientCertificate() bool {
+	return len(o.ClientCert) > 0
+}
+
+// Config converts the options into a tls.Config
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	// nolint:gosec
+	cfg := &tls.Config{
+		InsecureSkipVerify: o.InsecureSkipVerify,
+		ServerName:         o.ServerName,
+	}
+
+	if len(o.CA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(o.CA) {
+			return nil, fmt.Errorf("could not parse ca certificate")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.HasClientCertificate() {
+		cert, err := tls.X509KeyPair(o.ClientCert, o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
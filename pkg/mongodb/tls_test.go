@@ -0,0 +1,42 @@
+package mongodb
+
+import "testing"
+
+func TestTLSOptionsConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    TLSOptions
+		wantErr bool
+	}{
+		{
+			name: "server name and insecure skip verify",
+			opts: TLSOptions{ServerName: "mongodb.example.com", InsecureSkipVerify: true},
+		},
+		{
+			name:    "invalid ca",
+			opts:    TLSOptions{CA: []byte("not a certificate")},
+			wantErr: true,
+		},
+		{
+			name:    "client certificate without key",
+			opts:    TLSOptions{ClientCert: []byte("not a certificate")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.Config()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Config() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.ServerName != tt.opts.ServerName || got.InsecureSkipVerify != tt.opts.InsecureSkipVerify {
+				t.Errorf("Config() = %+v, want server name %q and insecure skip verify %v",
+					got, tt.opts.ServerName, tt.opts.InsecureSkipVerify)
+			}
+		})
+	}
+}
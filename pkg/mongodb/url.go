@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BuildURL injects the given username and password into the mongodb connection url,
+// credentials which are already present in the url get replaced
+func BuildURL(mongoURL, username, password string) (string, error) {
+	schemeEnd := strings.Index(mongoURL, "://")
+	if schemeEnd < 0 {
+		return "", fmt.Errorf("mongodb connection url must have a scheme")
+	}
+
+	var (
+		scheme = mongoURL[:schemeEnd+3]
+		rest   = mongoURL[schemeEnd+3:]
+	)
+
+	// the host list ends at the first slash or question mark,
+	// it may contain several comma separated hosts
+	hostsEnd := strings.IndexAny(rest, "/?")
+	if hostsEnd < 0 {
+		hostsEnd = len(rest)
+	}
+
+	hosts := rest[:hostsEnd]
+	if at := strings.LastIndex(hosts, "@"); at >= 0 {
+		hosts = hosts[at+1:]
+	}
+
+	if username == "" {
+		return "", fmt.Errorf("mongodb username cannot be empty")
+	}
+
+	userinfo := url.UserPassword(username, password)
+	if password == "" {
+		userinfo = url.User(username)
+	}
+
+	return scheme + userinfo.String() + "@" + hosts + rest[hostsEnd:], nil
+}
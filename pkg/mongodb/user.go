@@ -0,0 +1,195 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// userNotFound is returned when a user doesn't exist
+	userNotFound = 11
+
+	// roleNotFound is returned when a role doesn't exist
+	roleNotFound = 31
+
+	// userOwnerField is the customData field of the users created by the operator,
+	// it holds the uid of the resource which owns the user
+	userOwnerField = "mongodbDataOperatorOwner"
+
+	// passwordAlphabet is used for the generated passwords, it has
+	// no characters which need escaping in a connection url
+	passwordAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// RoleRef is a role of a database
+type RoleRef struct {
+	Role string `bson:"role"`
+	DB   string `bson:"db"`
+}
+
+// Privilege allows the actions on a resource, the resource is the
+// cluster or a database and collection where empty names match all
+type Privilege struct {
+	DB         string
+	Collection string
+	Cluster    bool
+	Actions    []string
+}
+
+// UserInfo is a user which exists in mongodb
+type UserInfo struct {
+	User       string    `bson:"user"`
+	DB         string    `bson:"db"`
+	Roles      []RoleRef `bson:"roles"`
+	CustomData bson.Raw  `bson:"customData,omitempty"`
+}
+
+// Owner returns the owner which has been recorded when the user was created by the operator,
+// it's empty for the users which are not created by the operator
+func (u *UserInfo) Owner() string {
+	owner, _ := u.CustomData.Lookup(userOwnerField).StringValueOK()
+	return owner
+}
+
+// GetUser returns the user of the database, or nil if there is no such user
+func GetUser(ctx context.Context, db *mongo.Database, user string) (*UserInfo, error) {
+
+	var result struct {
+		Users []UserInfo `bson:"users"`
+	}
+
+	cmd := bson.D{{Key: "usersInfo", Value: bson.D{{Key: "user", Value: user}, {Key: "db", Value: db.Name()}}}}
+	if err := db.RunCommand(ctx, cmd).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Users) == 0 {
+		return nil, nil
+	}
+
+	return &result.Users[0], nil
+}
+
+// CreateUser creates the user in the database with the given password and roles,
+// the owner is recorded in the customData of the user
+func CreateUser(ctx context.Context, db *mongo.Database, user, password string, roles []RoleRef, owner string) error {
+	cmd := bson.D{
+		{Key: "createUser", Value: user},
+		{Key: "pwd", Value: password},
+		{Key: "roles", Value: roleRefs(roles)},
+		{Key: "customData", Value: bson.D{{Key: userOwnerField, Value: owner}}},
+	}
+	return db.RunCommand(ctx, cmd).Err()
+}
+
+// UpdateUser replaces the roles of the user, the password is only changed when it's not empty
+func UpdateUser(ctx context.Context, db *mongo.Database, user, password string, roles []RoleRef) error {
+	cmd := bson.D{
+		{Key: "updateUser", Value: user},
+		{Key: "roles", Value: roleRefs(roles)},
+	}
+	if password != "" {
+		cmd = append(cmd, bson.E{Key: "pwd", Value: password})
+	}
+	return db.RunCommand(ctx, cmd).Err()
+}
+
+// DropUser drops the user from the database, it's not an error if the user doesn't exist
+func DropUser(ctx context.Context, db *mongo.Database, user string) error {
+	err := db.RunCommand(ctx, bson.D{{Key: "dropUser", Value: user}}).Err()
+	if err != nil && isCommandError(err, userNotFound) {
+		return nil
+	}
+	return err
+}
+
+// RoleExists reports whether the role exists in the database
+func RoleExists(ctx context.Context, db *mongo.Database, role string) (bool, error) {
+
+	var result struct {
+		Roles []bson.Raw `bson:"roles"`
+	}
+
+	cmd := bson.D{{Key: "rolesInfo", Value: bson.D{{Key: "role", Value: role}, {Key: "db", Value: db.Name()}}}}
+	if err := db.RunCommand(ctx, cmd).Decode(&result); err != nil {
+		return false, err
+	}
+
+	return len(result.Roles) > 0, nil
+}
+
+// CreateRole creates the role in the database with the given privileges and inherited roles
+func CreateRole(ctx context.Context, db *mongo.Database, role string, privileges []Privilege, roles []RoleRef) error {
+	cmd := bson.D{
+		{Key: "createRole", Value: role},
+		{Key: "privileges", Value: privilegeDocs(privileges)},
+		{Key: "roles", Value: roleRefs(roles)},
+	}
+	return db.RunCommand(ctx, cmd).Err()
+}
+
+// UpdateRole replaces the privileges and inherited roles of the role
+func UpdateRole(ctx context.Context, db *mongo.Database, role string, privileges []Privilege, roles []RoleRef) error {
+	cmd := bson.D{
+		{Key: "updateRole", Value: role},
+		{Key: "privileges", Value: privilegeDocs(privileges)},
+		{Key: "roles", Value: roleRefs(roles)},
+	}
+	return db.RunCommand(ctx, cmd).Err()
+}
+
+// DropRole drops the role from the database, it's not an error if the role doesn't exist
+func DropRole(ctx context.Context, db *mongo.Database, role string) error {
+	err := db.RunCommand(ctx, bson.D{{Key: "dropRole", Value: role}}).Err()
+	if err != nil && isCommandError(err, roleNotFound) {
+		return nil
+	}
+	return err
+}
+
+// GeneratePassword returns a random password of the given length
+func GeneratePassword(length int) (string, error) {
+	max := big.NewInt(int64(len(passwordAlphabet)))
+
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+
+	return string(password), nil
+}
+
+func roleRefs(roles []RoleRef) bson.A {
+	refs := bson.A{}
+	for _, role := range roles {
+		refs = append(refs, bson.D{{Key: "role", Value: role.Role}, {Key: "db", Value: role.DB}})
+	}
+	return refs
+}
+
+func privilegeDocs(privileges []Privilege) bson.A {
+	docs := bson.A{}
+	for _, privilege := range privileges {
+
+		resource := bson.D{{Key: "db", Value: privilege.DB}, {Key: "collection", Value: privilege.Collection}}
+		if privilege.Cluster {
+			resource = bson.D{{Key: "cluster", Value: true}}
+		}
+
+		actions := bson.A{}
+		for _, action := range privilege.Actions {
+			actions = append(actions, action)
+		}
+
+		docs = append(docs, bson.D{{Key: "resource", Value: resource}, {Key: "actions", Value: actions}})
+	}
+	return docs
+}
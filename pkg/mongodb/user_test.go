@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+	}{
+		{
+			name:   "minimum length",
+			length: 16,
+		},
+		{
+			name:   "maximum length",
+			length: 128,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := GeneratePassword(tt.length)
+			if err != nil {
+				t.Fatalf("GeneratePassword() error = %v", err)
+			}
+			if len(password) != tt.length {
+				t.Errorf("GeneratePassword() length = %d, want %d", len(password), tt.length)
+			}
+			for _, c := range password {
+				if !strings.ContainsRune(passwordAlphabet, c) {
+					t.Errorf("GeneratePassword() = %s, has %q which is not in the alphabet", password, c)
+				}
+			}
+
+			other, err := GeneratePassword(tt.length)
+			if err != nil {
+				t.Fatalf("GeneratePassword() error = %v", err)
+			}
+			if other == password {
+				t.Errorf("GeneratePassword() returned the same password twice")
+			}
+		})
+	}
+}
+
+func TestPrivilegeDocs(t *testing.T) {
+	tests := []struct {
+		name       string
+		privileges []Privilege
+		want       bson.A
+	}{
+		{
+			name:       "no privileges",
+			privileges: nil,
+			want:       bson.A{},
+		},
+		{
+			name: "collection privilege",
+			privileges: []Privilege{
+				{DB: "app", Collection: "users", Actions: []string{"find", "insert"}},
+			},
+			want: bson.A{
+				bson.D{
+					{Key: "resource", Value: bson.D{{Key: "db", Value: "app"}, {Key: "collection", Value: "users"}}},
+					{Key: "actions", Value: bson.A{"find", "insert"}},
+				},
+			},
+		},
+		{
+			name: "cluster privilege",
+			privileges: []Privilege{
+				{Cluster: true, Actions: []string{"serverStatus"}},
+			},
+			want: bson.A{
+				bson.D{
+					{Key: "resource", Value: bson.D{{Key: "cluster", Value: true}}},
+					{Key: "actions", Value: bson.A{"serverStatus"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := privilegeDocs(tt.privileges); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("privilegeDocs() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserInfoOwner(t *testing.T) {
+	tests := []struct {
+		name       string
+		customData bson.Raw
+		want       string
+	}{
+		{
+			name: "no custom data",
+			want: "",
+		},
+		{
+			name:       "created by the operator",
+			customData: mustMarshal(t, bson.D{{Key: userOwnerField, Value: "user-uid"}}),
+			want:       "user-uid",
+		},
+		{
+			name:       "custom data of another application",
+			customData: mustMarshal(t, bson.D{{Key: "team", Value: "billing"}}),
+			want:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &UserInfo{User: "app", DB: "team-a", CustomData: tt.customData}
+			if got := user.Owner(); got != tt.want {
+				t.Errorf("Owner() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
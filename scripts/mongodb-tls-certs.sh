@@ -0,0 +1,34 @@
+#!/bin/sh
+set -o errexit
+
+# generates a self-signed ca, a server and a client certificate for
+# testing the tls and x509 authentication of MongoDBConfig against
+# the mongodb in mongodb-tls-docker-compose.yaml
+certs_dir="${1:-./certs}"
+mkdir -p "${certs_dir}"
+cd "${certs_dir}"
+
+openssl req -x509 -newkey rsa:2048 -nodes -days 365 \
+  -subj "/CN=mongodb-data-operator-ca" \
+  -keyout ca.key -out ca.crt
+
+openssl req -newkey rsa:2048 -nodes \
+  -subj "/O=snappcloud/CN=localhost" \
+  -keyout server.key -out server.csr
+printf "subjectAltName=DNS:localhost,DNS:mongodb,IP:127.0.0.1\n" > server.ext
+openssl x509 -req -in server.csr -CA ca.crt -CAkey ca.key -CAcreateserial \
+  -days 365 -extfile server.ext -out server.crt
+cat server.crt server.key > server.pem
+
+# the client certificate subject is the x509 username
+openssl req -newkey rsa:2048 -nodes \
+  -subj "/O=snappcloud/OU=operator/CN=mongodb-data-operator" \
+  -keyout client.key -out client.csr
+openssl x509 -req -in client.csr -CA ca.crt -CAkey ca.key -CAcreateserial \
+  -days 365 -out client.crt
+
+rm -f server.csr server.ext client.csr
+
+echo "create the kubernetes secrets with:"
+echo "  kubectl create secret generic mongodb-ca --from-file=ca.crt=${certs_dir}/ca.crt"
+echo "  kubectl create secret tls mongodb-client --cert=${certs_dir}/client.crt --key=${certs_dir}/client.key"